@@ -44,7 +44,7 @@ func (x Topic) String() string {
 	return proto.EnumName(Topic_name, int32(x))
 }
 func (Topic) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_messages_8b1affe159ebec96, []int{0}
+	return fileDescriptor_messages_f0c1917fe4631ced, []int{0}
 }
 
 type CollationBodyRequest struct {
@@ -62,7 +62,7 @@ func (m *CollationBodyRequest) Reset()         { *m = CollationBodyRequest{} }
 func (m *CollationBodyRequest) String() string { return proto.CompactTextString(m) }
 func (*CollationBodyRequest) ProtoMessage()    {}
 func (*CollationBodyRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_messages_8b1affe159ebec96, []int{0}
+	return fileDescriptor_messages_f0c1917fe4631ced, []int{0}
 }
 func (m *CollationBodyRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CollationBodyRequest.Unmarshal(m, b)
@@ -129,7 +129,7 @@ func (m *CollationBodyResponse) Reset()         { *m = CollationBodyResponse{} }
 func (m *CollationBodyResponse) String() string { return proto.CompactTextString(m) }
 func (*CollationBodyResponse) ProtoMessage()    {}
 func (*CollationBodyResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_messages_8b1affe159ebec96, []int{1}
+	return fileDescriptor_messages_f0c1917fe4631ced, []int{1}
 }
 func (m *CollationBodyResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CollationBodyResponse.Unmarshal(m, b)
@@ -180,7 +180,7 @@ func (m *Transaction) Reset()         { *m = Transaction{} }
 func (m *Transaction) String() string { return proto.CompactTextString(m) }
 func (*Transaction) ProtoMessage()    {}
 func (*Transaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_messages_8b1affe159ebec96, []int{2}
+	return fileDescriptor_messages_f0c1917fe4631ced, []int{2}
 }
 func (m *Transaction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Transaction.Unmarshal(m, b)
@@ -262,7 +262,7 @@ func (m *Signature) Reset()         { *m = Signature{} }
 func (m *Signature) String() string { return proto.CompactTextString(m) }
 func (*Signature) ProtoMessage()    {}
 func (*Signature) Descriptor() ([]byte, []int) {
-	return fileDescriptor_messages_8b1affe159ebec96, []int{3}
+	return fileDescriptor_messages_f0c1917fe4631ced, []int{3}
 }
 func (m *Signature) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Signature.Unmarshal(m, b)
@@ -303,47 +303,234 @@ func (m *Signature) GetS() uint64 {
 	return 0
 }
 
+type CollationHeader struct {
+	// shard_id and period are uint64 even though they are big integers in the
+	// sharding manager contract. Values beyond 2^64 - 1 are not expected.
+	ShardId   uint64 `protobuf:"varint,1,opt,name=shard_id,json=shardId" json:"shard_id,omitempty"`
+	ChunkRoot []byte `protobuf:"bytes,2,opt,name=chunk_root,json=chunkRoot,proto3" json:"chunk_root,omitempty"`
+	Period    uint64 `protobuf:"varint,3,opt,name=period" json:"period,omitempty"`
+	// Hex encoded address of the collation proposer.
+	ProposerAddress  string `protobuf:"bytes,4,opt,name=proposer_address,json=proposerAddress" json:"proposer_address,omitempty"`
+	Signature        []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	SkipEvmExecution bool   `protobuf:"varint,6,opt,name=skip_evm_execution,json=skipEvmExecution" json:"skip_evm_execution,omitempty"`
+	Compressed       bool   `protobuf:"varint,7,opt,name=compressed" json:"compressed,omitempty"`
+	// BLS signature aggregated from the committee members approving the header.
+	AggregateSignature []byte `protobuf:"bytes,8,opt,name=aggregate_signature,json=aggregateSignature,proto3" json:"aggregate_signature,omitempty"`
+	// Hex encoded addresses and signatures of the proposers co-signing the
+	// collation besides proposer_address, at matching indices.
+	CoProposerAddresses  []string `protobuf:"bytes,9,rep,name=co_proposer_addresses,json=coProposerAddresses" json:"co_proposer_addresses,omitempty"`
+	CoProposerSignatures [][]byte `protobuf:"bytes,10,rep,name=co_proposer_signatures,json=coProposerSignatures,proto3" json:"co_proposer_signatures,omitempty"`
+	// How the collation body is encoded: 0 for serialized transactions, 1 for
+	// raw data and 2 for snappy compressed transactions.
+	BodyContentType      uint32   `protobuf:"varint,11,opt,name=body_content_type,json=bodyContentType" json:"body_content_type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CollationHeader) Reset()         { *m = CollationHeader{} }
+func (m *CollationHeader) String() string { return proto.CompactTextString(m) }
+func (*CollationHeader) ProtoMessage()    {}
+func (*CollationHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_messages_f0c1917fe4631ced, []int{4}
+}
+func (m *CollationHeader) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CollationHeader.Unmarshal(m, b)
+}
+func (m *CollationHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CollationHeader.Marshal(b, m, deterministic)
+}
+func (dst *CollationHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CollationHeader.Merge(dst, src)
+}
+func (m *CollationHeader) XXX_Size() int {
+	return xxx_messageInfo_CollationHeader.Size(m)
+}
+func (m *CollationHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_CollationHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CollationHeader proto.InternalMessageInfo
+
+func (m *CollationHeader) GetShardId() uint64 {
+	if m != nil {
+		return m.ShardId
+	}
+	return 0
+}
+
+func (m *CollationHeader) GetChunkRoot() []byte {
+	if m != nil {
+		return m.ChunkRoot
+	}
+	return nil
+}
+
+func (m *CollationHeader) GetPeriod() uint64 {
+	if m != nil {
+		return m.Period
+	}
+	return 0
+}
+
+func (m *CollationHeader) GetProposerAddress() string {
+	if m != nil {
+		return m.ProposerAddress
+	}
+	return ""
+}
+
+func (m *CollationHeader) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *CollationHeader) GetSkipEvmExecution() bool {
+	if m != nil {
+		return m.SkipEvmExecution
+	}
+	return false
+}
+
+func (m *CollationHeader) GetCompressed() bool {
+	if m != nil {
+		return m.Compressed
+	}
+	return false
+}
+
+func (m *CollationHeader) GetAggregateSignature() []byte {
+	if m != nil {
+		return m.AggregateSignature
+	}
+	return nil
+}
+
+func (m *CollationHeader) GetCoProposerAddresses() []string {
+	if m != nil {
+		return m.CoProposerAddresses
+	}
+	return nil
+}
+
+func (m *CollationHeader) GetCoProposerSignatures() [][]byte {
+	if m != nil {
+		return m.CoProposerSignatures
+	}
+	return nil
+}
+
+func (m *CollationHeader) GetBodyContentType() uint32 {
+	if m != nil {
+		return m.BodyContentType
+	}
+	return 0
+}
+
+type Collation struct {
+	Header *CollationHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	// The serialized blob of the collation's transactions.
+	Body                 []byte   `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Collation) Reset()         { *m = Collation{} }
+func (m *Collation) String() string { return proto.CompactTextString(m) }
+func (*Collation) ProtoMessage()    {}
+func (*Collation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_messages_f0c1917fe4631ced, []int{5}
+}
+func (m *Collation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Collation.Unmarshal(m, b)
+}
+func (m *Collation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Collation.Marshal(b, m, deterministic)
+}
+func (dst *Collation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Collation.Merge(dst, src)
+}
+func (m *Collation) XXX_Size() int {
+	return xxx_messageInfo_Collation.Size(m)
+}
+func (m *Collation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Collation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Collation proto.InternalMessageInfo
+
+func (m *Collation) GetHeader() *CollationHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *Collation) GetBody() []byte {
+	if m != nil {
+		return m.Body
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*CollationBodyRequest)(nil), "ethereum.sharding.p2p.v1.CollationBodyRequest")
 	proto.RegisterType((*CollationBodyResponse)(nil), "ethereum.sharding.p2p.v1.CollationBodyResponse")
 	proto.RegisterType((*Transaction)(nil), "ethereum.sharding.p2p.v1.Transaction")
 	proto.RegisterType((*Signature)(nil), "ethereum.sharding.p2p.v1.Signature")
+	proto.RegisterType((*CollationHeader)(nil), "ethereum.sharding.p2p.v1.CollationHeader")
+	proto.RegisterType((*Collation)(nil), "ethereum.sharding.p2p.v1.Collation")
 	proto.RegisterEnum("ethereum.sharding.p2p.v1.Topic", Topic_name, Topic_value)
 }
 
 func init() {
-	proto.RegisterFile("proto/sharding/p2p/v1/messages.proto", fileDescriptor_messages_8b1affe159ebec96)
-}
-
-var fileDescriptor_messages_8b1affe159ebec96 = []byte{
-	// 459 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0xdf, 0x6e, 0xd3, 0x30,
-	0x14, 0xc6, 0xf1, 0xd6, 0x3f, 0xeb, 0x69, 0x24, 0x22, 0x6b, 0x8c, 0xc0, 0x40, 0x54, 0x85, 0x8b,
-	0xc2, 0x45, 0xaa, 0x15, 0xf1, 0x00, 0x59, 0xa9, 0xc4, 0x44, 0x95, 0x8c, 0x24, 0x13, 0xe2, 0x2a,
-	0xf2, 0x12, 0x2b, 0xb1, 0x68, 0x6d, 0x63, 0x27, 0x91, 0xf6, 0x58, 0xbc, 0x16, 0x4f, 0x81, 0x62,
-	0xa7, 0x63, 0x02, 0x71, 0x97, 0xdf, 0xf7, 0x1d, 0x39, 0xe7, 0xe7, 0x04, 0xde, 0x48, 0x25, 0x6a,
-	0xb1, 0xd4, 0x15, 0x51, 0x05, 0xe3, 0xe5, 0x52, 0xae, 0xe4, 0xb2, 0xbd, 0x58, 0xee, 0xa9, 0xd6,
-	0xa4, 0xa4, 0xda, 0x37, 0x35, 0xf6, 0x68, 0x5d, 0x51, 0x45, 0x9b, 0xbd, 0x7f, 0x18, 0xf4, 0xe5,
-	0x4a, 0xfa, 0xed, 0xc5, 0xfc, 0x27, 0x82, 0xd3, 0xb5, 0xd8, 0xed, 0x48, 0xcd, 0x04, 0xbf, 0x14,
-	0xc5, 0x5d, 0x4c, 0x7f, 0x34, 0x54, 0xd7, 0xf8, 0x19, 0x9c, 0x98, 0xd9, 0x8c, 0x15, 0x1e, 0x9a,
-	0xa1, 0xc5, 0x20, 0x1e, 0x1b, 0xbe, 0x2a, 0xf0, 0x19, 0x8c, 0x24, 0x55, 0x4c, 0x14, 0xde, 0x91,
-	0x29, 0x7a, 0xc2, 0x2f, 0x01, 0xf2, 0xaa, 0xe1, 0xdf, 0x33, 0x25, 0x44, 0xed, 0x1d, 0xcf, 0xd0,
-	0xc2, 0x89, 0x27, 0x26, 0x89, 0x85, 0xa8, 0xf1, 0x5b, 0x70, 0xa5, 0x12, 0x52, 0x68, 0xaa, 0x32,
-	0x52, 0x14, 0x8a, 0x6a, 0xed, 0x0d, 0xcc, 0xd0, 0xe3, 0x43, 0x1e, 0xd8, 0x18, 0xbf, 0x80, 0x89,
-	0x66, 0x25, 0x27, 0x75, 0xa3, 0xa8, 0x37, 0xb4, 0x07, 0xdd, 0x07, 0xf3, 0x2d, 0x3c, 0xf9, 0x6b,
-	0x65, 0x2d, 0x05, 0xd7, 0x14, 0xbf, 0x82, 0x69, 0x45, 0x49, 0x41, 0x55, 0x56, 0x11, 0x5d, 0x99,
-	0xb5, 0x9d, 0x18, 0x6c, 0xf4, 0x89, 0xe8, 0x0a, 0x63, 0x18, 0xdc, 0x8a, 0xe2, 0xce, 0xec, 0xed,
-	0xc4, 0xe6, 0x79, 0xfe, 0x0b, 0xc1, 0x34, 0x55, 0x84, 0x6b, 0x92, 0x77, 0x07, 0xe2, 0x53, 0x18,
-	0x72, 0xc1, 0x73, 0xda, 0x5b, 0x5b, 0xc0, 0xe7, 0x30, 0x29, 0x89, 0xce, 0xa4, 0x62, 0x39, 0xed,
-	0xb5, 0x4f, 0x4a, 0xa2, 0xaf, 0x3b, 0x3e, 0x94, 0x3b, 0xb6, 0x67, 0xd6, 0xdb, 0x96, 0xdb, 0x8e,
-	0x3b, 0x17, 0x45, 0x73, 0x26, 0x19, 0xe5, 0x75, 0xef, 0xfb, 0x27, 0xe8, 0xde, 0xd6, 0x92, 0x5d,
-	0x63, 0x2d, 0x07, 0xb1, 0x85, 0x2e, 0x65, 0x5c, 0x36, 0xb5, 0x37, 0x32, 0xf3, 0x16, 0x70, 0xf0,
-	0xf0, 0x56, 0xc6, 0x33, 0xb4, 0x98, 0xae, 0x5e, 0xfb, 0xff, 0xfb, 0xb2, 0x7e, 0x72, 0x18, 0x7d,
-	0x78, 0x75, 0x1f, 0x60, 0x72, 0x9f, 0x63, 0x07, 0x50, 0xdb, 0x5b, 0xa2, 0xb6, 0x23, 0xd5, 0x9b,
-	0x21, 0xd5, 0x91, 0xee, 0x55, 0x90, 0x7e, 0x97, 0xc1, 0x30, 0x15, 0x92, 0xe5, 0x78, 0x0a, 0xe3,
-	0x9b, 0xf0, 0x73, 0x18, 0x7d, 0x0d, 0xdd, 0x47, 0xf8, 0x39, 0x9c, 0xad, 0xa3, 0xed, 0x36, 0x48,
-	0xaf, 0xa2, 0x30, 0xbb, 0x8c, 0x3e, 0x7e, 0xcb, 0xe2, 0xcd, 0x97, 0x9b, 0x4d, 0x92, 0xba, 0x08,
-	0x9f, 0xc3, 0xd3, 0x7f, 0xba, 0xe4, 0x3a, 0x0a, 0x93, 0x8d, 0x7b, 0x84, 0x5d, 0x70, 0xd2, 0x38,
-	0x08, 0x93, 0x60, 0xdd, 0xd5, 0x89, 0x7b, 0x7c, 0x3b, 0x32, 0xff, 0xe9, 0xfb, 0xdf, 0x01, 0x00,
-	0x00, 0xff, 0xff, 0x1e, 0x58, 0xa9, 0xa1, 0xcf, 0x02, 0x00, 0x00,
+	proto.RegisterFile("proto/sharding/p2p/v1/messages.proto", fileDescriptor_messages_f0c1917fe4631ced)
+}
+
+var fileDescriptor_messages_f0c1917fe4631ced = []byte{
+	// 649 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xdd, 0x6e, 0x1a, 0x3d,
+	0x10, 0xfd, 0x36, 0x10, 0xc2, 0x0e, 0x7c, 0x0a, 0x75, 0x7e, 0xba, 0x6d, 0xfa, 0x83, 0x68, 0x2f,
+	0x48, 0x54, 0x81, 0x42, 0xdb, 0x07, 0x20, 0x14, 0x29, 0x51, 0x11, 0xa4, 0x86, 0xa8, 0xea, 0x95,
+	0xe5, 0xec, 0x8e, 0x16, 0x2b, 0xb0, 0x76, 0xed, 0x05, 0x95, 0xc7, 0xea, 0x23, 0xf4, 0x75, 0xfa,
+	0x14, 0xd5, 0x7a, 0x97, 0x9f, 0xd0, 0xa6, 0x52, 0xef, 0x38, 0xe7, 0x8c, 0x3d, 0x3e, 0x33, 0x67,
+	0x81, 0xd7, 0x4a, 0xcb, 0x58, 0x36, 0xcd, 0x98, 0xeb, 0x40, 0x44, 0x61, 0x53, 0xb5, 0x54, 0x73,
+	0x7e, 0xde, 0x9c, 0xa2, 0x31, 0x3c, 0x44, 0xd3, 0xb0, 0x32, 0xf1, 0x30, 0x1e, 0xa3, 0xc6, 0xd9,
+	0xb4, 0xb1, 0x2c, 0x6c, 0xa8, 0x96, 0x6a, 0xcc, 0xcf, 0x6b, 0xdf, 0x1d, 0x38, 0xec, 0xc8, 0xc9,
+	0x84, 0xc7, 0x42, 0x46, 0x17, 0x32, 0x58, 0x50, 0xfc, 0x3a, 0x43, 0x13, 0x93, 0x27, 0x50, 0xb4,
+	0xb5, 0x4c, 0x04, 0x9e, 0x53, 0x75, 0xea, 0x79, 0xba, 0x67, 0xf1, 0x55, 0x40, 0x8e, 0xa1, 0xa0,
+	0x50, 0x0b, 0x19, 0x78, 0x3b, 0x56, 0xc8, 0x10, 0x79, 0x0e, 0xe0, 0x8f, 0x67, 0xd1, 0x1d, 0xd3,
+	0x52, 0xc6, 0x5e, 0xae, 0xea, 0xd4, 0xcb, 0xd4, 0xb5, 0x0c, 0x95, 0x32, 0x26, 0xa7, 0x50, 0x51,
+	0x5a, 0x2a, 0x69, 0x50, 0x33, 0x1e, 0x04, 0x1a, 0x8d, 0xf1, 0xf2, 0xb6, 0x68, 0x7f, 0xc9, 0xb7,
+	0x53, 0x9a, 0x3c, 0x03, 0xd7, 0x88, 0x30, 0xe2, 0xf1, 0x4c, 0xa3, 0xb7, 0x9b, 0x5e, 0xb4, 0x22,
+	0x6a, 0x3d, 0x38, 0xda, 0x7a, 0xb2, 0x51, 0x32, 0x32, 0x48, 0x5e, 0x42, 0x69, 0x8c, 0x3c, 0x40,
+	0xcd, 0xc6, 0xdc, 0x8c, 0xed, 0xb3, 0xcb, 0x14, 0x52, 0xea, 0x92, 0x9b, 0x31, 0x21, 0x90, 0xbf,
+	0x95, 0xc1, 0xc2, 0xbe, 0xbb, 0x4c, 0xed, 0xef, 0xda, 0x4f, 0x07, 0x4a, 0x23, 0xcd, 0x23, 0xc3,
+	0xfd, 0xe4, 0x42, 0x72, 0x08, 0xbb, 0x91, 0x8c, 0x7c, 0xcc, 0x5c, 0xa7, 0x80, 0x9c, 0x80, 0x1b,
+	0x72, 0xc3, 0x94, 0x16, 0x3e, 0x66, 0xb6, 0x8b, 0x21, 0x37, 0xd7, 0x5a, 0xac, 0xc5, 0x89, 0x98,
+	0x8a, 0xd4, 0x77, 0x2a, 0xf6, 0x12, 0x9c, 0x78, 0xd1, 0xe8, 0x0b, 0x25, 0x30, 0x8a, 0x33, 0xbf,
+	0x6b, 0x22, 0xe9, 0x36, 0xe7, 0x93, 0x59, 0xea, 0x32, 0x4f, 0x53, 0x90, 0xb0, 0x22, 0x52, 0xb3,
+	0xd8, 0x2b, 0xd8, 0xfa, 0x14, 0x90, 0xf6, 0xe6, 0x54, 0xf6, 0xaa, 0x4e, 0xbd, 0xd4, 0x7a, 0xd5,
+	0x78, 0x68, 0xb3, 0x8d, 0xe1, 0xb2, 0x74, 0x73, 0x74, 0xef, 0xc1, 0x5d, 0xf1, 0xa4, 0x0c, 0xce,
+	0x3c, 0x73, 0xe9, 0xcc, 0x13, 0xa4, 0x33, 0x67, 0x8e, 0x4e, 0x90, 0xc9, 0xac, 0x38, 0xa6, 0xf6,
+	0x23, 0x07, 0xfb, 0xab, 0x91, 0x5f, 0xda, 0x79, 0xfe, 0x2d, 0x20, 0xf7, 0x83, 0xb0, 0xb3, 0x1d,
+	0x84, 0x75, 0x7e, 0x72, 0xf7, 0xf2, 0xf3, 0x50, 0x40, 0xdc, 0x7f, 0x0c, 0x08, 0x79, 0x03, 0xc4,
+	0xdc, 0x09, 0xc5, 0x70, 0x3e, 0x65, 0xf8, 0x0d, 0xfd, 0x59, 0xf2, 0x6c, 0x3b, 0xcb, 0x22, 0xad,
+	0x24, 0x4a, 0x77, 0x3e, 0xed, 0x2e, 0x79, 0xf2, 0x02, 0xc0, 0x97, 0x53, 0x95, 0xdc, 0x8b, 0x81,
+	0x9d, 0x6b, 0x91, 0x6e, 0x30, 0xa4, 0x09, 0x07, 0x3c, 0x0c, 0x35, 0x86, 0x3c, 0x46, 0xb6, 0xee,
+	0x5a, 0xb4, 0x5d, 0xc9, 0x4a, 0x5a, 0xcf, 0xb5, 0x05, 0x47, 0xbe, 0x64, 0xdb, 0x56, 0xd0, 0x78,
+	0x6e, 0x35, 0x57, 0x77, 0xe9, 0x81, 0x2f, 0xaf, 0xef, 0xdb, 0x41, 0x43, 0xde, 0xc1, 0xf1, 0xe6,
+	0x99, 0x55, 0x1b, 0xe3, 0x41, 0x35, 0x57, 0x2f, 0xd3, 0xc3, 0xf5, 0xa1, 0x55, 0x23, 0x43, 0xce,
+	0xe0, 0x51, 0x92, 0x61, 0xe6, 0xcb, 0x28, 0xc6, 0x28, 0x66, 0xf1, 0x42, 0xa1, 0x57, 0xaa, 0x3a,
+	0xf5, 0xff, 0xe9, 0x7e, 0x22, 0x74, 0x52, 0x7e, 0xb4, 0x50, 0x58, 0xbb, 0x05, 0x77, 0xb5, 0x42,
+	0xd2, 0x86, 0x42, 0xfa, 0x59, 0xd8, 0xd5, 0x95, 0x5a, 0xa7, 0x0f, 0xe7, 0x68, 0x6b, 0xef, 0x34,
+	0x3b, 0xf8, 0xa7, 0x6f, 0xe9, 0x8c, 0xc1, 0xee, 0x48, 0x2a, 0xe1, 0x93, 0x12, 0xec, 0xdd, 0xf4,
+	0x3f, 0xf6, 0x07, 0x9f, 0xfb, 0x95, 0xff, 0xc8, 0x53, 0x38, 0xee, 0x0c, 0x7a, 0xbd, 0xf6, 0xe8,
+	0x6a, 0xd0, 0x67, 0x17, 0x83, 0x0f, 0x5f, 0x18, 0xed, 0x7e, 0xba, 0xe9, 0x0e, 0x47, 0x15, 0x87,
+	0x9c, 0xc0, 0xe3, 0xdf, 0xb4, 0xe1, 0xf5, 0xa0, 0x3f, 0xec, 0x56, 0x76, 0x48, 0x05, 0xca, 0x23,
+	0xda, 0xee, 0x0f, 0xdb, 0x9d, 0x44, 0x1e, 0x56, 0x72, 0xb7, 0x05, 0xfb, 0x7f, 0xf6, 0xf6, 0xd7,
+	0x00, 0x9c, 0x14, 0xa3, 0xfe, 0xf7, 0x04, 0x00, 0x00,
 }
@@ -0,0 +1,262 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: proto/sharding/rpc/v1/services.proto
+
+package ethereum_sharding_rpc_v1
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import v1 "github.com/prysmaticlabs/prysm/proto/sharding/p2p/v1"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type BodyChunk struct {
+	// Offset of data in the collation body.
+	Offset uint64 `protobuf:"varint,1,opt,name=offset" json:"offset,omitempty"`
+	Data   []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// Only set on the first chunk of an upload.
+	Header               *v1.CollationHeader `protobuf:"bytes,3,opt,name=header" json:"header,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *BodyChunk) Reset()         { *m = BodyChunk{} }
+func (m *BodyChunk) String() string { return proto.CompactTextString(m) }
+func (*BodyChunk) ProtoMessage()    {}
+func (*BodyChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_services_f6cf9bbb09b98c7a, []int{0}
+}
+func (m *BodyChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BodyChunk.Unmarshal(m, b)
+}
+func (m *BodyChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BodyChunk.Marshal(b, m, deterministic)
+}
+func (dst *BodyChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BodyChunk.Merge(dst, src)
+}
+func (m *BodyChunk) XXX_Size() int {
+	return xxx_messageInfo_BodyChunk.Size(m)
+}
+func (m *BodyChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_BodyChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BodyChunk proto.InternalMessageInfo
+
+func (m *BodyChunk) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *BodyChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *BodyChunk) GetHeader() *v1.CollationHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+type UploadCollationBodyResponse struct {
+	// Chunk root of the stored body.
+	ChunkRoot            []byte   `protobuf:"bytes,1,opt,name=chunk_root,json=chunkRoot,proto3" json:"chunk_root,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UploadCollationBodyResponse) Reset()         { *m = UploadCollationBodyResponse{} }
+func (m *UploadCollationBodyResponse) String() string { return proto.CompactTextString(m) }
+func (*UploadCollationBodyResponse) ProtoMessage()    {}
+func (*UploadCollationBodyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_services_f6cf9bbb09b98c7a, []int{1}
+}
+func (m *UploadCollationBodyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UploadCollationBodyResponse.Unmarshal(m, b)
+}
+func (m *UploadCollationBodyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UploadCollationBodyResponse.Marshal(b, m, deterministic)
+}
+func (dst *UploadCollationBodyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UploadCollationBodyResponse.Merge(dst, src)
+}
+func (m *UploadCollationBodyResponse) XXX_Size() int {
+	return xxx_messageInfo_UploadCollationBodyResponse.Size(m)
+}
+func (m *UploadCollationBodyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UploadCollationBodyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UploadCollationBodyResponse proto.InternalMessageInfo
+
+func (m *UploadCollationBodyResponse) GetChunkRoot() []byte {
+	if m != nil {
+		return m.ChunkRoot
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*BodyChunk)(nil), "ethereum.sharding.rpc.v1.BodyChunk")
+	proto.RegisterType((*UploadCollationBodyResponse)(nil), "ethereum.sharding.rpc.v1.UploadCollationBodyResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for CollationService service
+
+type CollationServiceClient interface {
+	// UploadCollationBody streams a collation body in chunks, as large bodies
+	// sent as a single message can exceed the gRPC message size limit. The
+	// first chunk carries the header of the collation the body belongs to.
+	UploadCollationBody(ctx context.Context, opts ...grpc.CallOption) (CollationService_UploadCollationBodyClient, error)
+}
+
+type collationServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCollationServiceClient(cc *grpc.ClientConn) CollationServiceClient {
+	return &collationServiceClient{cc}
+}
+
+func (c *collationServiceClient) UploadCollationBody(ctx context.Context, opts ...grpc.CallOption) (CollationService_UploadCollationBodyClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_CollationService_serviceDesc.Streams[0], c.cc, "/ethereum.sharding.rpc.v1.CollationService/UploadCollationBody", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &collationServiceUploadCollationBodyClient{stream}
+	return x, nil
+}
+
+type CollationService_UploadCollationBodyClient interface {
+	Send(*BodyChunk) error
+	CloseAndRecv() (*UploadCollationBodyResponse, error)
+	grpc.ClientStream
+}
+
+type collationServiceUploadCollationBodyClient struct {
+	grpc.ClientStream
+}
+
+func (x *collationServiceUploadCollationBodyClient) Send(m *BodyChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *collationServiceUploadCollationBodyClient) CloseAndRecv() (*UploadCollationBodyResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadCollationBodyResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for CollationService service
+
+type CollationServiceServer interface {
+	// UploadCollationBody streams a collation body in chunks, as large bodies
+	// sent as a single message can exceed the gRPC message size limit. The
+	// first chunk carries the header of the collation the body belongs to.
+	UploadCollationBody(CollationService_UploadCollationBodyServer) error
+}
+
+func RegisterCollationServiceServer(s *grpc.Server, srv CollationServiceServer) {
+	s.RegisterService(&_CollationService_serviceDesc, srv)
+}
+
+func _CollationService_UploadCollationBody_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CollationServiceServer).UploadCollationBody(&collationServiceUploadCollationBodyServer{stream})
+}
+
+type CollationService_UploadCollationBodyServer interface {
+	SendAndClose(*UploadCollationBodyResponse) error
+	Recv() (*BodyChunk, error)
+	grpc.ServerStream
+}
+
+type collationServiceUploadCollationBodyServer struct {
+	grpc.ServerStream
+}
+
+func (x *collationServiceUploadCollationBodyServer) SendAndClose(m *UploadCollationBodyResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *collationServiceUploadCollationBodyServer) Recv() (*BodyChunk, error) {
+	m := new(BodyChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _CollationService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "ethereum.sharding.rpc.v1.CollationService",
+	HandlerType: (*CollationServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadCollationBody",
+			Handler:       _CollationService_UploadCollationBody_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "proto/sharding/rpc/v1/services.proto",
+}
+
+func init() {
+	proto.RegisterFile("proto/sharding/rpc/v1/services.proto", fileDescriptor_services_f6cf9bbb09b98c7a)
+}
+
+var fileDescriptor_services_f6cf9bbb09b98c7a = []byte{
+	// 259 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x90, 0xcf, 0x4b, 0xc3, 0x30,
+	0x14, 0xc7, 0x89, 0x8e, 0xc2, 0x9e, 0x3b, 0x48, 0x04, 0x29, 0x13, 0xa1, 0x4c, 0x0f, 0xf5, 0x92,
+	0xd2, 0x8a, 0x37, 0x2f, 0xba, 0x8b, 0xe7, 0x88, 0x67, 0x89, 0xcd, 0xdb, 0x5a, 0xec, 0xfa, 0x42,
+	0x92, 0x15, 0xf4, 0x1f, 0xf0, 0xdf, 0x96, 0x66, 0x5b, 0x0f, 0x6a, 0xbd, 0xe5, 0xc7, 0xf7, 0xf3,
+	0xf8, 0xbc, 0x2f, 0x5c, 0x1b, 0x4b, 0x9e, 0x32, 0x57, 0x29, 0xab, 0xeb, 0x76, 0x9d, 0x59, 0x53,
+	0x66, 0x5d, 0x9e, 0x39, 0xb4, 0x5d, 0x5d, 0xa2, 0x13, 0xe1, 0x9b, 0xc7, 0xe8, 0x2b, 0xb4, 0xb8,
+	0xdd, 0x88, 0x43, 0x50, 0x58, 0x53, 0x8a, 0x2e, 0x9f, 0xff, 0xe4, 0x4d, 0x61, 0x7a, 0x7e, 0x83,
+	0xce, 0xa9, 0xf5, 0x81, 0x5f, 0x7c, 0xc2, 0xf4, 0x91, 0xf4, 0xc7, 0xb2, 0xda, 0xb6, 0xef, 0xfc,
+	0x1c, 0x22, 0x5a, 0xad, 0x1c, 0xfa, 0x98, 0x25, 0x2c, 0x9d, 0xc8, 0xfd, 0x8d, 0x73, 0x98, 0x68,
+	0xe5, 0x55, 0x7c, 0x94, 0xb0, 0x74, 0x26, 0xc3, 0x99, 0x3f, 0x40, 0x54, 0xa1, 0xd2, 0x68, 0xe3,
+	0xe3, 0x84, 0xa5, 0x27, 0xc5, 0x8d, 0xf8, 0x6d, 0x62, 0x0a, 0x23, 0xba, 0x5c, 0x2c, 0xa9, 0x69,
+	0x94, 0xaf, 0xa9, 0x7d, 0x0a, 0x80, 0xdc, 0x83, 0x8b, 0x7b, 0xb8, 0x78, 0x31, 0x0d, 0x29, 0x3d,
+	0x04, 0x7a, 0x15, 0x89, 0xce, 0x50, 0xeb, 0x90, 0x5f, 0x02, 0x94, 0xbd, 0xd6, 0xab, 0x25, 0xda,
+	0x19, 0xcd, 0xe4, 0x34, 0xbc, 0x48, 0x22, 0x5f, 0x7c, 0x31, 0x38, 0x1d, 0xc0, 0xe7, 0x5d, 0x2b,
+	0xdc, 0xc1, 0xd9, 0x1f, 0x23, 0xf9, 0x95, 0x18, 0xab, 0x49, 0x0c, 0xdb, 0xcf, 0xef, 0xc6, 0x43,
+	0xff, 0x68, 0xa6, 0xec, 0x2d, 0x0a, 0x55, 0xde, 0x7e, 0x0f, 0x00, 0x19, 0x73, 0xa5, 0xeb, 0xb2,
+	0x01, 0x00, 0x00,
+}
@@ -4,6 +4,7 @@ package shardutil
 
 import (
 	"fmt"
+	"io"
 	"math"
 
 	"github.com/ethereum/go-ethereum/rlp"
@@ -38,6 +39,11 @@ func NewRawBlob(i interface{}, skipEvm bool) (*RawBlob, error) {
 	return &RawBlob{data: data, flags: Flags{skipEvmExecution: skipEvm}}, nil
 }
 
+// Data returns the RLP encoded data of the blob.
+func (b *RawBlob) Data() []byte {
+	return b.data
+}
+
 // ConvertFromRawBlob converts raw blob back from a byte array
 // to its interface.
 func ConvertFromRawBlob(blob *RawBlob, i interface{}) error {
@@ -196,3 +202,33 @@ func Deserialize(data []byte) ([]RawBlob, error) {
 
 	return deserializedBlob, nil
 }
+
+// ReadRawBlob reads the next blob from a stream of serialized chunks, one chunk
+// at a time, so that callers do not need to hold the whole serialized data in memory.
+// Like Deserialize, a trailing partial chunk or trailing non-terminal chunks are
+// ignored, in which case io.EOF is returned.
+func ReadRawBlob(r io.Reader) (*RawBlob, error) {
+	chunk := make([]byte, chunkSize)
+	blob := &RawBlob{}
+
+	for {
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				return nil, io.EOF
+			}
+			return nil, err
+		}
+
+		databyteLength := getDatabyteLength(chunk[0])
+
+		// if indicator is non-terminal, append the whole chunk and keep reading.
+		if databyteLength == 0 {
+			blob.data = append(blob.data, chunk[1:]...)
+			continue
+		}
+
+		blob.flags.skipEvmExecution = isSkipEvm(chunk[0])
+		blob.data = append(blob.data, chunk[1:databyteLength+1]...)
+		return blob, nil
+	}
+}
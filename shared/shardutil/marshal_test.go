@@ -1,6 +1,8 @@
 package shardutil
 
 import (
+	"bytes"
+	"io"
 	"math/rand"
 	"reflect"
 	"testing"
@@ -85,6 +87,44 @@ func TestSerializeAndDeserializeblob(t *testing.T) {
 	}
 }
 
+func TestReadRawBlob(t *testing.T) {
+	for i := 1; i < 100; i++ {
+		blob := buildRawBlob(int64(i))
+
+		drefbody := make([]*RawBlob, len(blob))
+		for s := 0; s < len(blob); s++ {
+			drefbody[s] = &(blob[s])
+		}
+
+		serializedblob, err := Serialize(drefbody)
+		if err != nil {
+			t.Fatalf("Error Serializing blob at index %d: %v", i, err)
+		}
+
+		r := bytes.NewReader(serializedblob)
+		for s := 0; s < len(blob); s++ {
+			raw, err := ReadRawBlob(r)
+			if err != nil {
+				t.Fatalf("Error reading blob %d at index %d: %v", s, i, err)
+			}
+			if !reflect.DeepEqual(blob[s], *raw) {
+				t.Errorf("Error reading blob %d at index %d, the serialized and read versions are not the same:\n\n %v \n\n %v", s, i, blob[s], *raw)
+			}
+		}
+		if _, err := ReadRawBlob(r); err != io.EOF {
+			t.Errorf("Expected io.EOF after reading all blobs at index %d, got %v", i, err)
+		}
+	}
+}
+
+func TestReadRawBlobTruncated(t *testing.T) {
+	// a non-terminal chunk followed by a partial chunk is ignored, like in Deserialize.
+	data := make([]byte, 40)
+	if _, err := ReadRawBlob(bytes.NewReader(data)); err != io.EOF {
+		t.Errorf("Expected io.EOF for truncated data, got %v", err)
+	}
+}
+
 func TestDeserializeSkipEvm(t *testing.T) {
 	data := make([]byte, 64)
 
@@ -0,0 +1,108 @@
+// Package api exposes debug HTTP endpoints to inspect the collations stored
+// by a sharding node.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/prysmaticlabs/prysm/validator/types"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "api")
+
+// collationPathPrefix is the path prefix all collation endpoints are served under.
+const collationPathPrefix = "/collation/"
+
+// collationHandler serves collations from a CollationStore.
+type collationHandler struct {
+	store types.CollationStore
+}
+
+// NewCollationHTTPHandler returns a handler serving the collations of the store
+// through the following endpoints:
+//
+//	GET /collation/{shardID}/{period}       the collation header as JSON.
+//	GET /collation/{shardID}/{period}/body  the raw collation body.
+//
+// Unknown collations are answered with 404 and malformed shardIDs or periods
+// with 400.
+func NewCollationHTTPHandler(store types.CollationStore) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(collationPathPrefix, &collationHandler{store: store})
+	return mux
+}
+
+// ServeHTTP implements http.Handler.
+func (h *collationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, collationPathPrefix), "/")
+	if len(parts) < 2 || len(parts) > 3 || (len(parts) == 3 && parts[2] != "body") {
+		http.NotFound(w, r)
+		return
+	}
+	shardID, err := parseBigInt(parts[0])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid shardID: %v", err), http.StatusBadRequest)
+		return
+	}
+	period, err := parseBigInt(parts[1])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid period: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	collation, err := h.store.Get(shardID, period)
+	if errors.Is(err, types.ErrCollationNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Errorf("Could not fetch collation for shardID %v and period %v: %v", shardID, period, err)
+		http.Error(w, "could not fetch collation", http.StatusInternalServerError)
+		return
+	}
+
+	if len(parts) == 3 {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(collation.Body())
+		return
+	}
+	encoded, err := json.Marshal(collation.Header())
+	if err != nil {
+		log.Errorf("Could not encode header of collation for shardID %v and period %v: %v", shardID, period, err)
+		http.Error(w, "could not encode collation header", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+}
+
+// maxBigIntBits is the largest bit length of the shardIDs and periods stored
+// collations are keyed by.
+const maxBigIntBits = 256
+
+// parseBigInt parses a non-negative decimal integer of at most maxBigIntBits bits.
+func parseBigInt(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a decimal integer", s)
+	}
+	if n.Sign() < 0 {
+		return nil, fmt.Errorf("%v is negative", n)
+	}
+	if n.BitLen() > maxBigIntBits {
+		return nil, fmt.Errorf("%v exceeds %d bits", n, maxBigIntBits)
+	}
+	return n, nil
+}
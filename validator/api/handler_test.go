@@ -0,0 +1,127 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/prysmaticlabs/prysm/validator/types"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *types.Collation) {
+	addr := common.HexToAddress("0x0ee3e8ae19a3c2a6fce4a4d5a5e8d4e76f63e0c6")
+	header, err := types.NewCollationHeader(big.NewInt(1), nil, big.NewInt(5), &addr, make([]byte, 65), false)
+	if err != nil {
+		t.Fatalf("could not create collation header: %v", err)
+	}
+	txs := []*gethTypes.Transaction{
+		gethTypes.NewTransaction(0, common.HexToAddress("0x0"), nil, 0, nil, []byte{1, 2, 3}),
+	}
+	body, err := types.SerializeTxToBlob(txs)
+	if err != nil {
+		t.Fatalf("could not serialize transactions: %v", err)
+	}
+	collation := types.NewCollation(header, body, txs)
+	collation.CalculateChunkRoot()
+
+	store := types.NewMemoryCollationStore()
+	if err := store.Put(collation); err != nil {
+		t.Fatalf("could not store collation: %v", err)
+	}
+	return httptest.NewServer(NewCollationHTTPHandler(store)), collation
+}
+
+func TestCollationHTTPHandler_Header(t *testing.T) {
+	server, collation := newTestServer(t)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/collation/1/5")
+	if err != nil {
+		t.Fatalf("could not request header: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status code incorrect. want=%d. got=%d", http.StatusOK, resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("content type incorrect. want=%s. got=%s", "application/json", ct)
+	}
+
+	header := &types.CollationHeader{}
+	if err := json.NewDecoder(resp.Body).Decode(header); err != nil {
+		t.Fatalf("could not decode header: %v", err)
+	}
+	if !header.Equal(collation.Header()) {
+		t.Errorf("header incorrect. want=%v. got=%v", collation.Header().Hash().Hex(), header.Hash().Hex())
+	}
+}
+
+func TestCollationHTTPHandler_Body(t *testing.T) {
+	server, collation := newTestServer(t)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/collation/1/5/body")
+	if err != nil {
+		t.Fatalf("could not request body: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status code incorrect. want=%d. got=%d", http.StatusOK, resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/octet-stream" {
+		t.Errorf("content type incorrect. want=%s. got=%s", "application/octet-stream", ct)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("could not read body: %v", err)
+	}
+	if !bytes.Equal(body, collation.Body()) {
+		t.Errorf("body incorrect. want=%x. got=%x", collation.Body(), body)
+	}
+}
+
+func TestCollationHTTPHandler_Errors(t *testing.T) {
+	server, _ := newTestServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{path: "/collation/1/6", status: http.StatusNotFound},
+		{path: "/collation/2/5/body", status: http.StatusNotFound},
+		{path: "/collation/1/5/chunks", status: http.StatusNotFound},
+		{path: "/collation/1", status: http.StatusNotFound},
+		{path: "/collations/1/5", status: http.StatusNotFound},
+		{path: "/collation/one/5", status: http.StatusBadRequest},
+		{path: "/collation/1/-5", status: http.StatusBadRequest},
+		{path: "/collation/1/0x5/body", status: http.StatusBadRequest},
+		{path: "/collation/" + new(big.Int).Lsh(big.NewInt(1), 256).String() + "/5", status: http.StatusBadRequest},
+		{path: "/collation/1/" + new(big.Int).Lsh(big.NewInt(1), 300).String() + "/body", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		resp, err := http.Get(server.URL + tt.path)
+		if err != nil {
+			t.Fatalf("could not request %s: %v", tt.path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("status code for %s incorrect. want=%d. got=%d", tt.path, tt.status, resp.StatusCode)
+		}
+	}
+
+	resp, err := http.Post(server.URL+"/collation/1/5", "application/json", nil)
+	if err != nil {
+		t.Fatalf("could not post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("status code incorrect. want=%d. got=%d", http.StatusMethodNotAllowed, resp.StatusCode)
+	}
+}
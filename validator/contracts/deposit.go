@@ -0,0 +1,50 @@
+// Package contracts contains helpers to interpret the logs of the contracts
+// sharding nodes interact with on the main chain, and to encode the values
+// passed to them.
+package contracts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// proposerRegisteredTopic is the topic of the deposit contract's
+// ProposerRegistered(address indexed proposer, uint256 amount) event.
+var proposerRegisteredTopic = crypto.Keccak256Hash([]byte("ProposerRegistered(address,uint256)"))
+
+// VerifyProposerDeposit checks that the receipt of a deposit transaction
+// contains a ProposerRegistered event emitted by the deposit contract for the
+// proposer, with a non-zero deposit. It returns false if the transaction failed
+// or the receipt holds no such event, and an error if the receipt is missing
+// or a ProposerRegistered event of the deposit contract is malformed.
+func VerifyProposerDeposit(addr common.Address, receipt *gethTypes.Receipt, depositContractAddr common.Address) (bool, error) {
+	if receipt == nil {
+		return false, errors.New("deposit receipt is nil")
+	}
+	if receipt.Status == gethTypes.ReceiptStatusFailed {
+		return false, nil
+	}
+
+	for i, l := range receipt.Logs {
+		if l == nil || l.Address != depositContractAddr || len(l.Topics) == 0 || l.Topics[0] != proposerRegisteredTopic {
+			continue
+		}
+		if len(l.Topics) != 2 {
+			return false, fmt.Errorf("ProposerRegistered log %d has %d topics, expected 2", i, len(l.Topics))
+		}
+		if len(l.Data) != common.HashLength {
+			return false, fmt.Errorf("ProposerRegistered log %d has %d bytes of data, expected %d", i, len(l.Data), common.HashLength)
+		}
+		proposer := common.BytesToAddress(l.Topics[1].Bytes())
+		amount := new(big.Int).SetBytes(l.Data)
+		if proposer == addr && amount.Sign() > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,99 @@
+package contracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	testProposer        = common.HexToAddress("0x0ee3e8ae19a3c2a6fce4a4d5a5e8d4e76f63e0c6")
+	testDepositContract = common.HexToAddress("0x5a0b54d5dc17e0aadc383d2db43b0a0d3e029c4c")
+)
+
+func proposerRegisteredLog(contract common.Address, proposer common.Address, amount int64) *gethTypes.Log {
+	return &gethTypes.Log{
+		Address: contract,
+		Topics:  []common.Hash{proposerRegisteredTopic, common.BytesToHash(proposer.Bytes())},
+		Data:    common.BigToHash(big.NewInt(amount)).Bytes(),
+	}
+}
+
+func TestVerifyProposerDeposit(t *testing.T) {
+	otherLog := &gethTypes.Log{
+		Address: testDepositContract,
+		Topics:  []common.Hash{common.HexToHash("0x01")},
+	}
+	tests := []struct {
+		name   string
+		status uint64
+		logs   []*gethTypes.Log
+		want   bool
+	}{
+		{
+			name:   "valid deposit",
+			status: gethTypes.ReceiptStatusSuccessful,
+			logs:   []*gethTypes.Log{otherLog, proposerRegisteredLog(testDepositContract, testProposer, 100)},
+			want:   true,
+		},
+		{
+			name:   "failed transaction",
+			status: gethTypes.ReceiptStatusFailed,
+			logs:   []*gethTypes.Log{proposerRegisteredLog(testDepositContract, testProposer, 100)},
+			want:   false,
+		},
+		{
+			name:   "no logs",
+			status: gethTypes.ReceiptStatusSuccessful,
+			want:   false,
+		},
+		{
+			name:   "other proposer",
+			status: gethTypes.ReceiptStatusSuccessful,
+			logs:   []*gethTypes.Log{proposerRegisteredLog(testDepositContract, common.HexToAddress("0x01"), 100)},
+			want:   false,
+		},
+		{
+			name:   "other contract",
+			status: gethTypes.ReceiptStatusSuccessful,
+			logs:   []*gethTypes.Log{proposerRegisteredLog(common.HexToAddress("0x02"), testProposer, 100)},
+			want:   false,
+		},
+		{
+			name:   "zero deposit",
+			status: gethTypes.ReceiptStatusSuccessful,
+			logs:   []*gethTypes.Log{proposerRegisteredLog(testDepositContract, testProposer, 0)},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		receipt := &gethTypes.Receipt{Status: tt.status, Logs: tt.logs}
+		got, err := VerifyProposerDeposit(testProposer, receipt, testDepositContract)
+		if err != nil {
+			t.Fatalf("%s: could not verify deposit: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: deposit verification incorrect. want=%v. got=%v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestVerifyProposerDeposit_Malformed(t *testing.T) {
+	missingTopic := proposerRegisteredLog(testDepositContract, testProposer, 100)
+	missingTopic.Topics = missingTopic.Topics[:1]
+	shortData := proposerRegisteredLog(testDepositContract, testProposer, 100)
+	shortData.Data = shortData.Data[:8]
+
+	for _, l := range []*gethTypes.Log{missingTopic, shortData} {
+		receipt := &gethTypes.Receipt{Status: gethTypes.ReceiptStatusSuccessful, Logs: []*gethTypes.Log{l}}
+		if _, err := VerifyProposerDeposit(testProposer, receipt, testDepositContract); err == nil {
+			t.Errorf("verifying a malformed ProposerRegistered log should fail")
+		}
+	}
+	if _, err := VerifyProposerDeposit(testProposer, nil, testDepositContract); err == nil {
+		t.Errorf("verifying a nil receipt should fail")
+	}
+}
@@ -0,0 +1,45 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SMCCaller is the subset of the Sharding Manager Contract calls needed to
+// check notary eligibility, so that notaries do not depend on a concrete
+// Ethereum client.
+type SMCCaller interface {
+	// CurrentPeriod returns the period of the main chain's latest block.
+	CurrentPeriod(ctx context.Context) (*big.Int, error)
+	// GetAttesterInCommittee calls the SMC's getAttesterInCommittee, which
+	// returns the notary sampled into the shard's committee for the current
+	// period from the pool slot of opts.From.
+	GetAttesterInCommittee(opts *bind.CallOpts, shardID *big.Int) (common.Address, error)
+}
+
+// IsEligibleNotary checks whether the notary at addr is sampled into the
+// committee of the shard for the period. The SMC only samples committees for
+// the current period, so an error is returned for any other period.
+func IsEligibleNotary(ctx context.Context, addr common.Address, shardID *big.Int, period *big.Int, smcCaller SMCCaller) (bool, error) {
+	if shardID == nil || period == nil {
+		return false, errors.New("shardID and period are required")
+	}
+	current, err := smcCaller.CurrentPeriod(ctx)
+	if err != nil {
+		return false, fmt.Errorf("could not get current period: %v", err)
+	}
+	if current.Cmp(period) != 0 {
+		return false, fmt.Errorf("committee of period %v cannot be sampled in period %v", period, current)
+	}
+
+	sampled, err := smcCaller.GetAttesterInCommittee(&bind.CallOpts{From: addr, Context: ctx}, shardID)
+	if err != nil {
+		return false, fmt.Errorf("could not get committee member of shard %v: %v", shardID, err)
+	}
+	return sampled == addr, nil
+}
@@ -0,0 +1,68 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// mockSMCCaller samples the notaries in committee from a fixed table, keyed by
+// the caller's address and the shardID.
+type mockSMCCaller struct {
+	period    *big.Int
+	committee map[common.Address]map[int64]common.Address
+	err       error
+}
+
+func (m *mockSMCCaller) CurrentPeriod(ctx context.Context) (*big.Int, error) {
+	return m.period, nil
+}
+
+func (m *mockSMCCaller) GetAttesterInCommittee(opts *bind.CallOpts, shardID *big.Int) (common.Address, error) {
+	if m.err != nil {
+		return common.Address{}, m.err
+	}
+	return m.committee[opts.From][shardID.Int64()], nil
+}
+
+func TestIsEligibleNotary(t *testing.T) {
+	notary := common.HexToAddress("0x01")
+	other := common.HexToAddress("0x02")
+	caller := &mockSMCCaller{
+		period: big.NewInt(10),
+		committee: map[common.Address]map[int64]common.Address{
+			notary: {0: notary, 1: other},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		shardID int64
+		want    bool
+	}{
+		{name: "sampled", shardID: 0, want: true},
+		{name: "other notary sampled", shardID: 1, want: false},
+		{name: "no notary sampled", shardID: 2, want: false},
+	}
+	for _, tt := range tests {
+		eligible, err := IsEligibleNotary(context.Background(), notary, big.NewInt(tt.shardID), big.NewInt(10), caller)
+		if err != nil {
+			t.Fatalf("%s: could not check eligibility: %v", tt.name, err)
+		}
+		if eligible != tt.want {
+			t.Errorf("%s: eligibility incorrect. want=%v. got=%v", tt.name, tt.want, eligible)
+		}
+	}
+
+	if _, err := IsEligibleNotary(context.Background(), notary, big.NewInt(0), big.NewInt(11), caller); err == nil {
+		t.Errorf("checking eligibility for a period other than the current one should fail")
+	}
+	caller.err = errors.New("connection refused")
+	if _, err := IsEligibleNotary(context.Background(), notary, big.NewInt(0), big.NewInt(10), caller); err == nil {
+		t.Errorf("a failing SMC call should return an error")
+	}
+}
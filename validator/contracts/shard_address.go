@@ -0,0 +1,27 @@
+package contracts
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ShardIDToAddress encodes a shardID into the low-order bytes of an address,
+// the way the Sharding Manager Contract passes shardIDs in address arguments.
+// The shardID must be non-negative and fit in the 20 bytes of an address.
+func ShardIDToAddress(id *big.Int) (common.Address, error) {
+	if id == nil || id.Sign() < 0 {
+		return common.Address{}, fmt.Errorf("shardID %v must be non-negative", id)
+	}
+	if id.BitLen() > 8*common.AddressLength {
+		return common.Address{}, fmt.Errorf("shardID %v does not fit in %d bytes", id, common.AddressLength)
+	}
+	return common.BigToAddress(id), nil
+}
+
+// AddressToShardID decodes the shardID held in the low-order bytes of an
+// address by ShardIDToAddress.
+func AddressToShardID(addr common.Address) *big.Int {
+	return new(big.Int).SetBytes(addr.Bytes())
+}
@@ -0,0 +1,42 @@
+package contracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestShardIDToAddress(t *testing.T) {
+	maxID := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+	tests := []struct {
+		id   *big.Int
+		want common.Address
+	}{
+		{id: big.NewInt(0), want: common.Address{}},
+		{id: big.NewInt(1), want: common.HexToAddress("0x0000000000000000000000000000000000000001")},
+		{id: big.NewInt(0x1234), want: common.HexToAddress("0x0000000000000000000000000000000000001234")},
+		{id: maxID, want: common.HexToAddress("0xffffffffffffffffffffffffffffffffffffffff")},
+	}
+
+	for _, tt := range tests {
+		addr, err := ShardIDToAddress(tt.id)
+		if err != nil {
+			t.Fatalf("could not encode shardID %v: %v", tt.id, err)
+		}
+		if addr != tt.want {
+			t.Errorf("address of shardID %v incorrect. want=%s. got=%s", tt.id, tt.want.Hex(), addr.Hex())
+		}
+		if id := AddressToShardID(addr); id.Cmp(tt.id) != 0 {
+			t.Errorf("shardID decoded from %s incorrect. want=%v. got=%v", addr.Hex(), tt.id, id)
+		}
+	}
+}
+
+func TestShardIDToAddress_OutOfBounds(t *testing.T) {
+	for _, id := range []*big.Int{nil, big.NewInt(-1), new(big.Int).Lsh(big.NewInt(1), 160)} {
+		if _, err := ShardIDToAddress(id); err == nil {
+			t.Errorf("encoding shardID %v should fail", id)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "contracts")
+
+// periodStartedTopic is the topic of the SMC's PeriodStarted(uint256 period) event.
+var periodStartedTopic = crypto.Keccak256Hash([]byte("PeriodStarted(uint256)"))
+
+const (
+	// maxResubscribeAttempts bounds the attempts to re-subscribe to the SMC's
+	// events after a subscription dies.
+	maxResubscribeAttempts = 5
+	// defaultResubscribeBackoff is the delay before the first re-subscription
+	// attempt, doubled after every failed attempt.
+	defaultResubscribeBackoff = time.Second
+)
+
+// SMCWatcher watches the Sharding Manager Contract's events to trigger
+// period transitions.
+type SMCWatcher struct {
+	backoff time.Duration
+}
+
+// NewSMCWatcher creates an SMCWatcher.
+func NewSMCWatcher() *SMCWatcher {
+	return &SMCWatcher{backoff: defaultResubscribeBackoff}
+}
+
+// WatchPeriodStart subscribes to the PeriodStarted events of the SMC at
+// smcAddr and sends the number of every started period on the returned
+// channel. A dead subscription is re-subscribed with exponential backoff, and
+// the channel is closed once ctx is done or re-subscribing failed
+// maxResubscribeAttempts times in a row.
+func (w *SMCWatcher) WatchPeriodStart(ctx context.Context, client ethereum.LogFilterer, smcAddr common.Address) (<-chan *big.Int, error) {
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{smcAddr},
+		Topics:    [][]common.Hash{{periodStartedTopic}},
+	}
+	logs := make(chan gethTypes.Log)
+	sub, err := client.SubscribeFilterLogs(ctx, query, logs)
+	if err != nil {
+		return nil, fmt.Errorf("could not subscribe to PeriodStarted events: %v", err)
+	}
+
+	periods := make(chan *big.Int)
+	go w.watch(ctx, client, query, sub, logs, periods)
+	return periods, nil
+}
+
+// watch forwards the periods of the subscription's events until ctx is done
+// or the subscription cannot be restored.
+func (w *SMCWatcher) watch(ctx context.Context, client ethereum.LogFilterer, query ethereum.FilterQuery, sub ethereum.Subscription, logs chan gethTypes.Log, periods chan<- *big.Int) {
+	defer close(periods)
+	for {
+		select {
+		case <-ctx.Done():
+			sub.Unsubscribe()
+			return
+		case l := <-logs:
+			// Events of blocks reverted by a reorg are not period transitions.
+			if l.Removed {
+				continue
+			}
+			period, err := decodePeriodStarted(l)
+			if err != nil {
+				log.Warnf("Could not decode PeriodStarted event: %v", err)
+				continue
+			}
+			select {
+			case periods <- period:
+			case <-ctx.Done():
+				sub.Unsubscribe()
+				return
+			}
+		case err := <-sub.Err():
+			log.Warnf("PeriodStarted subscription died: %v", err)
+			sub.Unsubscribe()
+			if sub = w.resubscribe(ctx, client, query, logs); sub == nil {
+				return
+			}
+		}
+	}
+}
+
+// resubscribe re-subscribes to the query's events, doubling the backoff after
+// every failed attempt. It returns nil if ctx is done or every attempt failed.
+func (w *SMCWatcher) resubscribe(ctx context.Context, client ethereum.LogFilterer, query ethereum.FilterQuery, logs chan gethTypes.Log) ethereum.Subscription {
+	backoff := w.backoff
+	for attempt := 1; attempt <= maxResubscribeAttempts; attempt++ {
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil
+		}
+		sub, err := client.SubscribeFilterLogs(ctx, query, logs)
+		if err == nil {
+			log.Infof("Re-subscribed to PeriodStarted events after %d attempts", attempt)
+			return sub
+		}
+		log.Warnf("Could not re-subscribe to PeriodStarted events, attempt %d of %d: %v", attempt, maxResubscribeAttempts, err)
+		backoff *= 2
+	}
+	log.Errorf("Giving up on PeriodStarted events after %d attempts", maxResubscribeAttempts)
+	return nil
+}
+
+// decodePeriodStarted reads the period number from a PeriodStarted event,
+// whose only data is the ABI encoded uint256 period.
+func decodePeriodStarted(l gethTypes.Log) (*big.Int, error) {
+	if len(l.Topics) == 0 || l.Topics[0] != periodStartedTopic {
+		return nil, fmt.Errorf("log of tx %s is not a PeriodStarted event", l.TxHash.Hex())
+	}
+	if len(l.Data) != common.HashLength {
+		return nil, fmt.Errorf("PeriodStarted event has %d bytes of data, expected %d", len(l.Data), common.HashLength)
+	}
+	return new(big.Int).SetBytes(l.Data), nil
+}
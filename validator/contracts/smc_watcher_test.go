@@ -0,0 +1,189 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// subscriptionScript describes how a mock subscription behaves: it either
+// fails to subscribe with err, or emits logs and then dies with dieWith, or
+// stays open if dieWith is nil.
+type subscriptionScript struct {
+	err     error
+	logs    []gethTypes.Log
+	dieWith error
+}
+
+// mockChainReader serves log subscriptions following its scripts in order.
+type mockChainReader struct {
+	lock    sync.Mutex
+	scripts []subscriptionScript
+	queries []ethereum.FilterQuery
+}
+
+func (m *mockChainReader) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]gethTypes.Log, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockChainReader) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- gethTypes.Log) (ethereum.Subscription, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.queries = append(m.queries, q)
+	if len(m.scripts) == 0 {
+		return nil, errors.New("no more subscriptions")
+	}
+	script := m.scripts[0]
+	m.scripts = m.scripts[1:]
+	if script.err != nil {
+		return nil, script.err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		for _, l := range script.logs {
+			select {
+			case ch <- l:
+			case <-quit:
+				return nil
+			}
+		}
+		if script.dieWith != nil {
+			return script.dieWith
+		}
+		<-quit
+		return nil
+	}), nil
+}
+
+func (m *mockChainReader) subscriptions() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.queries)
+}
+
+func periodStartedLog(period int64) gethTypes.Log {
+	return gethTypes.Log{
+		Topics: []common.Hash{periodStartedTopic},
+		Data:   common.BigToHash(big.NewInt(period)).Bytes(),
+	}
+}
+
+// receivePeriods reads n periods from the channel, failing the test if they
+// do not arrive in time.
+func receivePeriods(t *testing.T, periods <-chan *big.Int, n int) []int64 {
+	var got []int64
+	for len(got) < n {
+		select {
+		case period, ok := <-periods:
+			if !ok {
+				t.Fatalf("period channel closed after %d periods, want %d", len(got), n)
+			}
+			got = append(got, period.Int64())
+		case <-time.After(time.Second):
+			t.Fatalf("timed out after %d periods, want %d", len(got), n)
+		}
+	}
+	return got
+}
+
+func expectClosed(t *testing.T, periods <-chan *big.Int) {
+	select {
+	case period, ok := <-periods:
+		if ok {
+			t.Fatalf("unexpected period %v", period)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("period channel should be closed")
+	}
+}
+
+func newTestSMCWatcher() *SMCWatcher {
+	w := NewSMCWatcher()
+	w.backoff = time.Millisecond
+	return w
+}
+
+func TestSMCWatcher_WatchPeriodStart(t *testing.T) {
+	removed := periodStartedLog(99)
+	removed.Removed = true
+	malformed := periodStartedLog(98)
+	malformed.Data = malformed.Data[:8]
+	client := &mockChainReader{scripts: []subscriptionScript{
+		{logs: []gethTypes.Log{periodStartedLog(1), removed, malformed, periodStartedLog(2), periodStartedLog(3)}},
+	}}
+	smcAddr := common.HexToAddress("0x1234")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	periods, err := newTestSMCWatcher().WatchPeriodStart(ctx, client, smcAddr)
+	if err != nil {
+		t.Fatalf("could not watch periods: %v", err)
+	}
+	if got, want := receivePeriods(t, periods, 3), []int64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected periods. want=%v. got=%v", want, got)
+	}
+
+	query := client.queries[0]
+	if len(query.Addresses) != 1 || query.Addresses[0] != smcAddr {
+		t.Errorf("subscription should filter on the SMC address. want=%v. got=%v", smcAddr.Hex(), query.Addresses)
+	}
+	if len(query.Topics) != 1 || len(query.Topics[0]) != 1 || query.Topics[0][0] != periodStartedTopic {
+		t.Errorf("subscription should filter on the PeriodStarted topic. got=%v", query.Topics)
+	}
+
+	cancel()
+	expectClosed(t, periods)
+}
+
+func TestSMCWatcher_Resubscribe(t *testing.T) {
+	client := &mockChainReader{scripts: []subscriptionScript{
+		{logs: []gethTypes.Log{periodStartedLog(1)}, dieWith: errors.New("connection lost")},
+		{err: errors.New("connection refused")},
+		{err: errors.New("connection refused")},
+		{logs: []gethTypes.Log{periodStartedLog(2)}},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	periods, err := newTestSMCWatcher().WatchPeriodStart(ctx, client, common.Address{})
+	if err != nil {
+		t.Fatalf("could not watch periods: %v", err)
+	}
+	if got, want := receivePeriods(t, periods, 2), []int64{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected periods. want=%v. got=%v", want, got)
+	}
+	if got := client.subscriptions(); got != 4 {
+		t.Errorf("unexpected number of subscriptions. want=%d. got=%d", 4, got)
+	}
+}
+
+func TestSMCWatcher_GivesUp(t *testing.T) {
+	scripts := []subscriptionScript{{dieWith: errors.New("connection lost")}}
+	for i := 0; i < maxResubscribeAttempts+1; i++ {
+		scripts = append(scripts, subscriptionScript{err: errors.New("connection refused")})
+	}
+	client := &mockChainReader{scripts: scripts}
+
+	periods, err := newTestSMCWatcher().WatchPeriodStart(context.Background(), client, common.Address{})
+	if err != nil {
+		t.Fatalf("could not watch periods: %v", err)
+	}
+	expectClosed(t, periods)
+	if got, want := client.subscriptions(), 1+maxResubscribeAttempts; got != want {
+		t.Errorf("unexpected number of subscriptions. want=%d. got=%d", want, got)
+	}
+}
+
+func TestSMCWatcher_SubscribeError(t *testing.T) {
+	client := &mockChainReader{scripts: []subscriptionScript{{err: errors.New("connection refused")}}}
+	if _, err := newTestSMCWatcher().WatchPeriodStart(context.Background(), client, common.Address{}); err == nil {
+		t.Errorf("watching periods should fail when the subscription cannot be created")
+	}
+}
@@ -10,9 +10,14 @@ import (
 // DefaultConfig returns pointer to a Config value with same defaults.
 func DefaultConfig() *Config {
 	return &Config{
-		CollationSizeLimit: DefaultCollationSizeLimit(),
-		SlotDuration:       8.0,
-		CycleLength:        64,
+		CollationSizeLimit:      DefaultCollationSizeLimit(),
+		CollationGasLimit:       DefaultCollationGasLimit(),
+		ShardCount:              DefaultShardCount(),
+		CommitteeSize:           DefaultCommitteeSize(),
+		PeriodLength:            DefaultPeriodLength(),
+		CommitteeRotationPeriod: DefaultCommitteeRotationPeriod(),
+		SlotDuration:            8.0,
+		CycleLength:             64,
 	}
 }
 
@@ -30,9 +35,42 @@ func DefaultCollationSizeLimit() int64 {
 	return int64(math.Pow(float64(2), float64(20)))
 }
 
+// DefaultCollationGasLimit is the maximum amount of gas the transactions of a
+// collation can use.
+func DefaultCollationGasLimit() uint64 {
+	return 10000000
+}
+
+// DefaultShardCount is the number of shards tracked by the sharding manager contract.
+func DefaultShardCount() int64 {
+	return 100
+}
+
+// DefaultCommitteeSize is the number of validators sampled into a shard's committee.
+func DefaultCommitteeSize() int {
+	return 135
+}
+
+// DefaultPeriodLength is the number of mainchain blocks in a period, as defined
+// by the sharding manager contract.
+func DefaultPeriodLength() int64 {
+	return 5
+}
+
+// DefaultCommitteeRotationPeriod is the number of periods a shard's committee
+// serves before it is sampled again.
+func DefaultCommitteeRotationPeriod() int64 {
+	return 4
+}
+
 // Config contains configs for node to participate in the sharded universe.
 type Config struct {
-	CollationSizeLimit int64  // CollationSizeLimit is the maximum size the serialized blobs in a collation can take.
-	SlotDuration       uint64 // SlotDuration in seconds.
-	CycleLength        uint64
+	CollationSizeLimit      int64  // CollationSizeLimit is the maximum size the serialized blobs in a collation can take.
+	CollationGasLimit       uint64 // CollationGasLimit is the maximum amount of gas the transactions in a collation can use.
+	ShardCount              int64  // ShardCount is the number of shards collations can be proposed to.
+	CommitteeSize           int    // CommitteeSize is the number of validators sampled into a shard's committee.
+	PeriodLength            int64  // PeriodLength is the number of mainchain blocks in a period.
+	CommitteeRotationPeriod int64  // CommitteeRotationPeriod is the number of periods a shard's committee serves before rotating.
+	SlotDuration            uint64 // SlotDuration in seconds.
+	CycleLength             uint64
 }
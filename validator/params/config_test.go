@@ -11,3 +11,38 @@ func TestAttesterCommitteeSize(t *testing.T) {
 		t.Errorf("Shard count incorrect. Wanted %d, got %d", int64(math.Pow(float64(2), float64(20))), c.CollationSizeLimit)
 	}
 }
+
+func TestShardCount(t *testing.T) {
+	c := DefaultConfig()
+	if c.ShardCount != 100 {
+		t.Errorf("Shard count incorrect. Wanted %d, got %d", 100, c.ShardCount)
+	}
+}
+
+func TestCollationGasLimit(t *testing.T) {
+	c := DefaultConfig()
+	if c.CollationGasLimit != 10000000 {
+		t.Errorf("Collation gas limit incorrect. Wanted %d, got %d", 10000000, c.CollationGasLimit)
+	}
+}
+
+func TestCommitteeSize(t *testing.T) {
+	c := DefaultConfig()
+	if c.CommitteeSize != 135 {
+		t.Errorf("Committee size incorrect. Wanted %d, got %d", 135, c.CommitteeSize)
+	}
+}
+
+func TestPeriodLength(t *testing.T) {
+	c := DefaultConfig()
+	if c.PeriodLength != 5 {
+		t.Errorf("Period length incorrect. Wanted %d, got %d", 5, c.PeriodLength)
+	}
+}
+
+func TestCommitteeRotationPeriod(t *testing.T) {
+	c := DefaultConfig()
+	if c.CommitteeRotationPeriod != 4 {
+		t.Errorf("Committee rotation period incorrect. Wanted %d, got %d", 4, c.CommitteeRotationPeriod)
+	}
+}
@@ -0,0 +1,150 @@
+// Package rpc defines the gRPC services a validator node uses to exchange
+// collation data with its peers.
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	pb "github.com/prysmaticlabs/prysm/proto/sharding/rpc/v1"
+	"github.com/prysmaticlabs/prysm/validator/params"
+	"github.com/prysmaticlabs/prysm/validator/types"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "rpc")
+
+// DefaultChunkSize is the number of body bytes sent in each chunk of a
+// streamed collation body upload.
+const DefaultChunkSize = 64 * 1024
+
+// CollationServer receives streamed collation bodies and stores them in a
+// shard.
+type CollationServer struct {
+	shard     *types.Shard
+	config    types.ShardConfig
+	chunkSize int
+}
+
+// NewCollationServer creates a CollationServer storing uploaded bodies in
+// shard, checking them against cfg and accepting chunks of at most chunkSize
+// bytes. A chunkSize of zero or less defaults to DefaultChunkSize.
+func NewCollationServer(shard *types.Shard, cfg types.ShardConfig, chunkSize int) *CollationServer {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	return &CollationServer{shard: shard, config: cfg, chunkSize: chunkSize}
+}
+
+// UploadCollationBody reassembles a collation body streamed in chunks, checks
+// it against the chunk root of the header sent with the first chunk and
+// stores the collation in the shard. The header must be signed by all of its
+// proposers.
+func (s *CollationServer) UploadCollationBody(stream pb.CollationService_UploadCollationBodyServer) error {
+	var header *types.CollationHeader
+	var body []byte
+	sizeLimit := s.config.CollationSizeLimit
+	if sizeLimit == 0 {
+		sizeLimit = params.DefaultCollationSizeLimit()
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("could not receive body chunk: %v", err)
+		}
+
+		if header == nil {
+			if chunk.Header == nil {
+				return errors.New("first body chunk carries no collation header")
+			}
+			if header, err = types.CollationHeaderFromProto(chunk.Header, s.config); err != nil {
+				return err
+			}
+			if header.ChunkRoot() == nil {
+				return errors.New("collation header has no chunk root")
+			}
+			if err := header.VerifyAllProposerSignatures(); err != nil {
+				return err
+			}
+		} else if chunk.Header != nil {
+			return fmt.Errorf("body chunk at offset %d carries a collation header, only the first chunk should", chunk.Offset)
+		}
+
+		if chunk.Offset != uint64(len(body)) {
+			return fmt.Errorf("body chunk at offset %d out of order, expected offset %d", chunk.Offset, len(body))
+		}
+		if len(chunk.Data) == 0 || len(chunk.Data) > s.chunkSize {
+			return fmt.Errorf("body chunk at offset %d has %d bytes, expected between 1 and %d", chunk.Offset, len(chunk.Data), s.chunkSize)
+		}
+		if int64(len(body)+len(chunk.Data)) > sizeLimit {
+			return fmt.Errorf("%w: body exceeds %d bytes", types.ErrCollationTooLarge, sizeLimit)
+		}
+		body = append(body, chunk.Data...)
+	}
+	if header == nil {
+		return fmt.Errorf("cannot upload body: %w", types.ErrEmptyBody)
+	}
+
+	collation := types.NewCollation(header, body, nil)
+	if err := collation.VerifyBodyIntegrity(); err != nil {
+		return err
+	}
+	if err := s.shard.SaveCollation(collation); err != nil {
+		return fmt.Errorf("could not save collation: %v", err)
+	}
+	log.WithFields(logrus.Fields{
+		"shardID":   header.ShardID(),
+		"period":    header.Period(),
+		"chunkRoot": header.ChunkRoot().Hex(),
+		"size":      len(body),
+	}).Debug("Stored uploaded collation body")
+
+	return stream.SendAndClose(&pb.UploadCollationBodyResponse{ChunkRoot: header.ChunkRoot().Bytes()})
+}
+
+// SendCollationBody uploads the body of the collation with the given header
+// to a CollationService, streaming it in DefaultChunkSize-byte chunks.
+func SendCollationBody(client pb.CollationServiceClient, header *types.CollationHeader, body []byte) error {
+	if header == nil {
+		return errors.New("no collation header provided")
+	}
+	if header.ChunkRoot() == nil {
+		return errors.New("collation header has no chunk root")
+	}
+	if len(body) == 0 {
+		return fmt.Errorf("cannot upload body: %w", types.ErrEmptyBody)
+	}
+
+	stream, err := client.UploadCollationBody(context.Background())
+	if err != nil {
+		return fmt.Errorf("could not open upload stream: %v", err)
+	}
+	for offset := 0; offset < len(body); offset += DefaultChunkSize {
+		end := offset + DefaultChunkSize
+		if end > len(body) {
+			end = len(body)
+		}
+		chunk := &pb.BodyChunk{Offset: uint64(offset), Data: body[offset:end]}
+		if offset == 0 {
+			chunk.Header = header.ToProto()
+		}
+		if err := stream.Send(chunk); err != nil {
+			return fmt.Errorf("could not send body chunk at offset %d: %v", offset, err)
+		}
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return fmt.Errorf("could not upload body: %v", err)
+	}
+	if !bytes.Equal(res.ChunkRoot, header.ChunkRoot().Bytes()) {
+		return fmt.Errorf("uploaded body stored under chunk root %#x, expected %s", res.ChunkRoot, header.ChunkRoot().Hex())
+	}
+	return nil
+}
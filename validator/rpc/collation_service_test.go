@@ -0,0 +1,256 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	pb "github.com/prysmaticlabs/prysm/proto/sharding/rpc/v1"
+	sharedDB "github.com/prysmaticlabs/prysm/shared/database"
+	"github.com/prysmaticlabs/prysm/validator/types"
+	"google.golang.org/grpc"
+)
+
+type mockUploadServerStream struct {
+	grpc.ServerStream
+	chunks []*pb.BodyChunk
+	res    *pb.UploadCollationBodyResponse
+}
+
+func (m *mockUploadServerStream) Recv() (*pb.BodyChunk, error) {
+	if len(m.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := m.chunks[0]
+	m.chunks = m.chunks[1:]
+	return chunk, nil
+}
+
+func (m *mockUploadServerStream) SendAndClose(res *pb.UploadCollationBodyResponse) error {
+	m.res = res
+	return nil
+}
+
+// mockUploadClientStream hands the chunks it is sent to the server once the
+// upload is closed.
+type mockUploadClientStream struct {
+	grpc.ClientStream
+	server *CollationServer
+	stream mockUploadServerStream
+	sent   int
+}
+
+func (m *mockUploadClientStream) Send(chunk *pb.BodyChunk) error {
+	m.stream.chunks = append(m.stream.chunks, chunk)
+	m.sent++
+	return nil
+}
+
+func (m *mockUploadClientStream) CloseAndRecv() (*pb.UploadCollationBodyResponse, error) {
+	if err := m.server.UploadCollationBody(&m.stream); err != nil {
+		return nil, err
+	}
+	return m.stream.res, nil
+}
+
+type mockCollationServiceClient struct {
+	server  *CollationServer
+	streams []*mockUploadClientStream
+}
+
+func (m *mockCollationServiceClient) UploadCollationBody(ctx context.Context, opts ...grpc.CallOption) (pb.CollationService_UploadCollationBodyClient, error) {
+	stream := &mockUploadClientStream{server: m.server}
+	m.streams = append(m.streams, stream)
+	return stream, nil
+}
+
+// makeLargeCollation creates a signed collation for shard 0 whose body spans
+// several upload chunks.
+func makeLargeCollation(t *testing.T) *types.Collation {
+	body := make([]byte, 3*DefaultChunkSize+100)
+	rand.New(rand.NewSource(1)).Read(body)
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	proposer := crypto.PubkeyToAddress(key.PublicKey)
+	header, err := types.NewCollationHeader(big.NewInt(0), nil, big.NewInt(1), &proposer, nil, false)
+	if err != nil {
+		t.Fatalf("could not create header: %v", err)
+	}
+	c := types.NewCollation(header, body, nil)
+	c.CalculateChunkRoot()
+	if err := header.Sign(key); err != nil {
+		t.Fatalf("could not sign header: %v", err)
+	}
+	return c
+}
+
+func TestSendCollationBody(t *testing.T) {
+	shard := types.NewShard(big.NewInt(0), sharedDB.NewKVStore())
+	client := &mockCollationServiceClient{server: NewCollationServer(shard, types.ShardConfig{}, 0)}
+	c := makeLargeCollation(t)
+
+	if err := SendCollationBody(client, c.Header(), c.Body()); err != nil {
+		t.Fatalf("could not upload collation body: %v", err)
+	}
+
+	wantChunks := (len(c.Body()) + DefaultChunkSize - 1) / DefaultChunkSize
+	if got := client.streams[0].sent; got != wantChunks {
+		t.Errorf("body of %d bytes sent in unexpected number of chunks. want=%d. got=%d", len(c.Body()), wantChunks, got)
+	}
+	body, err := shard.BodyByChunkRoot(c.Header().ChunkRoot())
+	if err != nil {
+		t.Fatalf("could not fetch uploaded body: %v", err)
+	}
+	if !bytes.Equal(body, c.Body()) {
+		t.Errorf("stored body of %d bytes does not match the uploaded body of %d bytes", len(body), len(c.Body()))
+	}
+	hash := c.Header().SignedHash()
+	header, err := shard.HeaderByHash(&hash)
+	if err != nil {
+		t.Fatalf("could not fetch uploaded header: %v", err)
+	}
+	if !header.Equal(c.Header()) {
+		t.Errorf("stored header does not match the uploaded header. want=%v. got=%v", c.Header(), header)
+	}
+}
+
+func TestSendCollationBody_Invalid(t *testing.T) {
+	shard := types.NewShard(big.NewInt(0), sharedDB.NewKVStore())
+	client := &mockCollationServiceClient{server: NewCollationServer(shard, types.ShardConfig{}, 0)}
+	c := makeLargeCollation(t)
+
+	if err := SendCollationBody(client, nil, c.Body()); err == nil {
+		t.Errorf("uploading a body without a header should fail")
+	}
+	if err := SendCollationBody(client, c.Header(), nil); !errors.Is(err, types.ErrEmptyBody) {
+		t.Errorf("uploading an empty body should fail. want=%v. got=%v", types.ErrEmptyBody, err)
+	}
+	if len(client.streams) != 0 {
+		t.Errorf("invalid uploads should not open a stream. got=%d streams", len(client.streams))
+	}
+}
+
+func TestUploadCollationBody_Invalid(t *testing.T) {
+	c := makeLargeCollation(t)
+	header := c.Header().ToProto()
+	body := c.Body()
+	corrupted := append([]byte{}, body...)
+	corrupted[len(corrupted)-1] ^= 0xff
+	noRoot := c.Header().ToProto()
+	noRoot.ChunkRoot = nil
+	forged := c.Header().ToProto()
+	forged.Signature = make([]byte, len(forged.Signature))
+
+	tests := []struct {
+		name    string
+		chunks  []*pb.BodyChunk
+		wantErr string
+		is      error
+	}{
+		{
+			name: "empty upload",
+			is:   types.ErrEmptyBody,
+		},
+		{
+			name:    "missing header",
+			chunks:  []*pb.BodyChunk{{Offset: 0, Data: body[:1024]}},
+			wantErr: "carries no collation header",
+		},
+		{
+			name:    "missing chunk root",
+			chunks:  []*pb.BodyChunk{{Offset: 0, Data: body[:1024], Header: noRoot}},
+			wantErr: "has no chunk root",
+		},
+		{
+			name:   "unsigned header",
+			chunks: []*pb.BodyChunk{{Offset: 0, Data: body[:1024], Header: forged}},
+			is:     types.ErrInvalidProposerSignature,
+		},
+		{
+			name: "header on later chunk",
+			chunks: []*pb.BodyChunk{
+				{Offset: 0, Data: body[:1024], Header: header},
+				{Offset: 1024, Data: body[1024:2048], Header: header},
+			},
+			wantErr: "only the first chunk should",
+		},
+		{
+			name: "out of order chunk",
+			chunks: []*pb.BodyChunk{
+				{Offset: 0, Data: body[:1024], Header: header},
+				{Offset: 2048, Data: body[2048:3072]},
+			},
+			wantErr: "out of order",
+		},
+		{
+			name:    "oversized chunk",
+			chunks:  []*pb.BodyChunk{{Offset: 0, Data: body[:DefaultChunkSize+1], Header: header}},
+			wantErr: "expected between 1 and",
+		},
+		{
+			name:    "empty chunk",
+			chunks:  []*pb.BodyChunk{{Offset: 0, Header: header}},
+			wantErr: "expected between 1 and",
+		},
+		{
+			name:   "corrupted body",
+			chunks: []*pb.BodyChunk{{Offset: 0, Data: corrupted[:DefaultChunkSize], Header: header}},
+			is:     types.ErrChunkRootMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shard := types.NewShard(big.NewInt(0), sharedDB.NewKVStore())
+			stream := &mockUploadServerStream{chunks: tt.chunks}
+			err := NewCollationServer(shard, types.ShardConfig{}, 0).UploadCollationBody(stream)
+			if err == nil {
+				t.Fatalf("upload should fail")
+			}
+			if tt.is != nil && !errors.Is(err, tt.is) {
+				t.Errorf("unexpected error. want=%v. got=%v", tt.is, err)
+			}
+			if tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("unexpected error. want=%q. got=%v", tt.wantErr, err)
+			}
+			if stream.res != nil {
+				t.Errorf("failed upload should not send a response")
+			}
+			if available, _ := shard.CheckAvailability(c.Header()); available {
+				t.Errorf("failed upload should not store the body")
+			}
+		})
+	}
+}
+
+func TestNewCollationServer_ChunkSize(t *testing.T) {
+	c := makeLargeCollation(t)
+	shard := types.NewShard(big.NewInt(0), sharedDB.NewKVStore())
+	stream := &mockUploadServerStream{chunks: []*pb.BodyChunk{
+		{Offset: 0, Data: c.Body()[:2048], Header: c.Header().ToProto()},
+	}}
+	err := NewCollationServer(shard, types.ShardConfig{}, 1024).UploadCollationBody(stream)
+	if err == nil || !strings.Contains(err.Error(), "expected between 1 and 1024") {
+		t.Errorf("chunks beyond the configured chunk size should be rejected. got=%v", err)
+	}
+}
+
+func TestNewCollationServer_SizeLimit(t *testing.T) {
+	c := makeLargeCollation(t)
+	shard := types.NewShard(big.NewInt(0), sharedDB.NewKVStore())
+	stream := &mockUploadServerStream{chunks: []*pb.BodyChunk{
+		{Offset: 0, Data: c.Body()[:1024], Header: c.Header().ToProto()},
+		{Offset: 1024, Data: c.Body()[1024:2048]},
+	}}
+	err := NewCollationServer(shard, types.ShardConfig{CollationSizeLimit: 1500}, 1024).UploadCollationBody(stream)
+	if !errors.Is(err, types.ErrCollationTooLarge) {
+		t.Errorf("bodies beyond the configured size limit should be rejected. want=%v. got=%v", types.ErrCollationTooLarge, err)
+	}
+}
@@ -0,0 +1,97 @@
+// Package testing provides fixtures for tests of the packages building on
+// validator/types.
+package testing
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/prysmaticlabs/prysm/validator/params"
+	"github.com/prysmaticlabs/prysm/validator/types"
+)
+
+// maxRandomTxDataSize bounds the size of the data of random transactions.
+const maxRandomTxDataSize = 128
+
+// RandomCollation creates an unsigned collation for the shard holding txCount
+// random transactions, proposed by a random proposer in a random period, with
+// its body serialized and its chunk root calculated. The shard count is raised
+// to fit shardID if needed. It panics if the transactions do not fit in a
+// collation, as fixtures are only meant for tests.
+func RandomCollation(r *rand.Rand, txCount int, shardID int) *types.Collation {
+	cfg := types.ShardConfig{}
+	if int64(shardID) >= params.DefaultShardCount() {
+		cfg.ShardCount = shardID + 1
+	}
+	c, err := newRandomCollation(r, randomTransactions(r, txCount), shardID, cfg)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// RandomCollationWithConfig creates a random collation like RandomCollation,
+// for a random shard within the config's shard count and with a random number
+// of transactions fitting in the config's size and gas limits.
+func RandomCollationWithConfig(r *rand.Rand, cfg types.ShardConfig) *types.Collation {
+	shardCount := cfg.ShardCount
+	if shardCount == 0 {
+		shardCount = int(params.DefaultShardCount())
+	}
+	txs := randomTransactions(r, 1+r.Intn(16))
+	for {
+		c, err := newRandomCollation(r, txs, r.Intn(shardCount), cfg)
+		if err == nil {
+			return c
+		}
+		if len(txs) == 0 {
+			panic(err)
+		}
+		// Drop transactions until the rest fits the limits.
+		txs = txs[:len(txs)-1]
+	}
+}
+
+// newRandomCollation creates a collation of the transactions for the shard,
+// with a random proposer and period.
+func newRandomCollation(r *rand.Rand, txs []*gethTypes.Transaction, shardID int, cfg types.ShardConfig) (*types.Collation, error) {
+	if shardID < 0 {
+		return nil, errors.New("shardID must be non-negative")
+	}
+	proposer := randomAddress(r)
+	period := big.NewInt(r.Int63n(1 << 32))
+	header, err := types.NewCollationHeader(big.NewInt(int64(shardID)), nil, period, &proposer, nil, false, cfg)
+	if err != nil {
+		return nil, err
+	}
+	c := types.NewCollation(header, nil, txs, types.WithConfig(cfg))
+	if err := c.Reserialize(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// randomTransactions creates n unsigned transactions with random fields.
+func randomTransactions(r *rand.Rand, n int) []*gethTypes.Transaction {
+	txs := make([]*gethTypes.Transaction, n)
+	for i := range txs {
+		data := make([]byte, r.Intn(maxRandomTxDataSize))
+		r.Read(data)
+		amount := big.NewInt(r.Int63())
+		gasPrice := big.NewInt(1 + r.Int63n(1e12))
+		txs[i] = gethTypes.NewTransaction(r.Uint64(), randomAddress(r), amount, 21000+uint64(r.Intn(100000)), gasPrice, data)
+	}
+	return txs
+}
+
+// randomAddress returns a random non-zero address.
+func randomAddress(r *rand.Rand) common.Address {
+	var addr common.Address
+	for addr == (common.Address{}) {
+		r.Read(addr[:])
+	}
+	return addr
+}
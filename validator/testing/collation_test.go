@@ -0,0 +1,50 @@
+package testing_test
+
+import (
+	"math/rand"
+	"testing"
+
+	shardtesting "github.com/prysmaticlabs/prysm/validator/testing"
+	"github.com/prysmaticlabs/prysm/validator/types"
+)
+
+func TestRandomCollation(t *testing.T) {
+	for _, shardID := range []int{0, 7, 250} {
+		c := shardtesting.RandomCollation(rand.New(rand.NewSource(int64(shardID))), 5, shardID)
+		if c.Header().ShardID().Int64() != int64(shardID) {
+			t.Errorf("shardID incorrect. want=%d. got=%v", shardID, c.Header().ShardID())
+		}
+		if len(c.Transactions()) != 5 {
+			t.Errorf("transaction count incorrect. want=%d. got=%d", 5, len(c.Transactions()))
+		}
+		if !c.IsChunkRootFresh() {
+			t.Errorf("chunk root should be calculated from the body")
+		}
+		if len(c.Header().ProposerAddresses()) != 1 {
+			t.Errorf("collation should have a single proposer. got=%d", len(c.Header().ProposerAddresses()))
+		}
+	}
+
+	a := shardtesting.RandomCollation(rand.New(rand.NewSource(1)), 3, 1)
+	b := shardtesting.RandomCollation(rand.New(rand.NewSource(1)), 3, 1)
+	if *a.Header().ChunkRoot() != *b.Header().ChunkRoot() || a.Header().SignedHash() != b.Header().SignedHash() {
+		t.Errorf("collations generated from the same seed should match")
+	}
+}
+
+func TestRandomCollationWithConfig(t *testing.T) {
+	cfg := types.ShardConfig{ShardCount: 3, CollationSizeLimit: 512}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		c := shardtesting.RandomCollationWithConfig(r, cfg)
+		if c.Header().ShardID().Int64() >= 3 {
+			t.Errorf("shardID should be below the shard count. got=%v", c.Header().ShardID())
+		}
+		if len(c.Body()) > 512 {
+			t.Errorf("body should fit the size limit. got=%d bytes", len(c.Body()))
+		}
+		if !c.IsChunkRootFresh() {
+			t.Errorf("chunk root should be calculated from the body")
+		}
+	}
+}
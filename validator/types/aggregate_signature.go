@@ -0,0 +1,130 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// Committee members sign with BLS signatures over the BLS12-381 curve so that
+// their approvals of a collation header can be aggregated into a single
+// signature. Public keys are compressed G1 points and signatures are compressed
+// G2 points. Aggregation is only safe against rogue public keys if every key
+// was registered along with a proof of possession of its secret key.
+const (
+	blsSecretKeyLength = 32
+	blsPublicKeyLength = 48
+	blsSignatureLength = 96
+)
+
+// blsDomain separates the hash of signed messages from other uses of the curve.
+var blsDomain = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+// blsSecretKey parses a big endian BLS secret key, which must be a non-zero
+// scalar of the curve's group order.
+func blsSecretKey(secretKey []byte) (*big.Int, error) {
+	if len(secretKey) != blsSecretKeyLength {
+		return nil, fmt.Errorf("secret key has length %d, wanted %d", len(secretKey), blsSecretKeyLength)
+	}
+	sk := new(big.Int).SetBytes(secretKey)
+	if sk.Sign() == 0 || sk.Cmp(bls12381.NewG1().Q()) >= 0 {
+		return nil, errors.New("secret key is not a valid scalar")
+	}
+	return sk, nil
+}
+
+// BLSPublicKey derives the compressed public key of a BLS secret key.
+func BLSPublicKey(secretKey []byte) ([]byte, error) {
+	sk, err := blsSecretKey(secretKey)
+	if err != nil {
+		return nil, err
+	}
+	g1 := bls12381.NewG1()
+	return g1.ToCompressed(g1.MulScalarBig(g1.New(), g1.One(), sk)), nil
+}
+
+// BLSSign signs the message with a BLS secret key, returning a compressed signature.
+func BLSSign(secretKey []byte, message []byte) ([]byte, error) {
+	sk, err := blsSecretKey(secretKey)
+	if err != nil {
+		return nil, err
+	}
+	g2 := bls12381.NewG2()
+	point, err := g2.HashToCurve(message, blsDomain)
+	if err != nil {
+		return nil, fmt.Errorf("could not hash message to curve: %v", err)
+	}
+	return g2.ToCompressed(g2.MulScalarBig(g2.New(), point, sk)), nil
+}
+
+// aggregatePublicKeys sums compressed public keys into a single G1 point.
+func aggregatePublicKeys(publicKeys [][]byte) (*bls12381.PointG1, error) {
+	if len(publicKeys) == 0 {
+		return nil, errors.New("no public keys to aggregate")
+	}
+	g1 := bls12381.NewG1()
+	aggregate := g1.Zero()
+	for i, key := range publicKeys {
+		point, err := g1.FromCompressed(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %d: %v", i, err)
+		}
+		if g1.IsZero(point) {
+			return nil, fmt.Errorf("public key %d is the point at infinity", i)
+		}
+		g1.Add(aggregate, aggregate, point)
+	}
+	return aggregate, nil
+}
+
+// AggregateSignatures combines the committee members' signatures over the same
+// message into one signature. The public keys of the signers are checked to be
+// valid and must be given in the same order as their signatures.
+func AggregateSignatures(sigs [][]byte, publicKeys [][]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return nil, errors.New("no signatures to aggregate")
+	}
+	if len(sigs) != len(publicKeys) {
+		return nil, fmt.Errorf("got %d signatures for %d public keys", len(sigs), len(publicKeys))
+	}
+	if _, err := aggregatePublicKeys(publicKeys); err != nil {
+		return nil, err
+	}
+
+	g2 := bls12381.NewG2()
+	aggregate := g2.Zero()
+	for i, sig := range sigs {
+		point, err := g2.FromCompressed(sig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature %d: %v", i, err)
+		}
+		g2.Add(aggregate, aggregate, point)
+	}
+	return g2.ToCompressed(aggregate), nil
+}
+
+// VerifyAggregateSignature checks that the aggregate signature was produced by
+// the holders of all the public keys signing the same message.
+func VerifyAggregateSignature(aggSig []byte, publicKeys [][]byte, message []byte) bool {
+	aggregateKey, err := aggregatePublicKeys(publicKeys)
+	if err != nil {
+		return false
+	}
+	g2 := bls12381.NewG2()
+	sig, err := g2.FromCompressed(aggSig)
+	if err != nil {
+		return false
+	}
+	point, err := g2.HashToCurve(message, blsDomain)
+	if err != nil {
+		return false
+	}
+
+	// e(aggregateKey, H(message)) == e(g1, sig)
+	engine := bls12381.NewEngine()
+	engine.AddPair(aggregateKey, point)
+	engine.AddPairInv(bls12381.NewG1().One(), sig)
+	return engine.Check()
+}
@@ -0,0 +1,155 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// makeBLSKeys returns deterministic BLS secret keys and their public keys.
+func makeBLSKeys(t *testing.T, n int) ([][]byte, [][]byte) {
+	secretKeys := make([][]byte, n)
+	publicKeys := make([][]byte, n)
+	for i := range secretKeys {
+		secretKeys[i] = common.BigToHash(big.NewInt(int64(1000 + i))).Bytes()
+		pub, err := BLSPublicKey(secretKeys[i])
+		if err != nil {
+			t.Fatalf("could not derive public key: %v", err)
+		}
+		publicKeys[i] = pub
+	}
+	return secretKeys, publicKeys
+}
+
+func signAll(t *testing.T, secretKeys [][]byte, message []byte) [][]byte {
+	sigs := make([][]byte, len(secretKeys))
+	for i, sk := range secretKeys {
+		sig, err := BLSSign(sk, message)
+		if err != nil {
+			t.Fatalf("could not sign message: %v", err)
+		}
+		sigs[i] = sig
+	}
+	return sigs
+}
+
+func TestAggregateSignatures_Verify(t *testing.T) {
+	secretKeys, publicKeys := makeBLSKeys(t, 4)
+	message := []byte("collation header")
+	sigs := signAll(t, secretKeys, message)
+
+	if !VerifyAggregateSignature(sigs[0], publicKeys[:1], message) {
+		t.Errorf("single signature should verify against its public key")
+	}
+
+	aggSig, err := AggregateSignatures(sigs, publicKeys)
+	if err != nil {
+		t.Fatalf("could not aggregate signatures: %v", err)
+	}
+	if len(aggSig) != blsSignatureLength {
+		t.Errorf("aggregate signature length incorrect. want=%d. got=%d", blsSignatureLength, len(aggSig))
+	}
+	if !VerifyAggregateSignature(aggSig, publicKeys, message) {
+		t.Errorf("aggregate signature should verify against all public keys")
+	}
+	if VerifyAggregateSignature(aggSig, publicKeys, []byte("other header")) {
+		t.Errorf("aggregate signature should not verify for another message")
+	}
+	if VerifyAggregateSignature(aggSig, publicKeys[:3], message) {
+		t.Errorf("aggregate signature should not verify without every signer's public key")
+	}
+
+	partial, err := AggregateSignatures(sigs[:3], publicKeys[:3])
+	if err != nil {
+		t.Fatalf("could not aggregate signatures: %v", err)
+	}
+	if !VerifyAggregateSignature(partial, publicKeys[:3], message) {
+		t.Errorf("aggregate of a subset of the committee should verify against its signers")
+	}
+}
+
+func TestAggregateSignatures_InvalidInputs(t *testing.T) {
+	secretKeys, publicKeys := makeBLSKeys(t, 2)
+	sigs := signAll(t, secretKeys, []byte("collation header"))
+
+	if _, err := AggregateSignatures(nil, nil); err == nil {
+		t.Errorf("aggregating no signatures should fail")
+	}
+	if _, err := AggregateSignatures(sigs, publicKeys[:1]); err == nil {
+		t.Errorf("aggregating with mismatched public keys should fail")
+	}
+	if _, err := AggregateSignatures([][]byte{sigs[0], make([]byte, 96)}, publicKeys); err == nil {
+		t.Errorf("aggregating a malformed signature should fail")
+	}
+	if _, err := AggregateSignatures(sigs, [][]byte{publicKeys[0], make([]byte, 48)}); err == nil {
+		t.Errorf("aggregating with a malformed public key should fail")
+	}
+	if VerifyAggregateSignature([]byte{1, 2, 3}, publicKeys, []byte("collation header")) {
+		t.Errorf("a malformed aggregate signature should not verify")
+	}
+	if VerifyAggregateSignature(sigs[0], nil, []byte("collation header")) {
+		t.Errorf("an aggregate signature should not verify without public keys")
+	}
+	if _, err := BLSSign(make([]byte, 32), []byte{}); err == nil {
+		t.Errorf("signing with a zero secret key should fail")
+	}
+	if _, err := BLSPublicKey(bytes.Repeat([]byte{0xff}, 32)); err == nil {
+		t.Errorf("secret keys beyond the group order should be rejected")
+	}
+}
+
+func TestCollationHeader_SetAggregateSignature(t *testing.T) {
+	chunkRoot := common.BytesToHash([]byte{1})
+	header := newTestCollationHeader(t, big.NewInt(1), &chunkRoot, big.NewInt(2))
+	unsignedHash := header.UnsignedHash()
+	signedHash := header.SignedHash()
+
+	secretKeys, publicKeys := makeBLSKeys(t, 3)
+	aggSig, err := AggregateSignatures(signAll(t, secretKeys, unsignedHash.Bytes()), publicKeys)
+	if err != nil {
+		t.Fatalf("could not aggregate signatures: %v", err)
+	}
+
+	if err := header.SetAggregateSignature(aggSig[:10]); err == nil {
+		t.Errorf("setting an aggregate signature with the wrong length should fail")
+	}
+	if err := header.SetAggregateSignature(aggSig); err != nil {
+		t.Fatalf("could not set aggregate signature: %v", err)
+	}
+	if !VerifyAggregateSignature(header.AggregateSig(), publicKeys, header.UnsignedHash().Bytes()) {
+		t.Errorf("header aggregate signature should verify against the committee")
+	}
+	if header.UnsignedHash() != unsignedHash {
+		t.Errorf("aggregate signature should not change the unsigned hash")
+	}
+	if header.SignedHash() == signedHash {
+		t.Errorf("aggregate signature should change the signed hash")
+	}
+
+	encoded, err := header.Encode()
+	if err != nil {
+		t.Fatalf("could not encode header: %v", err)
+	}
+	var decoded CollationHeader
+	if err := decoded.Decode(encoded); err != nil {
+		t.Fatalf("could not decode header: %v", err)
+	}
+	if !decoded.Equal(header) {
+		t.Errorf("RLP round tripped header does not match. want=%+v. got=%+v", header.data, decoded.data)
+	}
+
+	encodedJSON, err := json.Marshal(header)
+	if err != nil {
+		t.Fatalf("could not marshal header: %v", err)
+	}
+	var fromJSON CollationHeader
+	if err := json.Unmarshal(encodedJSON, &fromJSON); err != nil {
+		t.Fatalf("could not unmarshal header: %v", err)
+	}
+	if !fromJSON.Equal(header) {
+		t.Errorf("JSON round tripped header does not match. want=%+v. got=%+v", header.data, fromJSON.data)
+	}
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// CollationAnnouncement is gossiped by peers to announce the availability of a
+// collation without sending its body. It is the first message of the collation
+// fetch protocol: peers interested in the collation then request its body.
+type CollationAnnouncement struct {
+	HeaderHash common.Hash // the signed hash of the collation header.
+	ShardID    *big.Int    // the shard the collation belongs to.
+	Period     *big.Int    // the period the collation was proposed in.
+	BodySize   uint64      // the size in bytes of the collation body.
+}
+
+// CollationAnnouncementFromHeader creates the announcement of a collation
+// given its header and the size of its body.
+func CollationAnnouncementFromHeader(h *CollationHeader, bodySize uint64) *CollationAnnouncement {
+	return &CollationAnnouncement{
+		HeaderHash: h.SignedHash(),
+		ShardID:    h.ShardID(),
+		Period:     h.Period(),
+		BodySize:   bodySize,
+	}
+}
+
+// Encode RLP encodes the announcement to be sent over the wire.
+func (a *CollationAnnouncement) Encode() ([]byte, error) {
+	if a.ShardID == nil || a.Period == nil {
+		return nil, errors.New("announcement needs a shardID and period")
+	}
+	return rlp.EncodeToBytes(a)
+}
+
+// DecodeCollationAnnouncement decodes an RLP encoded collation announcement.
+func DecodeCollationAnnouncement(data []byte) (*CollationAnnouncement, error) {
+	a := &CollationAnnouncement{}
+	if err := rlp.DecodeBytes(data, a); err != nil {
+		return nil, fmt.Errorf("could not decode collation announcement: %v", err)
+	}
+	return a, nil
+}
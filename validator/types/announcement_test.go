@@ -0,0 +1,45 @@
+package types
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestCollationAnnouncement_EncodeDecode(t *testing.T) {
+	c := makeStoredCollation(t, 3, 7)
+	announcement := CollationAnnouncementFromHeader(c.Header(), uint64(len(c.Body())))
+	if announcement.HeaderHash != c.Header().SignedHash() {
+		t.Errorf("announcement header hash incorrect. want=%x. got=%x", c.Header().SignedHash(), announcement.HeaderHash)
+	}
+
+	encoded, err := announcement.Encode()
+	if err != nil {
+		t.Fatalf("could not encode announcement: %v", err)
+	}
+	decoded, err := DecodeCollationAnnouncement(encoded)
+	if err != nil {
+		t.Fatalf("could not decode announcement: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, announcement) {
+		t.Errorf("decoded announcement does not match. want=%+v. got=%+v", announcement, decoded)
+	}
+}
+
+func TestCollationAnnouncement_Invalid(t *testing.T) {
+	if _, err := (&CollationAnnouncement{Period: big.NewInt(1)}).Encode(); err == nil {
+		t.Errorf("encoding an announcement without a shardID should fail")
+	}
+
+	announcement := &CollationAnnouncement{ShardID: big.NewInt(1), Period: big.NewInt(1), BodySize: 64}
+	encoded, err := announcement.Encode()
+	if err != nil {
+		t.Fatalf("could not encode announcement: %v", err)
+	}
+	if _, err := DecodeCollationAnnouncement(encoded[:len(encoded)-1]); err == nil {
+		t.Errorf("decoding a truncated announcement should fail")
+	}
+	if _, err := DecodeCollationAnnouncement(append(encoded, 0x01)); err == nil {
+		t.Errorf("decoding an announcement with trailing bytes should fail")
+	}
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// AvailabilitySample randomly selects sampleCount distinct chunks of the
+// collation body and returns their indices in ascending order together with
+// the chunks. Light clients request such samples, along with the proofs of
+// ChunkProof, to gain confidence that a whole body is available without
+// downloading it.
+func AvailabilitySample(collation *Collation, sampleCount int, rng *rand.Rand) ([]int, [][]byte, error) {
+	chunks := ChunksFromBody(collation.Body())
+	if chunks.Len() == 0 {
+		return nil, nil, fmt.Errorf("cannot sample chunks: %w", ErrEmptyBody)
+	}
+	if sampleCount <= 0 || sampleCount > chunks.Len() {
+		return nil, nil, fmt.Errorf("sample count %d out of range for body with %d chunks", sampleCount, chunks.Len())
+	}
+
+	indices := rng.Perm(chunks.Len())[:sampleCount]
+	sort.Ints(indices)
+	sampled := make([][]byte, len(indices))
+	for i, index := range indices {
+		sampled[i] = chunks.chunk(index)
+	}
+	return indices, sampled, nil
+}
+
+// VerifyAvailabilitySample checks every sampled chunk against the header's
+// chunk root using its Merkle proof, with indices, chunks and proofs matched
+// by position. An empty sample proves nothing and does not verify. Every chunk
+// must be a full chunk and every proof must reach the depth of the chunk tree,
+// so that a peer holding only inner nodes of the tree cannot pass as holding
+// the data.
+func VerifyAvailabilitySample(header *CollationHeader, indices []int, chunks [][]byte, proofs [][][]byte) bool {
+	if header == nil || header.ChunkRoot() == nil {
+		return false
+	}
+	if len(indices) == 0 || len(indices) != len(chunks) || len(indices) != len(proofs) {
+		return false
+	}
+	// the header does not record the chunk count, but all leaves of the
+	// chunk tree are at the same depth, so every proof must be as long as the
+	// longest one.
+	depth := 0
+	for _, proof := range proofs {
+		if len(proof) > depth {
+			depth = len(proof)
+		}
+	}
+	for i, index := range indices {
+		if len(chunks[i]) != chunkSize || len(proofs[i]) != depth {
+			return false
+		}
+		if !VerifyChunkProof(*header.ChunkRoot(), index, chunks[i], proofs[i]) {
+			return false
+		}
+	}
+	return true
+}
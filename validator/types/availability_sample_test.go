@@ -0,0 +1,149 @@
+package types
+
+import (
+	"math/big"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// sampleProofs returns the chunk proofs of the sampled indices.
+func sampleProofs(t *testing.T, c *Collation, indices []int) [][][]byte {
+	proofs := make([][][]byte, len(indices))
+	for i, index := range indices {
+		proof, err := c.ChunkProof(index)
+		if err != nil {
+			t.Fatalf("could not generate proof of chunk %d: %v", index, err)
+		}
+		for _, sibling := range proof {
+			proofs[i] = append(proofs[i], sibling.Bytes())
+		}
+	}
+	return proofs
+}
+
+// mustProof returns the proof of the leaf at index in the tree.
+func mustProof(t *testing.T, tree *chunkTree, index int) []common.Hash {
+	proof, err := tree.proof(index)
+	if err != nil {
+		t.Fatalf("could not generate proof of chunk %d: %v", index, err)
+	}
+	return proof
+}
+
+func TestAvailabilitySample(t *testing.T) {
+	c := makeStoredCollation(t, 1, 10)
+	rng := rand.New(rand.NewSource(1))
+
+	indices, chunks, err := AvailabilitySample(c, 8, rng)
+	if err != nil {
+		t.Fatalf("could not sample chunks: %v", err)
+	}
+	if len(indices) != 8 || len(chunks) != 8 {
+		t.Fatalf("sample size incorrect. want=%d. got=%d indices, %d chunks", 8, len(indices), len(chunks))
+	}
+	if !sort.IntsAreSorted(indices) {
+		t.Errorf("sampled indices should be sorted. got=%v", indices)
+	}
+	for i, index := range indices {
+		if i > 0 && indices[i-1] == index {
+			t.Errorf("chunk %d sampled more than once", index)
+		}
+		chunk, err := c.GetChunk(index)
+		if err != nil {
+			t.Fatalf("could not get chunk %d: %v", index, err)
+		}
+		if !reflect.DeepEqual(chunks[i], chunk) {
+			t.Errorf("sampled chunk %d incorrect. want=%#x. got=%#x", index, chunk, chunks[i])
+		}
+	}
+
+	again, _, err := AvailabilitySample(c, 8, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("could not sample chunks: %v", err)
+	}
+	if !reflect.DeepEqual(indices, again) {
+		t.Errorf("samples from the same seed should match. want=%v. got=%v", indices, again)
+	}
+
+	all, _, err := AvailabilitySample(c, c.ChunkCount(), rng)
+	if err != nil {
+		t.Fatalf("could not sample every chunk: %v", err)
+	}
+	for i, index := range all {
+		if index != i {
+			t.Fatalf("sampling every chunk should return every index. got=%v", all)
+		}
+	}
+}
+
+func TestAvailabilitySample_Invalid(t *testing.T) {
+	c := makeStoredCollation(t, 1, 10)
+	rng := rand.New(rand.NewSource(1))
+	for _, count := range []int{0, -1, c.ChunkCount() + 1} {
+		if _, _, err := AvailabilitySample(c, count, rng); err == nil {
+			t.Errorf("sampling %d chunks of %d should fail", count, c.ChunkCount())
+		}
+	}
+	empty := NewCollation(newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1)), nil, nil)
+	if _, _, err := AvailabilitySample(empty, 1, rng); err == nil {
+		t.Errorf("sampling an empty body should fail")
+	}
+}
+
+func TestVerifyAvailabilitySample(t *testing.T) {
+	c := makeStoredCollation(t, 1, 10)
+	indices, chunks, err := AvailabilitySample(c, 5, rand.New(rand.NewSource(2)))
+	if err != nil {
+		t.Fatalf("could not sample chunks: %v", err)
+	}
+	proofs := sampleProofs(t, c, indices)
+	if !VerifyAvailabilitySample(c.Header(), indices, chunks, proofs) {
+		t.Fatalf("valid sample failed verification")
+	}
+
+	tampered := append([][]byte(nil), chunks...)
+	tampered[2] = append([]byte{}, chunks[2]...)
+	tampered[2][0] ^= 0xff
+	shifted := append([]int(nil), indices...)
+	shifted[0] = indices[1]
+	other := makeStoredCollation(t, 1, 11)
+
+	// a peer holding only the parents of the leaves passes their pairs off as
+	// chunks, with proofs one level short.
+	tree := newChunkTree(ChunksFromBody(c.Body()))
+	forgedChunks := [][]byte{append(tree.layers[0][0].Bytes(), tree.layers[0][1].Bytes()...)}
+	forgedProofs := [][][]byte{proofToBytes(mustProof(t, tree, 0)[1:])}
+	shortened := append([][][]byte(nil), proofs...)
+	shortened[0] = shortened[0][:len(shortened[0])-1]
+	truncated := append([][]byte(nil), chunks...)
+	truncated[1] = chunks[1][:chunkSize-1]
+
+	tests := []struct {
+		name    string
+		header  *CollationHeader
+		indices []int
+		chunks  [][]byte
+		proofs  [][][]byte
+	}{
+		{name: "tampered chunk", header: c.Header(), indices: indices, chunks: tampered, proofs: proofs},
+		{name: "wrong index", header: c.Header(), indices: shifted, chunks: chunks, proofs: proofs},
+		{name: "missing proof", header: c.Header(), indices: indices, chunks: chunks, proofs: proofs[1:]},
+		{name: "missing chunk", header: c.Header(), indices: indices, chunks: chunks[1:], proofs: proofs},
+		{name: "empty sample", header: c.Header()},
+		{name: "forged inner nodes", header: c.Header(), indices: []int{0}, chunks: forgedChunks, proofs: forgedProofs},
+		{name: "short proof", header: c.Header(), indices: indices, chunks: chunks, proofs: shortened},
+		{name: "short chunk", header: c.Header(), indices: indices, chunks: truncated, proofs: proofs},
+		{name: "other collation", header: other.Header(), indices: indices, chunks: chunks, proofs: proofs},
+		{name: "no header"},
+		{name: "no chunk root", header: newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(10)), indices: indices, chunks: chunks, proofs: proofs},
+	}
+	for _, tt := range tests {
+		if VerifyAvailabilitySample(tt.header, tt.indices, tt.chunks, tt.proofs) {
+			t.Errorf("%s: sample should fail verification", tt.name)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+var (
+	// blockIndexPrefix keys the collation hash and indexing period of a
+	// mainchain block hash and shardID.
+	blockIndexPrefix = []byte("i")
+	// blockIndexPeriodPrefix keys the block hash and shardID pairs indexed in a
+	// period, so that the entries of old periods can be found when flushing.
+	blockIndexPeriodPrefix = []byte("p")
+	// blockIndexCurrentPeriodKey holds the period new entries are indexed in.
+	blockIndexCurrentPeriodKey = []byte("current")
+)
+
+// BlockCollationIndex maps mainchain block hashes to the collations submitted
+// for each shard in the period of the block, persisted in LevelDB. Entries are
+// recorded in the current period, which FlushPeriod advances while deleting
+// the entries of periods older than the retention.
+type BlockCollationIndex struct {
+	db        *ethdb.LDBDatabase
+	retention *big.Int
+	period    *big.Int
+	lock      sync.RWMutex
+}
+
+// NewBlockCollationIndex opens or creates a block to collation index at path,
+// keeping the entries of the last retention periods when flushing.
+func NewBlockCollationIndex(path string, retention int64) (*BlockCollationIndex, error) {
+	if retention < 0 {
+		return nil, fmt.Errorf("retention %d must not be negative", retention)
+	}
+	db, err := ethdb.NewLDBDatabase(path, 16, 16)
+	if err != nil {
+		return nil, fmt.Errorf("could not open block collation index: %v", err)
+	}
+	period := new(big.Int)
+	enc, err := db.Get(blockIndexCurrentPeriodKey)
+	if err == nil {
+		period.SetBytes(enc)
+	}
+	return &BlockCollationIndex{
+		db:        db,
+		retention: big.NewInt(retention),
+		period:    period,
+	}, nil
+}
+
+// Close closes the underlying LevelDB database.
+func (b *BlockCollationIndex) Close() {
+	b.db.Close()
+}
+
+// blockIndexKey is the key of the entry of the block hash and shardID.
+func blockIndexKey(blockHash common.Hash, shardID *big.Int) []byte {
+	key := make([]byte, 0, len(blockIndexPrefix)+2*common.HashLength)
+	key = append(key, blockIndexPrefix...)
+	key = append(key, blockHash.Bytes()...)
+	return append(key, common.BigToHash(shardID).Bytes()...)
+}
+
+// blockIndexPeriodKey is the key listing the entry of the block hash and
+// shardID under the period it was indexed in. Periods are encoded as 32 byte
+// big-endian values so that the keys of older periods sort first.
+func blockIndexPeriodKey(period *big.Int, blockHash common.Hash, shardID *big.Int) []byte {
+	key := make([]byte, 0, len(blockIndexPeriodPrefix)+3*common.HashLength)
+	key = append(key, blockIndexPeriodPrefix...)
+	key = append(key, common.BigToHash(period).Bytes()...)
+	key = append(key, blockHash.Bytes()...)
+	return append(key, common.BigToHash(shardID).Bytes()...)
+}
+
+// Index records the collation submitted for the shard in the period of the
+// mainchain block, replacing any collation indexed for them before.
+func (b *BlockCollationIndex) Index(blockHash common.Hash, shardID *big.Int, collationHash common.Hash) error {
+	if shardID == nil || shardID.Sign() < 0 {
+		return fmt.Errorf("invalid shardID %v: %w", shardID, ErrInvalidShardID)
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	key := blockIndexKey(blockHash, shardID)
+	batch := b.db.NewBatch()
+	// drop the period listing of a replaced entry so that flushing its old
+	// period does not delete the new entry.
+	if old, err := b.db.Get(key); err == nil && len(old) == 2*common.HashLength {
+		oldPeriod := new(big.Int).SetBytes(old[common.HashLength:])
+		if err := batch.Delete(blockIndexPeriodKey(oldPeriod, blockHash, shardID)); err != nil {
+			return err
+		}
+	}
+	value := append(collationHash.Bytes(), common.BigToHash(b.period).Bytes()...)
+	if err := batch.Put(key, value); err != nil {
+		return err
+	}
+	if err := batch.Put(blockIndexPeriodKey(b.period, blockHash, shardID), nil); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// LookupByBlock returns the hash of the collation indexed for the shard and
+// mainchain block, and false if there is none.
+func (b *BlockCollationIndex) LookupByBlock(blockHash common.Hash, shardID *big.Int) (common.Hash, bool) {
+	if shardID == nil || shardID.Sign() < 0 {
+		return common.Hash{}, false
+	}
+
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	value, err := b.db.Get(blockIndexKey(blockHash, shardID))
+	if err != nil || len(value) != 2*common.HashLength {
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(value[:common.HashLength]), true
+}
+
+// FlushPeriod advances the index to the period, recording new entries in it,
+// and deletes the entries indexed in periods more than the retention before
+// it. Periods before the current one are ignored.
+func (b *BlockCollationIndex) FlushPeriod(period *big.Int) {
+	if err := b.flush(period); err != nil {
+		log.Errorf("Could not flush block collation index to period %v: %v", period, err)
+	}
+}
+
+// flush advances the index to the period and deletes the expired entries.
+func (b *BlockCollationIndex) flush(period *big.Int) error {
+	if period == nil {
+		return errors.New("no period to flush to")
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if period.Cmp(b.period) < 0 {
+		return fmt.Errorf("period %v is before the current period %v: %w", period, b.period, ErrInvalidPeriod)
+	}
+	cutoff := new(big.Int).Sub(period, b.retention)
+	if cutoff.Sign() < 0 {
+		cutoff.SetInt64(0)
+	}
+	cutoffKey := common.BigToHash(cutoff).Bytes()
+
+	batch := b.db.NewBatch()
+	it := b.db.NewIteratorWithPrefix(blockIndexPeriodPrefix)
+	for it.Next() {
+		key := it.Key()
+		rest := key[len(blockIndexPeriodPrefix):]
+		if len(rest) != 3*common.HashLength {
+			continue
+		}
+		// keys are sorted by period, so every later key is retained.
+		if bytes.Compare(rest[:common.HashLength], cutoffKey) >= 0 {
+			break
+		}
+		blockHash := common.BytesToHash(rest[common.HashLength : 2*common.HashLength])
+		shardID := new(big.Int).SetBytes(rest[2*common.HashLength:])
+		if err := batch.Delete(blockIndexKey(blockHash, shardID)); err != nil {
+			it.Release()
+			return err
+		}
+		if err := batch.Delete(common.CopyBytes(key)); err != nil {
+			it.Release()
+			return err
+		}
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("could not iterate block collation index: %v", err)
+	}
+	if err := batch.Put(blockIndexCurrentPeriodKey, period.Bytes()); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	b.period = new(big.Int).Set(period)
+	return nil
+}
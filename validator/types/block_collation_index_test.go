@@ -0,0 +1,135 @@
+package types
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func setupBlockCollationIndex(t *testing.T, retention int64) (*BlockCollationIndex, string, func()) {
+	dir, err := ioutil.TempDir("", "blockcollationindex")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	index, err := NewBlockCollationIndex(dir, retention)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("could not create block collation index: %v", err)
+	}
+	return index, dir, func() {
+		index.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func testBlockHash(i int) common.Hash {
+	return crypto.Keccak256Hash([]byte("block"), big.NewInt(int64(i)).Bytes())
+}
+
+func testCollationHash(block int, shardID int64) common.Hash {
+	return crypto.Keccak256Hash([]byte("collation"), big.NewInt(int64(block)).Bytes(), big.NewInt(shardID).Bytes())
+}
+
+func TestBlockCollationIndex_Lookup(t *testing.T) {
+	index, _, teardown := setupBlockCollationIndex(t, 10)
+	defer teardown()
+
+	shards := []int64{0, 1}
+	for i := 0; i < 100; i++ {
+		for _, shardID := range shards {
+			if err := index.Index(testBlockHash(i), big.NewInt(shardID), testCollationHash(i, shardID)); err != nil {
+				t.Fatalf("could not index block %d: %v", i, err)
+			}
+		}
+	}
+	for i := 0; i < 100; i++ {
+		for _, shardID := range shards {
+			got, ok := index.LookupByBlock(testBlockHash(i), big.NewInt(shardID))
+			if !ok {
+				t.Fatalf("block %d of shard %d not found", i, shardID)
+			}
+			if want := testCollationHash(i, shardID); got != want {
+				t.Errorf("collation of block %d and shard %d incorrect. want=%s. got=%s", i, shardID, want.Hex(), got.Hex())
+			}
+		}
+	}
+
+	if _, ok := index.LookupByBlock(testBlockHash(100), big.NewInt(0)); ok {
+		t.Errorf("block that was not indexed should not be found")
+	}
+	if _, ok := index.LookupByBlock(testBlockHash(0), big.NewInt(2)); ok {
+		t.Errorf("shard that was not indexed should not be found")
+	}
+	if err := index.Index(testBlockHash(0), nil, common.Hash{}); err == nil {
+		t.Errorf("indexing a block without a shardID should fail")
+	}
+}
+
+func TestBlockCollationIndex_FlushPeriod(t *testing.T) {
+	index, _, teardown := setupBlockCollationIndex(t, 2)
+	defer teardown()
+
+	// index 10 blocks in every period from 0 to 9.
+	for period := 0; period < 10; period++ {
+		index.FlushPeriod(big.NewInt(int64(period)))
+		for i := period * 10; i < (period+1)*10; i++ {
+			if err := index.Index(testBlockHash(i), big.NewInt(1), testCollationHash(i, 1)); err != nil {
+				t.Fatalf("could not index block %d: %v", i, err)
+			}
+		}
+	}
+	// the blocks of period 0 are reindexed in period 9, so they outlive it.
+	for i := 0; i < 10; i++ {
+		if err := index.Index(testBlockHash(i), big.NewInt(1), testCollationHash(i, 1)); err != nil {
+			t.Fatalf("could not reindex block %d: %v", i, err)
+		}
+	}
+
+	index.FlushPeriod(big.NewInt(10))
+	for i := 0; i < 100; i++ {
+		_, ok := index.LookupByBlock(testBlockHash(i), big.NewInt(1))
+		if want := i < 10 || i >= 80; ok != want {
+			t.Errorf("block %d indexed in period %d found after flushing to period 10. want=%v. got=%v", i, i/10, want, ok)
+		}
+	}
+
+	// flushing to an earlier period is ignored.
+	index.FlushPeriod(big.NewInt(3))
+	if _, ok := index.LookupByBlock(testBlockHash(80), big.NewInt(1)); !ok {
+		t.Errorf("flushing to an earlier period should not delete entries")
+	}
+}
+
+func TestBlockCollationIndex_Persistence(t *testing.T) {
+	index, dir, _ := setupBlockCollationIndex(t, 1)
+	defer os.RemoveAll(dir)
+
+	index.FlushPeriod(big.NewInt(5))
+	if err := index.Index(testBlockHash(1), big.NewInt(0), testCollationHash(1, 0)); err != nil {
+		t.Fatalf("could not index block: %v", err)
+	}
+	index.Close()
+
+	index, err := NewBlockCollationIndex(dir, 1)
+	if err != nil {
+		t.Fatalf("could not reopen block collation index: %v", err)
+	}
+	defer index.Close()
+	if got, ok := index.LookupByBlock(testBlockHash(1), big.NewInt(0)); !ok || got != testCollationHash(1, 0) {
+		t.Errorf("indexed collation should survive reopening. want=%s. got=%s", testCollationHash(1, 0).Hex(), got.Hex())
+	}
+
+	// the entry was indexed in period 5, which reopening kept as current.
+	index.FlushPeriod(big.NewInt(6))
+	if _, ok := index.LookupByBlock(testBlockHash(1), big.NewInt(0)); !ok {
+		t.Errorf("entry of period 5 should be retained in period 6")
+	}
+	index.FlushPeriod(big.NewInt(7))
+	if _, ok := index.LookupByBlock(testBlockHash(1), big.NewInt(0)); ok {
+		t.Errorf("entry of period 5 should be flushed in period 7")
+	}
+}
@@ -0,0 +1,170 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// BodyRequest asks a peer for the body of an announced collation.
+type BodyRequest struct {
+	HeaderHash common.Hash // the signed hash of the collation header.
+}
+
+// Encode RLP encodes the request to be sent over the wire.
+func (r *BodyRequest) Encode() ([]byte, error) {
+	return rlp.EncodeToBytes(r)
+}
+
+// DecodeBodyRequest decodes an RLP encoded body request.
+func DecodeBodyRequest(data []byte) (*BodyRequest, error) {
+	r := &BodyRequest{}
+	if err := rlp.DecodeBytes(data, r); err != nil {
+		return nil, fmt.Errorf("could not decode body request: %v", err)
+	}
+	return r, nil
+}
+
+// BodyResponse answers a BodyRequest with the requested collation body.
+type BodyResponse struct {
+	HeaderHash common.Hash // the signed hash of the collation header.
+	Body       []byte      // the serialized collation body.
+}
+
+// Encode RLP encodes the response to be sent over the wire.
+func (r *BodyResponse) Encode() ([]byte, error) {
+	return rlp.EncodeToBytes(r)
+}
+
+// DecodeBodyResponse decodes an RLP encoded body response.
+func DecodeBodyResponse(data []byte) (*BodyResponse, error) {
+	r := &BodyResponse{}
+	if err := rlp.DecodeBytes(data, r); err != nil {
+		return nil, fmt.Errorf("could not decode body response: %v", err)
+	}
+	return r, nil
+}
+
+// DefaultBodyRequestTimeout is how long a BodyFetcher waits for a response
+// before the request expires and the body can be requested again.
+const DefaultBodyRequestTimeout = 30 * time.Second
+
+// BodyFetcher requests collation bodies from peers and matches their responses
+// with the pending requests. Bodies are handed over to the callback registered
+// through OnBody. Requests that are not answered within the timeout expire.
+type BodyFetcher struct {
+	send    func(peer string, req *BodyRequest) error
+	onBody  func(hash common.Hash, body []byte) error
+	timeout time.Duration
+	pending map[common.Hash]*bodyRequest
+	lock    sync.Mutex
+}
+
+// bodyRequest is a request waiting for a response.
+type bodyRequest struct {
+	peer     string
+	deadline time.Time
+}
+
+// NewBodyFetcher creates a BodyFetcher sending its requests with send.
+func NewBodyFetcher(send func(peer string, req *BodyRequest) error) *BodyFetcher {
+	return &BodyFetcher{
+		send:    send,
+		timeout: DefaultBodyRequestTimeout,
+		pending: make(map[common.Hash]*bodyRequest),
+	}
+}
+
+// OnBody sets the callback receiving the bodies delivered for pending requests.
+// An error returned by the callback is returned from Deliver.
+func (f *BodyFetcher) OnBody(callback func(hash common.Hash, body []byte) error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.onBody = callback
+}
+
+// SetTimeout sets how long requests made from now on wait for a response.
+func (f *BodyFetcher) SetTimeout(timeout time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.timeout = timeout
+}
+
+// Request asks the peer for the body of the collation with the given header hash.
+// Only one request can be pending per collation until it expires.
+func (f *BodyFetcher) Request(peer string, hash common.Hash) error {
+	f.lock.Lock()
+	f.expire(time.Now())
+	if _, ok := f.pending[hash]; ok {
+		f.lock.Unlock()
+		return fmt.Errorf("body of collation %s is already requested", hash.Hex())
+	}
+	req := &bodyRequest{peer: peer, deadline: time.Now().Add(f.timeout)}
+	f.pending[hash] = req
+	f.lock.Unlock()
+
+	if err := f.send(peer, &BodyRequest{HeaderHash: hash}); err != nil {
+		f.lock.Lock()
+		if f.pending[hash] == req {
+			delete(f.pending, hash)
+		}
+		f.lock.Unlock()
+		return fmt.Errorf("could not request body from peer %s: %v", peer, err)
+	}
+	return nil
+}
+
+// Deliver resolves the pending request answered by the peer's response.
+// Responses that were not requested from that peer, or arrive after the
+// request expired, are rejected.
+func (f *BodyFetcher) Deliver(peerID string, resp *BodyResponse) error {
+	if resp == nil {
+		return errors.New("no body response provided")
+	}
+
+	f.lock.Lock()
+	f.expire(time.Now())
+	req, ok := f.pending[resp.HeaderHash]
+	if !ok || req.peer != peerID {
+		f.lock.Unlock()
+		return fmt.Errorf("body of collation %s was not requested from peer %s", resp.HeaderHash.Hex(), peerID)
+	}
+	delete(f.pending, resp.HeaderHash)
+	onBody := f.onBody
+	f.lock.Unlock()
+
+	if onBody == nil {
+		return nil
+	}
+	return onBody(resp.HeaderHash, resp.Body)
+}
+
+// Pending returns the number of requests waiting for a response.
+func (f *BodyFetcher) Pending() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.expire(time.Now())
+	return len(f.pending)
+}
+
+// requested reports whether a request for the body is waiting for a response.
+func (f *BodyFetcher) requested(hash common.Hash) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.expire(time.Now())
+	_, ok := f.pending[hash]
+	return ok
+}
+
+// expire drops the requests whose deadline passed. The caller must hold the lock.
+func (f *BodyFetcher) expire(now time.Time) {
+	for hash, req := range f.pending {
+		if now.After(req.deadline) {
+			delete(f.pending, hash)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package types
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type sentRequest struct {
+	peer string
+	req  *BodyRequest
+}
+
+func newTestBodyFetcher() (*BodyFetcher, *[]sentRequest) {
+	var sent []sentRequest
+	fetcher := NewBodyFetcher(func(peer string, req *BodyRequest) error {
+		sent = append(sent, sentRequest{peer: peer, req: req})
+		return nil
+	})
+	return fetcher, &sent
+}
+
+func TestBodyMessages_EncodeDecode(t *testing.T) {
+	req := &BodyRequest{HeaderHash: common.BytesToHash([]byte{1, 2})}
+	encoded, err := req.Encode()
+	if err != nil {
+		t.Fatalf("could not encode body request: %v", err)
+	}
+	decodedReq, err := DecodeBodyRequest(encoded)
+	if err != nil {
+		t.Fatalf("could not decode body request: %v", err)
+	}
+	if !reflect.DeepEqual(decodedReq, req) {
+		t.Errorf("decoded body request does not match. want=%+v. got=%+v", req, decodedReq)
+	}
+
+	resp := &BodyResponse{HeaderHash: req.HeaderHash, Body: []byte{3, 4, 5}}
+	encoded, err = resp.Encode()
+	if err != nil {
+		t.Fatalf("could not encode body response: %v", err)
+	}
+	decodedResp, err := DecodeBodyResponse(encoded)
+	if err != nil {
+		t.Fatalf("could not decode body response: %v", err)
+	}
+	if !reflect.DeepEqual(decodedResp, resp) {
+		t.Errorf("decoded body response does not match. want=%+v. got=%+v", resp, decodedResp)
+	}
+
+	if _, err := DecodeBodyRequest(encoded); err == nil {
+		t.Errorf("decoding a response as a request should fail")
+	}
+	if _, err := DecodeBodyResponse(encoded[:len(encoded)-1]); err == nil {
+		t.Errorf("decoding a truncated response should fail")
+	}
+}
+
+func TestBodyFetcher_RequestDeliver(t *testing.T) {
+	fetcher, sent := newTestBodyFetcher()
+	hash := common.BytesToHash([]byte{1})
+
+	var delivered []byte
+	fetcher.OnBody(func(h common.Hash, body []byte) error {
+		delivered = body
+		return nil
+	})
+
+	if err := fetcher.Request("peer1", hash); err != nil {
+		t.Fatalf("could not request body: %v", err)
+	}
+	if len(*sent) != 1 || (*sent)[0].peer != "peer1" || (*sent)[0].req.HeaderHash != hash {
+		t.Errorf("body request was not sent to the peer. got=%v", *sent)
+	}
+	if err := fetcher.Request("peer2", hash); err == nil {
+		t.Errorf("requesting a pending body twice should fail")
+	}
+
+	if err := fetcher.Deliver("peer2", &BodyResponse{HeaderHash: hash, Body: []byte{1}}); err == nil {
+		t.Errorf("delivering a body from a peer it was not requested from should fail")
+	}
+	if err := fetcher.Deliver("peer1", &BodyResponse{HeaderHash: hash, Body: []byte{1}}); err != nil {
+		t.Fatalf("could not deliver body: %v", err)
+	}
+	if !reflect.DeepEqual(delivered, []byte{1}) {
+		t.Errorf("delivered body incorrect. want=%x. got=%x", []byte{1}, delivered)
+	}
+	if fetcher.Pending() != 0 {
+		t.Errorf("delivered requests should not be pending. got=%d", fetcher.Pending())
+	}
+	if err := fetcher.Deliver("peer1", &BodyResponse{HeaderHash: hash, Body: []byte{1}}); err == nil {
+		t.Errorf("delivering a body twice should fail")
+	}
+}
+
+func TestBodyFetcher_SendFailure(t *testing.T) {
+	fetcher := NewBodyFetcher(func(peer string, req *BodyRequest) error {
+		return errors.New("peer disconnected")
+	})
+	hash := common.BytesToHash([]byte{1})
+	if err := fetcher.Request("peer1", hash); err == nil {
+		t.Errorf("request should fail if it cannot be sent")
+	}
+	if fetcher.Pending() != 0 {
+		t.Errorf("failed requests should not stay pending. got=%d", fetcher.Pending())
+	}
+}
+
+func TestBodyFetcher_RequestExpiry(t *testing.T) {
+	fetcher, sent := newTestBodyFetcher()
+	fetcher.SetTimeout(time.Millisecond)
+	hash := common.BytesToHash([]byte{1})
+
+	if err := fetcher.Request("peer1", hash); err != nil {
+		t.Fatalf("could not request body: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if fetcher.Pending() != 0 {
+		t.Errorf("expired requests should not be pending. got=%d", fetcher.Pending())
+	}
+	if err := fetcher.Deliver("peer1", &BodyResponse{HeaderHash: hash, Body: []byte{1}}); err == nil {
+		t.Errorf("delivering a body for an expired request should fail")
+	}
+	if err := fetcher.Request("peer2", hash); err != nil {
+		t.Fatalf("could not request body again after the request expired: %v", err)
+	}
+	if len(*sent) != 2 || (*sent)[1].peer != "peer2" {
+		t.Errorf("expired request should be sent to the new peer. got=%v", *sent)
+	}
+}
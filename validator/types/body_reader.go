@@ -0,0 +1,87 @@
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/prysmaticlabs/prysm/shared/shardutil"
+)
+
+// BodyReader reads the blobs of a serialized collation body one at a time,
+// so that bodies can be decoded without holding them in memory.
+type BodyReader interface {
+	// ReadBlob returns the RLP encoded data of the next blob of the body. It
+	// returns io.EOF once there are no blobs left to read.
+	ReadBlob() ([]byte, error)
+	io.Closer
+}
+
+// streamBodyReader reads the blobs of a serialized body from a stream.
+type streamBodyReader struct {
+	r      io.Reader
+	closer io.Closer
+}
+
+// BytesBodyReader creates a BodyReader over a serialized body held in memory.
+func BytesBodyReader(data []byte) BodyReader {
+	return &streamBodyReader{r: bytes.NewReader(data)}
+}
+
+// FileBodyReader creates a BodyReader over the serialized body stored in the
+// file at path. The file is closed when the reader is closed.
+func FileBodyReader(path string) (BodyReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open collation body: %v", err)
+	}
+	return &streamBodyReader{r: bufio.NewReader(f), closer: f}, nil
+}
+
+// ReadBlob implements BodyReader.
+func (sr *streamBodyReader) ReadBlob() ([]byte, error) {
+	blob, err := shardutil.ReadRawBlob(sr.r)
+	if err != nil {
+		return nil, err
+	}
+	return blob.Data(), nil
+}
+
+// Close implements io.Closer.
+func (sr *streamBodyReader) Close() error {
+	if sr.closer == nil {
+		return nil
+	}
+	return sr.closer.Close()
+}
+
+// CollationBodyReader decodes the transactions of a serialized collation body
+// from a stream one at a time, avoiding buffering the entire body in memory.
+type CollationBodyReader struct {
+	blobs BodyReader
+}
+
+// NewCollationBodyReader creates a reader decoding transactions from the serialized body in r.
+func NewCollationBodyReader(r io.Reader) *CollationBodyReader {
+	return &CollationBodyReader{blobs: &streamBodyReader{r: r}}
+}
+
+// NextTransaction decodes the next transaction from the body. It returns io.EOF
+// once there are no transactions left to read.
+func (br *CollationBodyReader) NextTransaction() (*gethTypes.Transaction, error) {
+	data, err := br.blobs.ReadBlob()
+	if err != nil {
+		return nil, err
+	}
+
+	tx := gethTypes.NewTransaction(0, common.HexToAddress("0x"), nil, 0, nil, nil)
+	if err := rlp.DecodeBytes(data, tx); err != nil {
+		return nil, fmt.Errorf("creation of transactions from raw blobs failed: %v", err)
+	}
+	return tx, nil
+}
@@ -0,0 +1,198 @@
+package types
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/prysmaticlabs/prysm/shared/shardutil"
+)
+
+// knownBody is a serialized body of two blobs: {1, 2, 3} in a single terminal
+// chunk, and the bytes 0 to 31 spread over a non-terminal and a terminal chunk.
+func knownBody() ([]byte, [][]byte) {
+	body := make([]byte, 3*chunkSize)
+	body[0] = 3
+	copy(body[1:], []byte{1, 2, 3})
+	long := make([]byte, chunkSize)
+	for i := range long {
+		long[i] = byte(i)
+	}
+	copy(body[chunkSize+1:], long[:chunkDataSize])
+	body[2*chunkSize] = 1
+	body[2*chunkSize+1] = long[chunkDataSize]
+	return body, [][]byte{{1, 2, 3}, long}
+}
+
+func readBlobs(t *testing.T, r BodyReader) [][]byte {
+	var blobs [][]byte
+	for {
+		blob, err := r.ReadBlob()
+		if err == io.EOF {
+			return blobs
+		}
+		if err != nil {
+			t.Fatalf("could not read blob %d: %v", len(blobs), err)
+		}
+		blobs = append(blobs, blob)
+	}
+}
+
+func TestBytesBodyReader(t *testing.T) {
+	body, want := knownBody()
+	r := BytesBodyReader(body)
+	if got := readBlobs(t, r); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected blobs. want=%v. got=%v", want, got)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("could not close reader: %v", err)
+	}
+
+	// a trailing partial chunk is not a blob.
+	if got := readBlobs(t, BytesBodyReader(append(body, 5, 1))); !reflect.DeepEqual(got, want) {
+		t.Errorf("trailing partial chunk should be ignored. want=%v. got=%v", want, got)
+	}
+	if got := readBlobs(t, BytesBodyReader(nil)); len(got) != 0 {
+		t.Errorf("empty body should have no blobs. got=%v", got)
+	}
+}
+
+func TestFileBodyReader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bodyreader")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	txs := makeRandomTransactions(10)
+	body, err := SerializeTxToBlob(txs)
+	if err != nil {
+		t.Fatalf("could not serialize transactions: %v", err)
+	}
+	path := filepath.Join(dir, "body")
+	if err := ioutil.WriteFile(path, body, 0600); err != nil {
+		t.Fatalf("could not write body: %v", err)
+	}
+
+	r, err := FileBodyReader(path)
+	if err != nil {
+		t.Fatalf("could not open body reader: %v", err)
+	}
+	got, err := DeserializeBlobToTx(r)
+	if err != nil {
+		t.Fatalf("could not deserialize body from file: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("could not close reader: %v", err)
+	}
+	if len(*got) != len(txs) {
+		t.Fatalf("transaction count incorrect. want=%d. got=%d", len(txs), len(*got))
+	}
+	for i, tx := range *got {
+		if tx.Hash() != txs[i].Hash() {
+			t.Errorf("transaction %d mismatch. want=%v. got=%v", i, txs[i].Hash().Hex(), tx.Hash().Hex())
+		}
+	}
+
+	if _, err := FileBodyReader(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("opening a missing body should fail")
+	}
+}
+
+func TestCollationBodyReader_NextTransaction(t *testing.T) {
+	txs := makeRandomTransactions(20)
+	blob, err := SerializeTxToBlob(txs)
+	if err != nil {
+		t.Fatalf("could not serialize transactions: %v", err)
+	}
+
+	reader := NewCollationBodyReader(bytes.NewReader(blob))
+	for i := range txs {
+		tx, err := reader.NextTransaction()
+		if err != nil {
+			t.Fatalf("could not read transaction %d: %v", i, err)
+		}
+		if tx.Hash() != txs[i].Hash() {
+			t.Errorf("transaction %d mismatch. want=%v. got=%v", i, txs[i].Hash().Hex(), tx.Hash().Hex())
+		}
+	}
+	if _, err := reader.NextTransaction(); err != io.EOF {
+		t.Errorf("expected io.EOF after reading all transactions, got %v", err)
+	}
+}
+
+func TestCollationBodyReader_InvalidTransaction(t *testing.T) {
+	// a single terminal chunk holding bytes that are not an RLP encoded transaction.
+	data := make([]byte, 32)
+	data[0] = 0x02
+	data[1] = 0xff
+	data[2] = 0xff
+
+	reader := NewCollationBodyReader(bytes.NewReader(data))
+	if _, err := reader.NextTransaction(); err == nil || err == io.EOF {
+		t.Errorf("reading an invalid transaction should fail, got %v", err)
+	}
+	if _, err := DeserializeBlobToTx(BytesBodyReader(data)); err == nil {
+		t.Errorf("deserializing an invalid transaction should fail")
+	}
+}
+
+// deserializeBuffered decodes a body by first deserializing all blobs in memory,
+// which is the approach CollationBodyReader replaces.
+func deserializeBuffered(blob []byte) ([]*gethTypes.Transaction, error) {
+	rawBlobs, err := shardutil.Deserialize(blob)
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]*gethTypes.Transaction, len(rawBlobs))
+	for i := range rawBlobs {
+		txs[i] = gethTypes.NewTransaction(0, common.HexToAddress("0x"), nil, 0, nil, nil)
+		if err := shardutil.ConvertFromRawBlob(&rawBlobs[i], txs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return txs, nil
+}
+
+func BenchmarkDeserializeBuffered1000(b *testing.B) {
+	blob, err := SerializeTxToBlob(makeRandomTransactions(1000))
+	if err != nil {
+		b.Fatalf("SerializeTxToBlob failed: %v", err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := deserializeBuffered(blob); err != nil {
+			b.Errorf("deserializeBuffered failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkDeserializeStreaming1000(b *testing.B) {
+	blob, err := SerializeTxToBlob(makeRandomTransactions(1000))
+	if err != nil {
+		b.Fatalf("SerializeTxToBlob failed: %v", err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		reader := NewCollationBodyReader(bytes.NewReader(blob))
+		for {
+			_, err := reader.NextTransaction()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				b.Fatalf("NextTransaction failed: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package types
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prysmaticlabs/prysm/shared/hashutil"
+)
+
+// chunkTree is a binary Merkle tree built over the chunks of a collation body.
+// Leaves are the blake2b hashes of each chunk. A layer with an odd number of
+// nodes is padded with a single zero hash before its pairs are hashed into the
+// next layer, so every leaf is paired at each layer and all proofs have the
+// same depth.
+type chunkTree struct {
+	// layers contains the leaves in its first element and the root in its last.
+	layers [][]common.Hash
+	// leafCount is the number of chunks, excluding the zero hash padding.
+	leafCount int
+}
+
+// newChunkTree merklizes the given chunks into a chunkTree.
+func newChunkTree(chunks Chunks) *chunkTree {
+	leaves := make([]common.Hash, chunks.Len())
+	for i := range leaves {
+		leaves[i] = hashutil.Hash(chunks.chunk(i))
+	}
+	return newChunkTreeFromLeaves(leaves)
+}
+
+// newChunkTreeFromLeaves builds a chunkTree over already hashed leaves.
+func newChunkTreeFromLeaves(leaves []common.Hash) *chunkTree {
+	layer := leaves
+	tree := &chunkTree{leafCount: len(layer)}
+	for len(layer) > 1 {
+		if len(layer)%2 == 1 {
+			layer = append(layer, common.Hash{})
+		}
+		tree.layers = append(tree.layers, layer)
+		next := make([]common.Hash, len(layer)/2)
+		for i := range next {
+			next[i] = hashChunkNodes(layer[2*i], layer[2*i+1])
+		}
+		layer = next
+	}
+	tree.layers = append(tree.layers, layer)
+	return tree
+}
+
+// root returns the Merkle root of the tree. An empty tree has the hash of
+// an empty byte slice as its root.
+func (t *chunkTree) root() common.Hash {
+	top := t.layers[len(t.layers)-1]
+	if len(top) == 0 {
+		return hashutil.Hash([]byte{})
+	}
+	return top[0]
+}
+
+// proof returns the sibling hashes on the path from the leaf at index to the root.
+func (t *chunkTree) proof(index int) ([]common.Hash, error) {
+	if index < 0 || index >= t.leafCount {
+		return nil, fmt.Errorf("chunk index %d out of range for body with %d chunks", index, t.leafCount)
+	}
+	proof := make([]common.Hash, 0, len(t.layers)-1)
+	for _, layer := range t.layers[:len(t.layers)-1] {
+		proof = append(proof, layer[index^1])
+		index /= 2
+	}
+	return proof, nil
+}
+
+// hashChunkNodes computes the parent of two nodes in the chunk tree.
+func hashChunkNodes(left common.Hash, right common.Hash) common.Hash {
+	return hashutil.Hash(append(left.Bytes(), right.Bytes()...))
+}
+
+// chunkRootFromBody computes the chunk root of a serialized collation body.
+func chunkRootFromBody(body []byte) common.Hash {
+	return newChunkTree(ChunksFromBody(body)).root()
+}
+
+// IncrementalChunkTree is a chunk tree that keeps every node, so that the
+// chunk root can be updated after a chunk changes by rehashing only the path
+// from its leaf to the root instead of merklizing the whole body again. Its root
+// is the same as the chunk root of the body.
+type IncrementalChunkTree struct {
+	// nodes holds the tree in heap order: the root at index 1 and the children
+	// of node i at 2i and 2i+1, with the leaves starting at index width. Nodes
+	// covering no chunk are zero hashes, matching the padding of chunkTree.
+	nodes []common.Hash
+	// width is the number of leaf slots, the chunk count rounded up to a power of two.
+	width     int
+	leafCount int
+}
+
+// NewIncrementalChunkTree merklizes the chunks of body into an IncrementalChunkTree.
+func NewIncrementalChunkTree(body []byte) *IncrementalChunkTree {
+	chunks := ChunksFromBody(body)
+	width := 1
+	for width < chunks.Len() {
+		width *= 2
+	}
+	t := &IncrementalChunkTree{
+		nodes:     make([]common.Hash, 2*width),
+		width:     width,
+		leafCount: chunks.Len(),
+	}
+	for i := 0; i < chunks.Len(); i++ {
+		t.nodes[width+i] = hashutil.Hash(chunks.chunk(i))
+	}
+	// Walk the layers bottom up, only hashing the nodes that cover chunks.
+	for start, count := width/2, (chunks.Len()+1)/2; start >= 1; start, count = start/2, (count+1)/2 {
+		for i := start; i < start+count; i++ {
+			t.nodes[i] = hashChunkNodes(t.nodes[2*i], t.nodes[2*i+1])
+		}
+	}
+	return t
+}
+
+// Update replaces the chunk at chunkIndex and rehashes the path from its leaf
+// to the root. A chunk shorter than 32 bytes is zero-padded, as the last chunk
+// of a body is.
+func (t *IncrementalChunkTree) Update(chunkIndex int, newChunk []byte) error {
+	if chunkIndex < 0 || chunkIndex >= t.leafCount {
+		return fmt.Errorf("chunk index %d out of range for body with %d chunks", chunkIndex, t.leafCount)
+	}
+	if len(newChunk) > chunkSize {
+		return fmt.Errorf("chunk has %d bytes, expected at most %d", len(newChunk), chunkSize)
+	}
+	var chunk [chunkSize]byte
+	copy(chunk[:], newChunk)
+
+	i := t.width + chunkIndex
+	t.nodes[i] = hashutil.Hash(chunk[:])
+	for i /= 2; i >= 1; i /= 2 {
+		t.nodes[i] = hashChunkNodes(t.nodes[2*i], t.nodes[2*i+1])
+	}
+	return nil
+}
+
+// Root returns the chunk root of the tree.
+func (t *IncrementalChunkTree) Root() common.Hash {
+	if t.leafCount == 0 {
+		return hashutil.Hash([]byte{})
+	}
+	// With a single chunk the root is its leaf, stored at index 1.
+	return t.nodes[1]
+}
+
+// CalculateChunkRootParallel computes the same chunk root as CalculateChunkRoot,
+// spreading the work across up to workers goroutines. The chunks are split into
+// segments whose size is a power of two, so that the root of every segment is a
+// node of the chunk tree, and the segment roots are then merklized into the root.
+func (c *Collation) CalculateChunkRootParallel(workers int) error {
+	if workers < 1 {
+		return fmt.Errorf("number of workers must be positive, got %d", workers)
+	}
+	chunks := ChunksFromBody(c.body)
+	segmentSize := 1
+	for segmentSize*workers < chunks.Len() {
+		segmentSize *= 2
+	}
+	if segmentSize >= chunks.Len() {
+		// A single segment spans the whole tree.
+		c.CalculateChunkRoot()
+		return nil
+	}
+
+	defer observeDuration(chunkRootDuration, time.Now())
+	roots := make([]common.Hash, (chunks.Len()+segmentSize-1)/segmentSize)
+	var wg sync.WaitGroup
+	for i := range roots {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			end := (i + 1) * segmentSize
+			if end > chunks.Len() {
+				end = chunks.Len()
+			}
+			roots[i] = chunkSubtreeRoot(chunks, i*segmentSize, end, segmentSize)
+		}(i)
+	}
+	wg.Wait()
+
+	chunkRoot := newChunkTreeFromLeaves(roots).root()
+	c.header.data.ChunkRoot = &chunkRoot
+	c.bodyHash = hashutil.Hash(c.body)
+	c.logEvent(EventChunkRootCalculated, map[string]interface{}{"chunkRoot": chunkRoot.Hex()})
+	return nil
+}
+
+// chunkSubtreeRoot computes the node of the chunk tree covering the chunks from
+// start, up to but excluding end, at the height of a segment of segmentSize
+// chunks. Layers are padded with zero hashes exactly like in the full tree.
+func chunkSubtreeRoot(chunks Chunks, start int, end int, segmentSize int) common.Hash {
+	layer := make([]common.Hash, end-start)
+	for i := range layer {
+		layer[i] = hashutil.Hash(chunks.chunk(start + i))
+	}
+	for width := segmentSize; width > 1; width /= 2 {
+		if len(layer)%2 == 1 {
+			layer = append(layer, common.Hash{})
+		}
+		next := make([]common.Hash, len(layer)/2)
+		for i := range next {
+			next[i] = hashChunkNodes(layer[2*i], layer[2*i+1])
+		}
+		layer = next
+	}
+	return layer[0]
+}
+
+// ChunkProof returns a Merkle inclusion proof for the chunk at index in the
+// collation body, which can be checked against the chunk root using VerifyChunkProof.
+func (c *Collation) ChunkProof(index int) ([]common.Hash, error) {
+	return newChunkTree(ChunksFromBody(c.body)).proof(index)
+}
+
+// ChunkCount returns the number of 32 byte chunks in the collation body.
+func (c *Collation) ChunkCount() int {
+	return ChunksFromBody(c.body).Len()
+}
+
+// GetChunk returns the chunk at index in the collation body, allowing light
+// clients to verify single chunks through ChunkProof instead of downloading the
+// whole body. The last chunk is zero-padded, exactly as it is merklized.
+func (c *Collation) GetChunk(index int) ([]byte, error) {
+	chunks := ChunksFromBody(c.body)
+	if index < 0 || index >= chunks.Len() {
+		return nil, fmt.Errorf("chunk index %d out of range for body with %d chunks", index, chunks.Len())
+	}
+	return chunks.chunk(index), nil
+}
+
+// CalculateSaltedChunkRoot computes the chunk root used by validators during custody
+// challenges. Each chunk of the body is split into two halves which are XORed with
+// the matching half of a key derived from the validator's salt and the custody bit,
+// and the root is computed over the salted halves. Proposers should keep using
+// CalculateChunkRoot.
+func (c *Collation) CalculateSaltedChunkRoot(custodyBit uint8, salt []byte) common.Hash {
+	key := hashutil.Hash(append(append([]byte{}, salt...), custodyBit))
+	chunks := ChunksFromBody(c.body)
+
+	leaves := make([]common.Hash, 0, 2*chunks.Len())
+	for i := 0; i < chunks.Len(); i++ {
+		chunk := chunks.chunk(i)
+		half := chunkSize / 2
+		for h := 0; h < 2; h++ {
+			salted := make([]byte, half)
+			for j := range salted {
+				salted[j] = chunk[h*half+j] ^ key[h*half+j]
+			}
+			leaves = append(leaves, hashutil.Hash(salted))
+		}
+	}
+	return newChunkTreeFromLeaves(leaves).root()
+}
+
+// VerifyChunkProof checks that chunk is included at index in the collation body
+// identified by chunkRoot, given the sibling hashes returned by ChunkProof.
+// Chunks must be zero-padded to the chunk size, since leaves and inner nodes are
+// hashed alike and a 64 byte chunk could otherwise pass for a pair of nodes.
+func VerifyChunkProof(chunkRoot common.Hash, index int, chunk []byte, proof [][]byte) bool {
+	if len(chunk) != chunkSize {
+		return false
+	}
+	if index < 0 || (len(proof) < 63 && index >= 1<<uint(len(proof))) {
+		return false
+	}
+	node := common.Hash(hashutil.Hash(chunk))
+	for _, sibling := range proof {
+		if len(sibling) != common.HashLength {
+			return false
+		}
+		if index%2 == 0 {
+			node = hashChunkNodes(node, common.BytesToHash(sibling))
+		} else {
+			node = hashChunkNodes(common.BytesToHash(sibling), node)
+		}
+		index /= 2
+	}
+	return node == chunkRoot
+}
@@ -0,0 +1,427 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"runtime"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/prysmaticlabs/prysm/shared/hashutil"
+)
+
+func proofToBytes(proof []common.Hash) [][]byte {
+	raw := make([][]byte, len(proof))
+	for i, sibling := range proof {
+		raw[i] = sibling.Bytes()
+	}
+	return raw
+}
+
+// paddedChunk returns the i-th 32 byte chunk of body, zero-padded.
+func paddedChunk(body []byte, i int) []byte {
+	chunk := make([]byte, chunkSize)
+	copy(chunk, body[i*chunkSize:])
+	return chunk
+}
+
+func TestCollation_ChunkProof(t *testing.T) {
+	for _, size := range []int{1, 32, 33, 64, 100, 257} {
+		body := make([]byte, size)
+		for i := range body {
+			body[i] = byte(i + 1)
+		}
+		header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+		c := NewCollation(header, body, nil)
+		c.CalculateChunkRoot()
+		chunkRoot := *c.Header().ChunkRoot()
+		chunkCount := (size + 31) / 32
+
+		for i := 0; i < chunkCount; i++ {
+			proof, err := c.ChunkProof(i)
+			if err != nil {
+				t.Fatalf("could not generate proof for chunk %d: %v", i, err)
+			}
+			chunk := paddedChunk(body, i)
+			if !VerifyChunkProof(chunkRoot, i, chunk, proofToBytes(proof)) {
+				t.Errorf("valid proof for chunk %d of %d-byte body failed verification", i, size)
+			}
+			if VerifyChunkProof(chunkRoot, i, []byte{0xff}, proofToBytes(proof)) {
+				t.Errorf("proof for chunk %d of %d-byte body verified a tampered chunk", i, size)
+			}
+			if chunkCount > 1 && VerifyChunkProof(chunkRoot, (i+1)%chunkCount, chunk, proofToBytes(proof)) {
+				t.Errorf("proof for chunk %d of %d-byte body verified at the wrong index", i, size)
+			}
+		}
+		if _, err := c.ChunkProof(chunkCount); err == nil {
+			t.Errorf("generating a proof past the last chunk of a %d-byte body should fail", size)
+		}
+	}
+}
+
+func TestCollation_GetChunk(t *testing.T) {
+	serialized, err := SerializeTxToBlob(makeRandomTransactions(5))
+	if err != nil {
+		t.Fatalf("could not serialize transactions: %v", err)
+	}
+	for _, body := range [][]byte{{}, {1}, bytes.Repeat([]byte{1}, 33), serialized} {
+		header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+		c := NewCollation(header, body, nil)
+		c.CalculateChunkRoot()
+
+		wantCount := (len(body) + chunkSize - 1) / chunkSize
+		if c.ChunkCount() != wantCount {
+			t.Errorf("chunk count incorrect. want=%d. got=%d", wantCount, c.ChunkCount())
+		}
+
+		var reassembled []byte
+		for i := 0; i < c.ChunkCount(); i++ {
+			chunk, err := c.GetChunk(i)
+			if err != nil {
+				t.Fatalf("could not get chunk %d: %v", i, err)
+			}
+			if len(chunk) != chunkSize {
+				t.Errorf("chunk size incorrect. want=%d. got=%d", chunkSize, len(chunk))
+			}
+			proof, err := c.ChunkProof(i)
+			if err != nil {
+				t.Fatalf("could not generate proof for chunk %d: %v", i, err)
+			}
+			if !VerifyChunkProof(*header.ChunkRoot(), i, chunk, proofToBytes(proof)) {
+				t.Errorf("chunk %d failed verification", i)
+			}
+			reassembled = append(reassembled, chunk...)
+		}
+		// Only the padding of the last chunk differs from the body.
+		if !bytes.Equal(reassembled[:len(body)], body) || len(bytes.TrimRight(reassembled[len(body):], "\x00")) != 0 {
+			t.Errorf("reassembled chunks do not match the body. want=%x. got=%x", body, reassembled)
+		}
+		if _, err := c.GetChunk(c.ChunkCount()); err == nil {
+			t.Errorf("getting a chunk past the last one should fail")
+		}
+		if _, err := c.GetChunk(-1); err == nil {
+			t.Errorf("getting a negative chunk index should fail")
+		}
+	}
+
+	// Serialized bodies are aligned to chunks, so their chunks reassemble exactly.
+	c := NewCollation(newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1)), serialized, nil)
+	var reassembled []byte
+	for i := 0; i < c.ChunkCount(); i++ {
+		chunk, _ := c.GetChunk(i)
+		reassembled = append(reassembled, chunk...)
+	}
+	if !bytes.Equal(reassembled, serialized) {
+		t.Errorf("reassembled chunks do not match the serialized body")
+	}
+}
+
+func TestCollation_CalculateChunkRootParallel(t *testing.T) {
+	for _, size := range []int{0, 1, 32, 33, 100, 257, 1000, 4096, 5000} {
+		body := make([]byte, size)
+		for i := range body {
+			body[i] = byte(i + 1)
+		}
+		header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+		c := NewCollation(header, body, nil)
+		c.CalculateChunkRoot()
+		want := *header.ChunkRoot()
+
+		for _, workers := range []int{1, 2, 3, 4, 7, 16, 1000} {
+			header.data.ChunkRoot = nil
+			if err := c.CalculateChunkRootParallel(workers); err != nil {
+				t.Fatalf("could not calculate chunk root: %v", err)
+			}
+			if *header.ChunkRoot() != want {
+				t.Errorf("chunk root of %d-byte body with %d workers incorrect. want=%x. got=%x", size, workers, want, *header.ChunkRoot())
+			}
+			if !c.IsChunkRootFresh() {
+				t.Errorf("chunk root of %d-byte body with %d workers should be fresh", size, workers)
+			}
+		}
+	}
+
+	c := NewCollation(newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1)), nil, nil)
+	if err := c.CalculateChunkRootParallel(0); err == nil {
+		t.Errorf("calculating the chunk root without workers should fail")
+	}
+}
+
+// newLargeBodyCollation returns a collation with a 1 MiB body of 32768 chunks.
+func newLargeBodyCollation(b *testing.B) *Collation {
+	body := make([]byte, 32768*chunkSize)
+	for i := range body {
+		body[i] = byte(i)
+	}
+	return NewCollation(newTestCollationHeader(b, big.NewInt(1), nil, big.NewInt(1)), body, nil)
+}
+
+func BenchmarkCalculateChunkRoot(b *testing.B) {
+	c := newLargeBodyCollation(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.CalculateChunkRoot()
+	}
+}
+
+func BenchmarkCalculateChunkRootParallel(b *testing.B) {
+	c := newLargeBodyCollation(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := c.CalculateChunkRootParallel(runtime.NumCPU()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestIncrementalChunkTree(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 5, 8, 13, 33} {
+		body := make([]byte, n*chunkSize-n%2)
+		for i := range body {
+			body[i] = byte(i + 1)
+		}
+		tree := NewIncrementalChunkTree(body)
+		if want, got := chunkRootFromBody(body), tree.Root(); want != got {
+			t.Errorf("root of %d chunks incorrect. want=%x. got=%x", n, want, got)
+		}
+
+		for _, index := range []int{0, n / 2, n - 1} {
+			if index < 0 || index >= n {
+				continue
+			}
+			chunk := bytes.Repeat([]byte{byte(index + 0xf0)}, chunkSize)
+			if rest := len(body) - index*chunkSize; rest < chunkSize {
+				// the last chunk of a body not ending on a chunk boundary.
+				chunk = chunk[:rest]
+			}
+			if err := tree.Update(index, chunk); err != nil {
+				t.Fatalf("could not update chunk %d of %d: %v", index, n, err)
+			}
+			copy(body[index*chunkSize:], chunk)
+			if want, got := chunkRootFromBody(body), tree.Root(); want != got {
+				t.Errorf("root of %d chunks after updating chunk %d incorrect. want=%x. got=%x", n, index, want, got)
+			}
+		}
+	}
+}
+
+func TestIncrementalChunkTree_UpdateShortChunk(t *testing.T) {
+	body := make([]byte, 3*chunkSize)
+	tree := NewIncrementalChunkTree(body)
+	if err := tree.Update(1, []byte{1, 2, 3}); err != nil {
+		t.Fatalf("could not update chunk: %v", err)
+	}
+	body[chunkSize], body[chunkSize+1], body[chunkSize+2] = 1, 2, 3
+	if want, got := chunkRootFromBody(body), tree.Root(); want != got {
+		t.Errorf("short chunk should be zero-padded. want=%x. got=%x", want, got)
+	}
+}
+
+func TestIncrementalChunkTree_UpdateInvalid(t *testing.T) {
+	tree := NewIncrementalChunkTree(make([]byte, 4*chunkSize))
+	root := tree.Root()
+	if err := tree.Update(-1, nil); err == nil {
+		t.Errorf("updating a negative chunk index should fail")
+	}
+	if err := tree.Update(4, nil); err == nil {
+		t.Errorf("updating a chunk index beyond the body should fail")
+	}
+	if err := tree.Update(0, make([]byte, chunkSize+1)); err == nil {
+		t.Errorf("updating a chunk with more than %d bytes should fail", chunkSize)
+	}
+	if tree.Root() != root {
+		t.Errorf("failed updates should leave the root unchanged")
+	}
+}
+
+// BenchmarkIncrementalChunkTree_Update updates a single chunk of a 32768 chunk
+// body, to compare with BenchmarkCalculateChunkRoot which merklizes it all.
+func BenchmarkIncrementalChunkTree_Update(b *testing.B) {
+	tree := NewIncrementalChunkTree(newLargeBodyCollation(b).Body())
+	chunk := bytes.Repeat([]byte{0xff}, chunkSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		chunk[0] = byte(i)
+		if err := tree.Update(i%32768, chunk); err != nil {
+			b.Fatal(err)
+		}
+		tree.Root()
+	}
+}
+
+func TestChunks_FixedSize(t *testing.T) {
+	body := make([]byte, 70)
+	for i := range body {
+		body[i] = byte(i + 1)
+	}
+	chunks := ChunksFromBody(body)
+
+	if chunks.Len() != 3 {
+		t.Fatalf("chunk count incorrect. want=%d. got=%d", 3, chunks.Len())
+	}
+	if ChunksFromBody(body[:64]).Len() != 2 {
+		t.Errorf("chunk count of 64 byte body incorrect. want=%d. got=%d", 2, ChunksFromBody(body[:64]).Len())
+	}
+	if ChunksFromBody([]byte{}).Len() != 0 {
+		t.Errorf("empty body should have no chunks")
+	}
+
+	last := chunks.chunk(2)
+	if len(last) != 32 {
+		t.Fatalf("last chunk should be padded to 32 bytes, got %d", len(last))
+	}
+	if !bytes.Equal(last[:6], body[64:]) || !bytes.Equal(last[6:], make([]byte, 26)) {
+		t.Errorf("last chunk should be the remaining body bytes followed by zero padding, got %x", last)
+	}
+
+	expected, err := rlp.EncodeToBytes(body[32:64])
+	if err != nil {
+		t.Fatalf("could not encode chunk: %v", err)
+	}
+	if !bytes.Equal(chunks.GetRlp(1), expected) {
+		t.Errorf("GetRlp should encode the 32 byte chunk. want=%x. got=%x", expected, chunks.GetRlp(1))
+	}
+
+	body[0] = 0xff
+	if chunks[0][0] != 1 {
+		t.Errorf("chunks should not share memory with the body")
+	}
+}
+
+func TestCollation_ChunkProofOutOfRange(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	c := NewCollation(header, []byte{1, 2, 3}, nil)
+
+	if _, err := c.ChunkProof(1); err == nil {
+		t.Errorf("generating a proof for an out of range chunk should fail")
+	}
+	if _, err := c.ChunkProof(-1); err == nil {
+		t.Errorf("generating a proof for a negative chunk index should fail")
+	}
+}
+
+func TestVerifyChunkProof_MalformedProof(t *testing.T) {
+	body := make([]byte, 128)
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	c := NewCollation(header, body, nil)
+	c.CalculateChunkRoot()
+
+	proof, err := c.ChunkProof(1)
+	if err != nil {
+		t.Fatalf("could not generate proof: %v", err)
+	}
+	raw := proofToBytes(proof)
+	raw[0] = raw[0][:10]
+	if VerifyChunkProof(*c.Header().ChunkRoot(), 1, paddedChunk(body, 1), raw) {
+		t.Errorf("proof with a truncated sibling should not verify")
+	}
+	if VerifyChunkProof(*c.Header().ChunkRoot(), 1, paddedChunk(body, 1), nil) {
+		t.Errorf("empty proof should not verify a multi-chunk body")
+	}
+}
+
+func TestVerifyChunkProof_ForgedInnerNode(t *testing.T) {
+	body := make([]byte, 4*chunkSize)
+	for i := range body {
+		body[i] = byte(i)
+	}
+	tree := newChunkTree(ChunksFromBody(body))
+
+	// the two leftmost leaves hash to the left child of the root, so their
+	// concatenation and the right child form a proof of the same depth.
+	forged := append(tree.layers[0][0].Bytes(), tree.layers[0][1].Bytes()...)
+	proof := [][]byte{tree.layers[1][1].Bytes()}
+	if VerifyChunkProof(tree.root(), 0, forged, proof) {
+		t.Errorf("pair of inner nodes should not verify as a chunk")
+	}
+	if VerifyChunkProof(tree.root(), 0, body[:chunkSize-1], nil) {
+		t.Errorf("chunk shorter than the chunk size should not verify")
+	}
+}
+
+func TestCollation_CalculateSaltedChunkRootKeyHalves(t *testing.T) {
+	// a chunk with equal halves only salts to equal leaves if both halves
+	// are XORed with the same part of the key.
+	body := bytes.Repeat([]byte{0xab}, chunkSize)
+	c := NewCollation(newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1)), body, nil)
+	salt := []byte{7}
+
+	key := hashutil.Hash(append(append([]byte{}, salt...), 1))
+	var leaves []common.Hash
+	for h := 0; h < 2; h++ {
+		salted := make([]byte, chunkSize/2)
+		for j := range salted {
+			salted[j] = body[h*chunkSize/2+j] ^ key[h*chunkSize/2+j]
+		}
+		leaves = append(leaves, hashutil.Hash(salted))
+	}
+	if leaves[0] == leaves[1] {
+		t.Fatalf("key halves should salt equal chunk halves differently")
+	}
+	want := newChunkTreeFromLeaves(leaves).root()
+	if got := c.CalculateSaltedChunkRoot(1, salt); got != want {
+		t.Errorf("salted chunk root incorrect. want=%x. got=%x", want, got)
+	}
+}
+
+func TestCollation_CalculateSaltedChunkRoot(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	body := make([]byte, 100)
+	for i := range body {
+		body[i] = byte(i)
+	}
+	c := NewCollation(header, body, nil)
+	c.CalculateChunkRoot()
+
+	salt := []byte{1, 0x9f}
+	root := c.CalculateSaltedChunkRoot(0, salt)
+
+	if root == *c.Header().ChunkRoot() {
+		t.Errorf("salted chunk root should differ from the unsalted chunk root")
+	}
+	if root != c.CalculateSaltedChunkRoot(0, salt) {
+		t.Errorf("salted chunk root should be deterministic")
+	}
+	if root == c.CalculateSaltedChunkRoot(1, salt) {
+		t.Errorf("salted chunk root should differ between custody bits")
+	}
+	if root == c.CalculateSaltedChunkRoot(0, []byte{2, 0x9f}) {
+		t.Errorf("salted chunk root should differ between salts")
+	}
+}
+
+func TestCollation_IsChunkRootFresh(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	c := NewCollation(header, []byte{1, 2, 3}, nil)
+
+	if c.IsChunkRootFresh() {
+		t.Errorf("chunk root should not be fresh before it is calculated")
+	}
+
+	c.CalculateChunkRoot()
+	if !c.IsChunkRootFresh() {
+		t.Errorf("chunk root should be fresh after it is calculated")
+	}
+
+	// mutating the body in place makes the chunk root stale.
+	c.body[0] = 0xff
+	if c.IsChunkRootFresh() {
+		t.Errorf("chunk root should be stale after the body is mutated")
+	}
+	c.CalculateChunkRoot()
+	if !c.IsChunkRootFresh() {
+		t.Errorf("chunk root should be fresh after it is recalculated")
+	}
+
+	// collations created with a precomputed chunk root are checked against the body.
+	chunkRoot := *c.Header().ChunkRoot()
+	stored := NewCollation(newTestCollationHeader(t, big.NewInt(1), &chunkRoot, big.NewInt(1)), []byte{0xff, 2, 3}, nil)
+	if !stored.IsChunkRootFresh() {
+		t.Errorf("precomputed chunk root matching the body should be fresh")
+	}
+	stored = NewCollation(newTestCollationHeader(t, big.NewInt(1), &chunkRoot, big.NewInt(1)), []byte{1, 2, 3}, nil)
+	if stored.IsChunkRootFresh() {
+		t.Errorf("precomputed chunk root not matching the body should be stale")
+	}
+}
@@ -0,0 +1,208 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ChunkRequest asks a peer for some chunks of the body of a collation.
+type ChunkRequest struct {
+	HeaderHash common.Hash // the signed hash of the collation header.
+	Indices    []uint64    // the indices of the requested chunks.
+}
+
+// Encode RLP encodes the request to be sent over the wire.
+func (r *ChunkRequest) Encode() ([]byte, error) {
+	return rlp.EncodeToBytes(r)
+}
+
+// DecodeChunkRequest decodes an RLP encoded chunk request.
+func DecodeChunkRequest(data []byte) (*ChunkRequest, error) {
+	r := &ChunkRequest{}
+	if err := rlp.DecodeBytes(data, r); err != nil {
+		return nil, fmt.Errorf("could not decode chunk request: %v", err)
+	}
+	return r, nil
+}
+
+// ChunkResponse answers a ChunkRequest with one of the requested chunks and
+// its Merkle proof against the collation's chunk root, as returned by
+// ChunkProof. Chunks are answered one at a time so that every chunk received
+// over a poor connection is kept.
+type ChunkResponse struct {
+	HeaderHash common.Hash // the signed hash of the collation header.
+	Index      uint64      // the index of the chunk in the body.
+	Chunk      []byte      // the zero-padded chunk.
+	Proof      [][]byte    // the sibling hashes from the chunk to the chunk root.
+}
+
+// Encode RLP encodes the response to be sent over the wire.
+func (r *ChunkResponse) Encode() ([]byte, error) {
+	return rlp.EncodeToBytes(r)
+}
+
+// DecodeChunkResponse decodes an RLP encoded chunk response.
+func DecodeChunkResponse(data []byte) (*ChunkResponse, error) {
+	r := &ChunkResponse{}
+	if err := rlp.DecodeBytes(data, r); err != nil {
+		return nil, fmt.Errorf("could not decode chunk response: %v", err)
+	}
+	return r, nil
+}
+
+// ChunkedBodyFetcher downloads collation bodies chunk by chunk, so that a
+// partial download can be completed from other peers instead of starting
+// over. Chunks are checked against the header's chunk root as they arrive.
+type ChunkedBodyFetcher struct {
+	send    func(peer string, req *ChunkRequest) error
+	pending map[common.Hash]*chunkDownload
+	lock    sync.Mutex
+}
+
+// chunkDownload is a pending FetchChunks call.
+type chunkDownload struct {
+	peer      string
+	chunkRoot common.Hash
+	chunks    map[int][]byte
+	missing   map[int]bool
+	done      chan struct{}
+}
+
+// NewChunkedBodyFetcher creates a ChunkedBodyFetcher sending its requests with
+// send. Responses are handed to Deliver.
+func NewChunkedBodyFetcher(send func(peer string, req *ChunkRequest) error) *ChunkedBodyFetcher {
+	return &ChunkedBodyFetcher{
+		send:    send,
+		pending: make(map[common.Hash]*chunkDownload),
+	}
+}
+
+// FetchChunks asks the peer for the chunks of the collation's body at the
+// indices and waits until all of them are delivered. If ctx is done first,
+// the chunks delivered so far are returned along with the context's error, so
+// that the missing chunks can be fetched elsewhere. Only one fetch can be
+// pending per collation.
+func (f *ChunkedBodyFetcher) FetchChunks(ctx context.Context, header *CollationHeader, chunkIndices []int, peer string) (map[int][]byte, error) {
+	if header == nil || header.ChunkRoot() == nil {
+		return nil, errors.New("collation header has no chunk root to check chunks against")
+	}
+	download := &chunkDownload{
+		peer:      peer,
+		chunkRoot: *header.ChunkRoot(),
+		chunks:    make(map[int][]byte),
+		missing:   make(map[int]bool),
+		done:      make(chan struct{}),
+	}
+	req := &ChunkRequest{HeaderHash: header.SignedHash()}
+	for _, index := range chunkIndices {
+		if index < 0 {
+			return nil, fmt.Errorf("invalid chunk index %d", index)
+		}
+		if !download.missing[index] {
+			download.missing[index] = true
+			req.Indices = append(req.Indices, uint64(index))
+		}
+	}
+	if len(download.missing) == 0 {
+		return download.chunks, nil
+	}
+
+	f.lock.Lock()
+	if _, ok := f.pending[req.HeaderHash]; ok {
+		f.lock.Unlock()
+		return nil, fmt.Errorf("chunks of collation %s are already being fetched", req.HeaderHash.Hex())
+	}
+	f.pending[req.HeaderHash] = download
+	f.lock.Unlock()
+
+	defer func() {
+		f.lock.Lock()
+		delete(f.pending, req.HeaderHash)
+		f.lock.Unlock()
+	}()
+
+	if err := f.send(peer, req); err != nil {
+		return nil, fmt.Errorf("could not request chunks from peer %s: %v", peer, err)
+	}
+
+	select {
+	case <-download.done:
+	case <-ctx.Done():
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	chunks := make(map[int][]byte, len(download.chunks))
+	for index, chunk := range download.chunks {
+		chunks[index] = chunk
+	}
+	if len(download.missing) > 0 {
+		return chunks, fmt.Errorf("fetched %d of %d chunks from peer %s: %v", len(chunks), len(req.Indices), peer, ctx.Err())
+	}
+	return chunks, nil
+}
+
+// Deliver hands a chunk to the pending fetch it answers. Chunks that were not
+// requested from the peer or that do not match the chunk root are rejected.
+func (f *ChunkedBodyFetcher) Deliver(peerID string, resp *ChunkResponse) error {
+	if resp == nil {
+		return errors.New("no chunk response provided")
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	download, ok := f.pending[resp.HeaderHash]
+	if !ok || download.peer != peerID {
+		return fmt.Errorf("chunks of collation %s were not requested from peer %s", resp.HeaderHash.Hex(), peerID)
+	}
+	index := int(resp.Index)
+	if index < 0 || !download.missing[index] {
+		return fmt.Errorf("chunk %d of collation %s was not requested or already delivered", resp.Index, resp.HeaderHash.Hex())
+	}
+	if len(resp.Chunk) != chunkSize || !VerifyChunkProof(download.chunkRoot, index, resp.Chunk, resp.Proof) {
+		return fmt.Errorf("chunk %d of collation %s does not match its chunk root", index, resp.HeaderHash.Hex())
+	}
+	download.chunks[index] = resp.Chunk
+	delete(download.missing, index)
+	if len(download.missing) == 0 {
+		close(download.done)
+	}
+	return nil
+}
+
+// AssembleBody joins the chunks of a body with totalChunks chunks. Missing
+// chunks are left as zero bytes, so that a partially downloaded body can be
+// inspected. Bodies are assembled with the zero padding of their last chunk.
+func AssembleBody(chunks map[int][]byte, totalChunks int) ([]byte, error) {
+	if totalChunks < 0 {
+		return nil, fmt.Errorf("invalid chunk count %d", totalChunks)
+	}
+	body := make([]byte, totalChunks*chunkSize)
+	for index, chunk := range chunks {
+		if index < 0 || index >= totalChunks {
+			return nil, fmt.Errorf("chunk index %d out of range for body with %d chunks", index, totalChunks)
+		}
+		if len(chunk) > chunkSize {
+			return nil, fmt.Errorf("chunk %d has %d bytes, more than the chunk size %d", index, len(chunk), chunkSize)
+		}
+		copy(body[index*chunkSize:], chunk)
+	}
+	return body, nil
+}
+
+// IsBodyComplete reports whether every chunk of a body with totalChunks chunks
+// is present.
+func IsBodyComplete(chunks map[int][]byte, totalChunks int) bool {
+	for index := 0; index < totalChunks; index++ {
+		if chunks[index] == nil {
+			return false
+		}
+	}
+	return true
+}
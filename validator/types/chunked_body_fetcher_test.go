@@ -0,0 +1,227 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// chunkPeer serves chunk requests for a collation, answering only the chunks
+// it holds.
+type chunkPeer struct {
+	collation *Collation
+	has       func(index int) bool
+}
+
+// newTestChunkedBodyFetcher creates a fetcher whose requests are answered
+// asynchronously by the peers.
+func newTestChunkedBodyFetcher(t *testing.T, peers map[string]*chunkPeer) *ChunkedBodyFetcher {
+	var fetcher *ChunkedBodyFetcher
+	fetcher = NewChunkedBodyFetcher(func(peer string, req *ChunkRequest) error {
+		p, ok := peers[peer]
+		if !ok {
+			return errors.New("unknown peer")
+		}
+		go func() {
+			for _, index := range req.Indices {
+				if !p.has(int(index)) {
+					continue
+				}
+				if err := fetcher.Deliver(peer, chunkResponse(t, p.collation, int(index))); err != nil {
+					t.Errorf("could not deliver chunk %d: %v", index, err)
+				}
+			}
+		}()
+		return nil
+	})
+	return fetcher
+}
+
+func chunkResponse(t *testing.T, c *Collation, index int) *ChunkResponse {
+	chunk, err := c.GetChunk(index)
+	if err != nil {
+		t.Fatalf("could not get chunk %d: %v", index, err)
+	}
+	proof, err := c.ChunkProof(index)
+	if err != nil {
+		t.Fatalf("could not prove chunk %d: %v", index, err)
+	}
+	encoded := make([][]byte, len(proof))
+	for i := range proof {
+		encoded[i] = proof[i].Bytes()
+	}
+	return &ChunkResponse{HeaderHash: c.Header().SignedHash(), Index: uint64(index), Chunk: chunk, Proof: encoded}
+}
+
+func allChunks(n int) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
+
+func TestChunkMessages_EncodeDecode(t *testing.T) {
+	req := &ChunkRequest{HeaderHash: common.BytesToHash([]byte{1}), Indices: []uint64{0, 4, 7}}
+	encoded, err := req.Encode()
+	if err != nil {
+		t.Fatalf("could not encode chunk request: %v", err)
+	}
+	decodedReq, err := DecodeChunkRequest(encoded)
+	if err != nil {
+		t.Fatalf("could not decode chunk request: %v", err)
+	}
+	if !reflect.DeepEqual(decodedReq, req) {
+		t.Errorf("decoded chunk request does not match. want=%+v. got=%+v", req, decodedReq)
+	}
+
+	resp := chunkResponse(t, makeStoredCollation(t, 1, 1), 2)
+	encoded, err = resp.Encode()
+	if err != nil {
+		t.Fatalf("could not encode chunk response: %v", err)
+	}
+	decodedResp, err := DecodeChunkResponse(encoded)
+	if err != nil {
+		t.Fatalf("could not decode chunk response: %v", err)
+	}
+	if !reflect.DeepEqual(decodedResp, resp) {
+		t.Errorf("decoded chunk response does not match. want=%+v. got=%+v", resp, decodedResp)
+	}
+	if _, err := DecodeChunkResponse(encoded[:len(encoded)-1]); err == nil {
+		t.Errorf("decoding a truncated response should fail")
+	}
+}
+
+func TestChunkedBodyFetcher_PartialDownload(t *testing.T) {
+	c := makeStoredCollation(t, 1, 1)
+	total := c.ChunkCount()
+	fetcher := newTestChunkedBodyFetcher(t, map[string]*chunkPeer{
+		// a peer whose connection drops every odd chunk.
+		"flaky": {collation: c, has: func(index int) bool { return index%2 == 0 }},
+		"good":  {collation: c, has: func(index int) bool { return true }},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	chunks, err := fetcher.FetchChunks(ctx, c.Header(), allChunks(total), "flaky")
+	if err == nil {
+		t.Fatalf("fetching from a peer missing chunks should time out")
+	}
+	if len(chunks) != (total+1)/2 {
+		t.Fatalf("partial download chunk count incorrect. want=%d. got=%d", (total+1)/2, len(chunks))
+	}
+	if IsBodyComplete(chunks, total) {
+		t.Errorf("partial download should not be complete")
+	}
+
+	// a partial body has zero bytes in place of the missing chunks.
+	partial, err := AssembleBody(chunks, total)
+	if err != nil {
+		t.Fatalf("could not assemble partial body: %v", err)
+	}
+	if len(partial) != total*chunkSize {
+		t.Fatalf("partial body length incorrect. want=%d. got=%d", total*chunkSize, len(partial))
+	}
+	for index := 0; index < total; index++ {
+		want, _ := c.GetChunk(index)
+		if index%2 == 1 {
+			want = make([]byte, chunkSize)
+		}
+		if got := partial[index*chunkSize : (index+1)*chunkSize]; !bytes.Equal(got, want) {
+			t.Errorf("chunk %d of partial body incorrect. want=%x. got=%x", index, want, got)
+		}
+	}
+
+	// the missing chunks are completed from another peer.
+	var missing []int
+	for index := 0; index < total; index++ {
+		if _, ok := chunks[index]; !ok {
+			missing = append(missing, index)
+		}
+	}
+	rest, err := fetcher.FetchChunks(context.Background(), c.Header(), missing, "good")
+	if err != nil {
+		t.Fatalf("could not fetch missing chunks: %v", err)
+	}
+	for index, chunk := range rest {
+		chunks[index] = chunk
+	}
+	if !IsBodyComplete(chunks, total) {
+		t.Fatalf("body should be complete after fetching the missing chunks")
+	}
+	body, err := AssembleBody(chunks, total)
+	if err != nil {
+		t.Fatalf("could not assemble body: %v", err)
+	}
+	if !bytes.Equal(body[:len(c.Body())], c.Body()) || len(bytes.Trim(body[len(c.Body()):], "\x00")) != 0 {
+		t.Errorf("assembled body should be the zero-padded collation body")
+	}
+}
+
+func TestChunkedBodyFetcher_Deliver(t *testing.T) {
+	c := makeStoredCollation(t, 1, 1)
+	other := makeStoredCollation(t, 1, 2)
+	fetcher := NewChunkedBodyFetcher(func(peer string, req *ChunkRequest) error { return nil })
+
+	done := make(chan error)
+	go func() {
+		_, err := fetcher.FetchChunks(context.Background(), c.Header(), []int{0, 1}, "peer1")
+		done <- err
+	}()
+	// wait for the fetch to be pending.
+	for {
+		fetcher.lock.Lock()
+		pending := len(fetcher.pending)
+		fetcher.lock.Unlock()
+		if pending == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := fetcher.Deliver("peer2", chunkResponse(t, c, 0)); err == nil {
+		t.Errorf("delivering a chunk from a peer it was not requested from should fail")
+	}
+	if err := fetcher.Deliver("peer1", chunkResponse(t, c, 2)); err == nil {
+		t.Errorf("delivering a chunk that was not requested should fail")
+	}
+	forged := chunkResponse(t, other, 0)
+	forged.HeaderHash = c.Header().SignedHash()
+	if err := fetcher.Deliver("peer1", forged); err == nil {
+		t.Errorf("delivering a chunk that does not match the chunk root should fail")
+	}
+	if err := fetcher.Deliver("peer1", chunkResponse(t, c, 0)); err != nil {
+		t.Fatalf("could not deliver chunk: %v", err)
+	}
+	if err := fetcher.Deliver("peer1", chunkResponse(t, c, 0)); err == nil {
+		t.Errorf("delivering a chunk twice should fail")
+	}
+	if err := fetcher.Deliver("peer1", chunkResponse(t, c, 1)); err != nil {
+		t.Fatalf("could not deliver chunk: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("fetch should complete once every chunk is delivered: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("fetch did not complete")
+	}
+}
+
+func TestAssembleBody_Invalid(t *testing.T) {
+	if _, err := AssembleBody(map[int][]byte{2: make([]byte, chunkSize)}, 2); err == nil {
+		t.Errorf("assembling a chunk out of range should fail")
+	}
+	if _, err := AssembleBody(map[int][]byte{0: make([]byte, chunkSize+1)}, 2); err == nil {
+		t.Errorf("assembling an oversized chunk should fail")
+	}
+	if !IsBodyComplete(nil, 0) {
+		t.Errorf("empty body should be complete")
+	}
+}
@@ -1,20 +1,30 @@
 package types
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"io"
 
 	"math/big"
+	"sort"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/snappy"
 	"github.com/prysmaticlabs/prysm/shared/hashutil"
 	"github.com/prysmaticlabs/prysm/shared/shardutil"
 	"github.com/prysmaticlabs/prysm/validator/params"
 )
 
 // Collation defines a base struct that serves as a primitive equivalent of a "block"
-// in a sharded Ethereum blockchain.
+// in a sharded Ethereum blockchain. A Collation is not safe for concurrent use;
+// callers sharing one between goroutines need to synchronize access to it.
 type Collation struct {
 	header *CollationHeader
 	// body represents the serialized blob of a collation's transactions.
@@ -25,6 +35,17 @@ type Collation struct {
 	// body would need to be recalculated. This will be a useful property for proposers
 	// in our system.
 	transactions []*gethTypes.Transaction
+	// config contains the shard parameters such as the collation size limit
+	// the collation body is serialized against.
+	config ShardConfig
+	// bodyHash is the hash of the body the header's chunk root was last
+	// calculated from, used to detect stale chunk roots.
+	bodyHash common.Hash
+	// txIndex maps transaction hashes to their index in transactions. It is
+	// built on the first TransactionByHash call and dropped when the body changes.
+	txIndex map[common.Hash]int
+	// events receives the collation's lifecycle events, if set.
+	events *EventLogger
 }
 
 // CollationHeader base struct.
@@ -36,37 +57,290 @@ type CollationHeader struct {
 }
 
 type collationHeaderData struct {
-	ShardID           *big.Int        // the shard ID of the shard.
-	ChunkRoot         *common.Hash    // the root of the chunk tree which identifies collation body.
-	Period            *big.Int        // the period number in which collation to be included.
-	ProposerAddress   *common.Address // address of the collation proposer.
-	ProposerSignature [32]byte        // the proposer's signature for calculating collation hash.
+	ShardID                    *big.Int          // the shard ID of the shard.
+	ChunkRoot                  *common.Hash      // the root of the chunk tree which identifies collation body.
+	Period                     *big.Int          // the period number in which collation to be included.
+	ProposerAddresses          []*common.Address // addresses of the proposers co-signing the collation.
+	ProposerSignatures         [][]byte          // the proposers' signatures, at the index of their address.
+	SkipEvmExecution           bool              // whether the collation's transactions skip EVM execution.
+	AggregateProposerSignature []byte            // the BLS signature aggregated from the committee members approving the header.
+	BodyContentType            uint8             // how the collation body is encoded, one of the ContentType constants.
 }
 
+// Collation body content types, telling how a collation body is decoded.
+const (
+	// ContentTypeTransactions is a body of serialized transaction blobs.
+	ContentTypeTransactions uint8 = iota
+	// ContentTypeRawData is a body of arbitrary data, e.g. for data shards.
+	ContentTypeRawData
+	// ContentTypeCompressed is a snappy compressed body of serialized
+	// transaction blobs, as produced by Compress.
+	ContentTypeCompressed
+)
+
+// collationHeaderRLP is the RLP layout of a collation header. A header with a
+// single proposer encodes its address and signature as plain strings, exactly
+// like headers did before multi-proposer collations, while a header with
+// several proposers encodes lists of addresses and signatures sorted by address.
+// The body content type is only appended when it is not ContentTypeTransactions,
+// so that headers of transaction bodies keep their encoding and hashes. The
+// compressed flag is kept for the layout but derived from the content type.
+type collationHeaderRLP struct {
+	ShardID                    *big.Int
+	ChunkRoot                  *common.Hash `rlp:"nil"` // a header without chunk root encodes it as an empty string.
+	Period                     *big.Int
+	Proposers                  rlp.RawValue
+	Signatures                 rlp.RawValue
+	SkipEvmExecution           bool
+	Compressed                 bool
+	AggregateProposerSignature []byte
+	Optional                   []rlp.RawValue `rlp:"tail"`
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (d collationHeaderData) EncodeRLP(w io.Writer) error {
+	addrs, sigs := d.sortedProposers()
+	var proposers, signatures interface{} = addrs, sigs
+	if len(addrs) <= 1 {
+		var addr *common.Address
+		var sig []byte
+		if len(addrs) == 1 {
+			addr, sig = addrs[0], sigs[0]
+		}
+		proposers, signatures = addr, sig
+	}
+
+	enc := collationHeaderRLP{
+		ShardID:                    d.ShardID,
+		ChunkRoot:                  d.ChunkRoot,
+		Period:                     d.Period,
+		SkipEvmExecution:           d.SkipEvmExecution,
+		Compressed:                 d.compressed(),
+		AggregateProposerSignature: d.AggregateProposerSignature,
+	}
+	var err error
+	if enc.Proposers, err = rlp.EncodeToBytes(proposers); err != nil {
+		return err
+	}
+	if enc.Signatures, err = rlp.EncodeToBytes(signatures); err != nil {
+		return err
+	}
+	if d.BodyContentType != ContentTypeTransactions {
+		contentType, err := rlp.EncodeToBytes(d.BodyContentType)
+		if err != nil {
+			return err
+		}
+		enc.Optional = []rlp.RawValue{contentType}
+	}
+	return rlp.Encode(w, &enc)
+}
+
+// DecodeRLP implements rlp.Decoder, accepting both the single proposer and the
+// multi-proposer layout.
+func (d *collationHeaderData) DecodeRLP(s *rlp.Stream) error {
+	var dec collationHeaderRLP
+	if err := s.Decode(&dec); err != nil {
+		return err
+	}
+	*d = collationHeaderData{
+		ShardID:                    dec.ShardID,
+		ChunkRoot:                  dec.ChunkRoot,
+		Period:                     dec.Period,
+		SkipEvmExecution:           dec.SkipEvmExecution,
+		AggregateProposerSignature: dec.AggregateProposerSignature,
+	}
+	if len(dec.Optional) > 1 {
+		return fmt.Errorf("header has %d unknown trailing fields", len(dec.Optional)-1)
+	}
+	if len(dec.Optional) == 1 {
+		if err := rlp.DecodeBytes(dec.Optional[0], &d.BodyContentType); err != nil {
+			return err
+		}
+	}
+	if dec.Compressed != d.compressed() {
+		return fmt.Errorf("compressed flag %t does not match body content type %d", dec.Compressed, d.BodyContentType)
+	}
+
+	kind, content, _, err := rlp.Split(dec.Proposers)
+	if err != nil {
+		return err
+	}
+	if kind == rlp.List {
+		if err := rlp.DecodeBytes(dec.Proposers, &d.ProposerAddresses); err != nil {
+			return err
+		}
+		return rlp.DecodeBytes(dec.Signatures, &d.ProposerSignatures)
+	}
+	if len(content) == 0 {
+		// An empty address string is a header without proposers.
+		return nil
+	}
+	addr := new(common.Address)
+	if err := rlp.DecodeBytes(dec.Proposers, addr); err != nil {
+		return err
+	}
+	var sig []byte
+	if err := rlp.DecodeBytes(dec.Signatures, &sig); err != nil {
+		return err
+	}
+	d.ProposerAddresses = []*common.Address{addr}
+	d.ProposerSignatures = [][]byte{sig}
+	return nil
+}
+
+// sortedProposers returns copies of the proposer addresses and their signatures
+// sorted by address, so that hashes and encodings do not depend on the order in
+// which proposers were added. Missing signatures are returned as nil.
+func (d *collationHeaderData) sortedProposers() ([]*common.Address, [][]byte) {
+	addrs := make([]*common.Address, len(d.ProposerAddresses))
+	sigs := make([][]byte, len(d.ProposerAddresses))
+	copy(addrs, d.ProposerAddresses)
+	copy(sigs, d.ProposerSignatures)
+	sort.Sort(proposersByAddress{addrs, sigs})
+	return addrs, sigs
+}
+
+// proposersByAddress sorts proposer addresses along with their signatures.
+type proposersByAddress struct {
+	addrs []*common.Address
+	sigs  [][]byte
+}
+
+func (p proposersByAddress) Len() int { return len(p.addrs) }
+func (p proposersByAddress) Swap(i, j int) {
+	p.addrs[i], p.addrs[j] = p.addrs[j], p.addrs[i]
+	p.sigs[i], p.sigs[j] = p.sigs[j], p.sigs[i]
+}
+func (p proposersByAddress) Less(i, j int) bool {
+	if p.addrs[i] == nil || p.addrs[j] == nil {
+		return p.addrs[i] == nil && p.addrs[j] != nil
+	}
+	return bytes.Compare(p.addrs[i][:], p.addrs[j][:]) < 0
+}
+
+// signature returns the signature of the proposer at index i, or nil if the
+// proposer has not signed yet.
+func (d *collationHeaderData) signature(i int) []byte {
+	if i < len(d.ProposerSignatures) {
+		return d.ProposerSignatures[i]
+	}
+	return nil
+}
+
+// proposerIndex returns the index of the proposer address, or -1 if the
+// address is not a proposer of the header.
+func (d *collationHeaderData) proposerIndex(addr common.Address) int {
+	for i, a := range d.ProposerAddresses {
+		if a != nil && *a == addr {
+			return i
+		}
+	}
+	return -1
+}
+
+// setSignature sets the signature of the proposer at index i.
+func (d *collationHeaderData) setSignature(i int, sig []byte) {
+	for len(d.ProposerSignatures) <= i {
+		d.ProposerSignatures = append(d.ProposerSignatures, nil)
+	}
+	d.ProposerSignatures[i] = sig
+}
+
+// proposerSignatureLength is the length of a secp256k1 signature in the [R || S || V] format.
+const proposerSignatureLength = 65
+
+// averageTxSize is the approximate size in bytes of a serialized transaction,
+// based on recent mainnet blocks (i.e. tx size = block size / # txs).
+const averageTxSize = 200
+
 // NewCollation initializes a collation and leaves it up to validators to serialize, deserialize
 // and provide the body and transactions upon creation.
-func NewCollation(header *CollationHeader, body []byte, transactions []*gethTypes.Transaction) *Collation {
-	return &Collation{
+func NewCollation(header *CollationHeader, body []byte, transactions []*gethTypes.Transaction, opts ...Option) *Collation {
+	c := &Collation{
 		header:       header,
 		body:         body,
 		transactions: transactions,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-// NewCollationHeader initializes a collation header struct.
-func NewCollationHeader(shardID *big.Int, chunkRoot *common.Hash, period *big.Int, proposerAddress *common.Address, proposerSignature [32]byte) *CollationHeader {
+// NewCollationHeader initializes a collation header struct with a single proposer
+// and validates its fields. The shardID is checked against the shard count of
+// the optional shard config, which defaults to the params package shard count.
+// Co-proposers can be added through AddProposer.
+func NewCollationHeader(shardID *big.Int, chunkRoot *common.Hash, period *big.Int, proposerAddress *common.Address, proposerSignature []byte, skipEvmExecution bool, config ...ShardConfig) (*CollationHeader, error) {
 	data := collationHeaderData{
-		ShardID:           shardID,
-		ChunkRoot:         chunkRoot,
-		Period:            period,
-		ProposerAddress:   proposerAddress,
-		ProposerSignature: proposerSignature,
+		ShardID:            shardID,
+		ChunkRoot:          chunkRoot,
+		Period:             period,
+		ProposerAddresses:  []*common.Address{proposerAddress},
+		ProposerSignatures: [][]byte{proposerSignature},
+		SkipEvmExecution:   skipEvmExecution,
+	}
+	var cfg ShardConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	header := &CollationHeader{data: data}
+	if err := header.validate(int64(cfg.shardCount())); err != nil {
+		return nil, fmt.Errorf("invalid collation header: %w", err)
+	}
+	return header, nil
+}
+
+// Validate checks that the header's fields are within the bounds expected by
+// the sharding manager contract before the header gets RLP encoded.
+func (h *CollationHeader) Validate() error {
+	return h.validate(params.DefaultConfig().ShardCount)
+}
+
+// validate checks the header's fields given the number of shards.
+func (h *CollationHeader) validate(shardCount int64) error {
+	if err := ValidateShardID(h.data.ShardID, int(shardCount)); err != nil {
+		return err
+	}
+	if h.data.Period == nil || h.data.Period.Sign() < 0 {
+		return fmt.Errorf("period %v must be non-negative: %w", h.data.Period, ErrInvalidPeriod)
+	}
+	if len(h.data.ProposerAddresses) == 0 {
+		return errors.New("header has no proposer address")
+	}
+	if len(h.data.ProposerSignatures) > len(h.data.ProposerAddresses) {
+		return fmt.Errorf("header has %d proposer signatures for %d proposers: %w", len(h.data.ProposerSignatures), len(h.data.ProposerAddresses), ErrInvalidProposerSignature)
+	}
+	for i, addr := range h.data.ProposerAddresses {
+		if addr == nil || *addr == (common.Address{}) {
+			return errors.New("proposer address cannot be the zero address")
+		}
+		if h.data.proposerIndex(*addr) != i {
+			return fmt.Errorf("proposer %s is listed more than once", addr.Hex())
+		}
+		// An empty signature is allowed so that proposers can build the header,
+		// compute its chunk root and sign it afterwards with SetProposerSignature.
+		if sig := h.data.signature(i); len(sig) != 0 && len(sig) != proposerSignatureLength {
+			return fmt.Errorf("proposer signature has length %d, wanted %d: %w", len(sig), proposerSignatureLength, ErrInvalidProposerSignature)
+		}
 	}
-	return &CollationHeader{data: data}
+	if h.data.BodyContentType > ContentTypeCompressed {
+		return fmt.Errorf("unknown body content type %d", h.data.BodyContentType)
+	}
+	return nil
 }
 
 // Hash takes the blake2b of the collation header's data contents.
+//
+// Deprecated: Hash is an alias of SignedHash. Use SignedHash to identify a signed
+// header or UnsignedHash to obtain the hash a proposer signs over.
 func (h *CollationHeader) Hash() (hash common.Hash) {
+	return h.SignedHash()
+}
+
+// SignedHash takes the blake2b of the collation header's data contents, including
+// the proposer signatures. Proposers are sorted by address before hashing.
+func (h *CollationHeader) SignedHash() (hash common.Hash) {
 	encoded, err := rlp.EncodeToBytes(h.data)
 	if err != nil {
 		log.Errorf("Failed to RLP encode data: %v", err)
@@ -74,23 +348,296 @@ func (h *CollationHeader) Hash() (hash common.Hash) {
 	return hashutil.Hash(encoded)
 }
 
-// AddSig adds the signature of proposer after collationHeader gets signed.
-func (h *CollationHeader) AddSig(sig [32]byte) {
-	h.data.ProposerSignature = sig
+// UnsignedHash takes the blake2b of the collation header's shardID, chunk root,
+// period and proposer addresses sorted by address, followed by the body content
+// type unless the body holds transactions. It excludes the proposer signatures,
+// so it stays stable when the header gets signed and is the hash every proposer
+// signs over. A single proposer is hashed as a plain address, which keeps the
+// hash of single proposer headers unchanged.
+func (h *CollationHeader) UnsignedHash() (hash common.Hash) {
+	addrs, _ := h.data.sortedProposers()
+	var proposers interface{} = addrs
+	if len(addrs) <= 1 {
+		var addr *common.Address
+		if len(addrs) == 1 {
+			addr = addrs[0]
+		}
+		proposers = addr
+	}
+	fields := []interface{}{
+		h.data.ShardID,
+		h.data.ChunkRoot,
+		h.data.Period,
+		proposers,
+	}
+	if h.data.BodyContentType != ContentTypeTransactions {
+		fields = append(fields, h.data.BodyContentType)
+	}
+	encoded, err := rlp.EncodeToBytes(fields)
+	if err != nil {
+		log.Errorf("Failed to RLP encode data: %v", err)
+	}
+	return hashutil.Hash(encoded)
 }
 
-// Sig is the signature the collation corresponds to.
-func (h *CollationHeader) Sig() [32]byte { return h.data.ProposerSignature }
+// HashNoSig takes the blake2b of the collation header's data contents, excluding
+// the proposer signature.
+//
+// Deprecated: use UnsignedHash instead.
+func (h *CollationHeader) HashNoSig() (hash common.Hash) {
+	return h.UnsignedHash()
+}
+
+// AddProposer adds an unsigned co-proposer to the header. Since the unsigned hash
+// covers every proposer address, proposers should only sign the header once all
+// of them have been added.
+func (h *CollationHeader) AddProposer(addr *common.Address) error {
+	if addr == nil || *addr == (common.Address{}) {
+		return errors.New("proposer address cannot be the zero address")
+	}
+	if h.data.proposerIndex(*addr) >= 0 {
+		return fmt.Errorf("%s is already a proposer of the header", addr.Hex())
+	}
+	h.data.setSignature(len(h.data.ProposerAddresses), nil)
+	h.data.ProposerAddresses = append(h.data.ProposerAddresses, addr)
+	return nil
+}
+
+// Sign signs the header's unsigned hash with a proposer's private key and sets
+// the resulting signature as that proposer's signature.
+func (h *CollationHeader) Sign(privKey *ecdsa.PrivateKey) error {
+	i := h.data.proposerIndex(crypto.PubkeyToAddress(privKey.PublicKey))
+	if i < 0 {
+		return fmt.Errorf("%s is not a proposer of the header", crypto.PubkeyToAddress(privKey.PublicKey).Hex())
+	}
+	hash := h.UnsignedHash()
+	sig, err := crypto.Sign(hash.Bytes(), privKey)
+	if err != nil {
+		return fmt.Errorf("could not sign collation header: %v", err)
+	}
+	h.data.setSignature(i, sig)
+	return nil
+}
+
+// VerifyProposerSignature recovers the signer of the signature of the proposer
+// with the given public key, and checks that it matches the proposer address.
+func (h *CollationHeader) VerifyProposerSignature(pubkey *ecdsa.PublicKey) error {
+	if pubkey == nil {
+		return errors.New("no public key provided to verify the proposer signature")
+	}
+	i := h.data.proposerIndex(crypto.PubkeyToAddress(*pubkey))
+	if i < 0 {
+		return fmt.Errorf("%s is not a proposer of the header: %w", crypto.PubkeyToAddress(*pubkey).Hex(), ErrInvalidProposerSignature)
+	}
+	return h.verifySignature(i)
+}
+
+// VerifyAllProposerSignatures checks that every proposer of the header signed
+// its unsigned hash.
+func (h *CollationHeader) VerifyAllProposerSignatures() error {
+	if len(h.data.ProposerAddresses) == 0 {
+		return errors.New("header has no proposer address set")
+	}
+	for i := range h.data.ProposerAddresses {
+		if err := h.verifySignature(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifySignature recovers the signer of the signature at index i and checks it
+// against the proposer address at the same index.
+func (h *CollationHeader) verifySignature(i int) error {
+	addr := h.data.ProposerAddresses[i]
+	if addr == nil {
+		return errors.New("header has no proposer address set")
+	}
+
+	hash := h.UnsignedHash()
+	signerKey, err := crypto.SigToPub(hash.Bytes(), h.data.signature(i))
+	if err != nil {
+		return fmt.Errorf("could not recover signer from the signature of proposer %s: %v: %w", addr.Hex(), err, ErrInvalidProposerSignature)
+	}
+	if signer := crypto.PubkeyToAddress(*signerKey); signer != *addr {
+		return fmt.Errorf("proposer signature was signed by %s, wanted proposer %s: %w", signer.Hex(), addr.Hex(), ErrInvalidProposerSignature)
+	}
+	return nil
+}
+
+// AddSig adds the signature of the first proposer after collationHeader gets signed.
+func (h *CollationHeader) AddSig(sig []byte) {
+	h.data.setSignature(0, sig)
+}
+
+// SetProposerSignature sets the signature of the first proposer of an unsigned
+// header. It refuses to overwrite an existing signature to prevent a header from
+// being signed twice.
+func (h *CollationHeader) SetProposerSignature(sig []byte) error {
+	if len(sig) != proposerSignatureLength {
+		return fmt.Errorf("proposer signature has length %d, wanted %d: %w", len(sig), proposerSignatureLength, ErrInvalidProposerSignature)
+	}
+	if len(h.data.signature(0)) != 0 {
+		return errors.New("collation header is already signed")
+	}
+	h.data.setSignature(0, sig)
+	return nil
+}
+
+// ClearSignature removes the signatures of all proposers from the header.
+func (h *CollationHeader) ClearSignature() {
+	h.data.ProposerSignatures = nil
+}
+
+// SetAggregateSignature sets the BLS signature aggregated from the committee
+// members that approved the header. Committee members sign the header's
+// UnsignedHash, and the aggregate can be replaced as more approvals come in.
+func (h *CollationHeader) SetAggregateSignature(aggSig []byte) error {
+	if len(aggSig) != blsSignatureLength {
+		return fmt.Errorf("aggregate signature has length %d, wanted %d", len(aggSig), blsSignatureLength)
+	}
+	h.data.AggregateProposerSignature = aggSig
+	return nil
+}
+
+// AggregateSig is the aggregated committee signature of the header.
+func (h *CollationHeader) AggregateSig() []byte { return h.data.AggregateProposerSignature }
+
+// Sig is the signature of the first proposer of the collation.
+func (h *CollationHeader) Sig() []byte { return h.data.signature(0) }
+
+// ProposerAddresses are the addresses of the proposers co-signing the collation.
+func (h *CollationHeader) ProposerAddresses() []*common.Address { return h.data.ProposerAddresses }
+
+// ProposerSignatures are the proposers' signatures, at the index of their address.
+// Proposers that have not signed yet have an empty signature.
+func (h *CollationHeader) ProposerSignatures() [][]byte {
+	sigs := make([][]byte, len(h.data.ProposerAddresses))
+	for i := range sigs {
+		sigs[i] = h.data.signature(i)
+	}
+	return sigs
+}
+
+// isSigned reports whether every proposer of the header signed it.
+func (h *CollationHeader) isSigned() bool {
+	for i := range h.data.ProposerAddresses {
+		if len(h.data.signature(i)) == 0 {
+			return false
+		}
+	}
+	return len(h.data.ProposerAddresses) > 0
+}
 
 // ShardID the collation corresponds to.
 func (h *CollationHeader) ShardID() *big.Int { return h.data.ShardID }
 
+// ShardIDUint64 returns the shardID as a uint64, and false if it is nil or does
+// not fit in a uint64.
+func (h *CollationHeader) ShardIDUint64() (uint64, bool) {
+	if h.data.ShardID == nil || !h.data.ShardID.IsUint64() {
+		return 0, false
+	}
+	return h.data.ShardID.Uint64(), true
+}
+
+// ProposerAddressString returns the EIP-55 checksum encoding of the first
+// proposer's address, or an empty string if the header has no proposer.
+func (h *CollationHeader) ProposerAddressString() string {
+	if len(h.data.ProposerAddresses) == 0 || h.data.ProposerAddresses[0] == nil {
+		return ""
+	}
+	return h.data.ProposerAddresses[0].Hex()
+}
+
 // Period the collation corresponds to.
 func (h *CollationHeader) Period() *big.Int { return h.data.Period }
 
 // ChunkRoot of the serialized collation body.
 func (h *CollationHeader) ChunkRoot() *common.Hash { return h.data.ChunkRoot }
 
+// SkipEvmExecution reports whether the collation's transactions skip EVM execution.
+func (h *CollationHeader) SkipEvmExecution() bool { return h.data.SkipEvmExecution }
+
+// WithSkipEvm sets whether the collation's transactions skip EVM execution. The flag
+// is encoded into every serialized blob, so changing it after CalculateChunkRoot
+// invalidates the chunk root until the body is serialized and merklized again.
+func (h *CollationHeader) WithSkipEvm(skip bool) {
+	h.data.SkipEvmExecution = skip
+}
+
+// BodyContentType reports how the collation body is encoded, as one of the
+// ContentType constants.
+func (h *CollationHeader) BodyContentType() uint8 { return h.data.BodyContentType }
+
+// WithBodyContentType sets how the collation body is encoded. The content type
+// is covered by UnsignedHash, so it needs to be set before the header is signed.
+func (h *CollationHeader) WithBodyContentType(contentType uint8) {
+	h.data.BodyContentType = contentType
+}
+
+// Compressed reports whether the collation body is transmitted snappy compressed,
+// which is the case for the ContentTypeCompressed body content type.
+func (h *CollationHeader) Compressed() bool { return h.data.compressed() }
+
+// compressed reports whether the body content type is ContentTypeCompressed.
+func (d *collationHeaderData) compressed() bool {
+	return d.BodyContentType == ContentTypeCompressed
+}
+
+// Equal checks if two collation headers have the same data fields. Integers are
+// compared by value, nil fields are only equal to other nil fields and proposers
+// are compared regardless of their order.
+func (h *CollationHeader) Equal(other *CollationHeader) bool {
+	if h == nil || other == nil {
+		return h == other
+	}
+	a, b := h.data, other.data
+	return bigIntEqual(a.ShardID, b.ShardID) &&
+		bigIntEqual(a.Period, b.Period) &&
+		((a.ChunkRoot == nil && b.ChunkRoot == nil) || (a.ChunkRoot != nil && b.ChunkRoot != nil && *a.ChunkRoot == *b.ChunkRoot)) &&
+		proposersEqual(&a, &b) &&
+		a.SkipEvmExecution == b.SkipEvmExecution &&
+		a.BodyContentType == b.BodyContentType &&
+		bytes.Equal(a.AggregateProposerSignature, b.AggregateProposerSignature)
+}
+
+// Less orders collation headers by shardID and then by period, which is useful
+// for keeping headers in priority queues.
+func (h *CollationHeader) Less(other *CollationHeader) bool {
+	if cmp := h.data.ShardID.Cmp(other.data.ShardID); cmp != 0 {
+		return cmp < 0
+	}
+	return h.data.Period.Cmp(other.data.Period) < 0
+}
+
+// proposersEqual compares the proposer addresses and signatures of two headers
+// after sorting them by address.
+func proposersEqual(a *collationHeaderData, b *collationHeaderData) bool {
+	aAddrs, aSigs := a.sortedProposers()
+	bAddrs, bSigs := b.sortedProposers()
+	if len(aAddrs) != len(bAddrs) {
+		return false
+	}
+	for i := range aAddrs {
+		if (aAddrs[i] == nil) != (bAddrs[i] == nil) || (aAddrs[i] != nil && *aAddrs[i] != *bAddrs[i]) {
+			return false
+		}
+		if !bytes.Equal(aSigs[i], bSigs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// bigIntEqual compares two possibly nil integers by value.
+func bigIntEqual(a *big.Int, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
+
 // EncodeRLP gives an encoded representation of the collation header.
 func (h *CollationHeader) EncodeRLP() ([]byte, error) {
 	return rlp.EncodeToBytes(&h.data)
@@ -101,6 +648,61 @@ func (h *CollationHeader) DecodeRLP(s *rlp.Stream) error {
 	return s.Decode(&h.data)
 }
 
+// Encode gives the RLP encoding of the collation header, as EncodeRLP does.
+func (h *CollationHeader) Encode() ([]byte, error) {
+	return h.EncodeRLP()
+}
+
+// Decode populates the collation header from data produced by Encode.
+func (h *CollationHeader) Decode(data []byte) error {
+	return h.DecodeRLP(rlp.NewStream(bytes.NewReader(data), uint64(len(data))))
+}
+
+// EncodeHeaders RLP encodes a list of collation headers as a single payload,
+// where every element uses the same encoding as EncodeRLP.
+func EncodeHeaders(headers []*CollationHeader) ([]byte, error) {
+	data := make([]*collationHeaderData, len(headers))
+	for i, h := range headers {
+		if h == nil {
+			return nil, fmt.Errorf("header %d is nil", i)
+		}
+		data[i] = &h.data
+	}
+	return rlp.EncodeToBytes(data)
+}
+
+// DecodeHeaders decodes a payload produced by either EncodeHeaders or the
+// EncodeRLP of a single header. A batch is told apart from a single header by
+// its first element, which is a list in a batch and the shardID otherwise.
+func DecodeHeaders(data []byte) ([]*CollationHeader, error) {
+	s := rlp.NewStream(bytes.NewReader(data), uint64(len(data)))
+	if _, err := s.List(); err != nil {
+		return nil, fmt.Errorf("could not decode headers: %v", err)
+	}
+	kind, _, err := s.Kind()
+	if err != nil && err != rlp.EOL {
+		return nil, fmt.Errorf("could not decode headers: %v", err)
+	}
+
+	if err == nil && kind != rlp.List {
+		header := &CollationHeader{}
+		if err := rlp.DecodeBytes(data, &header.data); err != nil {
+			return nil, fmt.Errorf("could not decode header: %v", err)
+		}
+		return []*CollationHeader{header}, nil
+	}
+
+	var batch []*collationHeaderData
+	if err := rlp.DecodeBytes(data, &batch); err != nil {
+		return nil, fmt.Errorf("could not decode headers: %v", err)
+	}
+	headers := make([]*CollationHeader, len(batch))
+	for i, d := range batch {
+		headers[i] = &CollationHeader{data: *d}
+	}
+	return headers, nil
+}
+
 // Header returns the collation's header.
 func (c *Collation) Header() *CollationHeader { return c.header }
 
@@ -110,21 +712,168 @@ func (c *Collation) Body() []byte { return c.body }
 // Transactions returns an array of tx's in the collation.
 func (c *Collation) Transactions() []*gethTypes.Transaction { return c.transactions }
 
-// ProposerAddress is the coinbase addr of the creator for the collation.
+// TransactionAt returns the transaction at the index of the collation's
+// transactions.
+func (c *Collation) TransactionAt(index int) (*gethTypes.Transaction, error) {
+	if index < 0 || index >= len(c.transactions) {
+		return nil, fmt.Errorf("transaction index %d out of range, collation has %d transactions", index, len(c.transactions))
+	}
+	return c.transactions[index], nil
+}
+
+// TransactionCount returns the number of transactions in the collation.
+func (c *Collation) TransactionCount() int {
+	return len(c.transactions)
+}
+
+// TransactionByHash looks up a transaction of the collation and its index. The
+// index of transaction hashes is built on the first lookup, so that subsequent
+// lookups do not need to scan the transactions.
+func (c *Collation) TransactionByHash(hash common.Hash) (*gethTypes.Transaction, int, bool) {
+	if c.txIndex == nil {
+		c.txIndex = make(map[common.Hash]int, len(c.transactions))
+		for i, tx := range c.transactions {
+			if _, ok := c.txIndex[tx.Hash()]; !ok {
+				c.txIndex[tx.Hash()] = i
+			}
+		}
+	}
+	i, ok := c.txIndex[hash]
+	if !ok {
+		return nil, 0, false
+	}
+	return c.transactions[i], i, true
+}
+
+// setBody replaces the collation body, dropping the transaction index since the
+// body no longer necessarily corresponds to the indexed transactions.
+func (c *Collation) setBody(body []byte) {
+	c.body = body
+	c.txIndex = nil
+}
+
+// ProposerAddress is the coinbase addr of the first proposer of the collation.
 func (c *Collation) ProposerAddress() *common.Address {
-	return c.header.data.ProposerAddress
+	if len(c.header.data.ProposerAddresses) == 0 {
+		return nil
+	}
+	return c.header.data.ProposerAddresses[0]
+}
+
+// Fingerprint returns the FNV-64a hash of the collation's shardID, period and
+// chunk root. It is much cheaper to compute than the RLP based header hashes and
+// is meant for seen-sets and bloom filters, where rare collisions are acceptable.
+func (c *Collation) Fingerprint() uint64 {
+	h := fnv.New64a()
+	var shardID, period, chunkRoot common.Hash
+	if c.header.data.ShardID != nil {
+		shardID = common.BigToHash(c.header.data.ShardID)
+	}
+	if c.header.data.Period != nil {
+		period = common.BigToHash(c.header.data.Period)
+	}
+	if c.header.data.ChunkRoot != nil {
+		chunkRoot = *c.header.data.ChunkRoot
+	}
+	h.Write(shardID[:])
+	h.Write(period[:])
+	h.Write(chunkRoot[:])
+	return h.Sum64()
+}
+
+// IsExpired checks if the collation's validity window, which starts at the header's
+// period and lasts for the configured period window, is over by currentPeriod.
+func (c *Collation) IsExpired(currentPeriod *big.Int) bool {
+	return isPeriodExpired(c.header.Period(), c.config.periodWindow(), currentPeriod)
+}
+
+// isPeriodExpired checks if the window of a collation proposed in period is
+// over by currentPeriod.
+func isPeriodExpired(period *big.Int, window int64, currentPeriod *big.Int) bool {
+	end := new(big.Int).Add(period, big.NewInt(window))
+	return currentPeriod.Cmp(end) >= 0
+}
+
+// IsPrematurely checks if currentPeriod is before the collation's period, meaning
+// the collation cannot be included yet.
+func (c *Collation) IsPrematurely(currentPeriod *big.Int) bool {
+	return currentPeriod.Cmp(c.header.Period()) < 0
 }
 
 // CalculateChunkRoot updates the collation header's chunk root based on the body.
+// The chunk root is the root of a binary Merkle tree over the body's chunks, which
+// allows proving the inclusion of individual chunks through ChunkProof.
 func (c *Collation) CalculateChunkRoot() {
-	chunks := BytesToChunks(c.body)          // wrapper allowing us to merklizing the chunks.
-	chunkRoot := gethTypes.DeriveSha(chunks) // merklize the serialized blobs.
+	defer observeDuration(chunkRootDuration, time.Now())
+	chunkRoot := chunkRootFromBody(c.body) // merklize the serialized blobs.
 	c.header.data.ChunkRoot = &chunkRoot
+	c.bodyHash = hashutil.Hash(c.body)
+	c.logEvent(EventChunkRootCalculated, map[string]interface{}{"chunkRoot": chunkRoot.Hex()})
+}
+
+// Pad appends zero bytes to the collation body until it ends on a chunk
+// boundary and recalculates the chunk root. The chunk root already treats the
+// last chunk as zero-padded, so padding does not change it, but Pad must be
+// called before transmitting a collation so that implementations computing the
+// chunk root over the raw body agree with this one.
+func (c *Collation) Pad() error {
+	padding := (chunkSize - len(c.body)%chunkSize) % chunkSize
+	if csl := c.config.collationSizeLimit(); int64(len(c.body)+padding) > csl {
+		return fmt.Errorf("padded body of %d bytes exceeds the collation size limit %d: %w", len(c.body)+padding, csl, ErrCollationTooLarge)
+	}
+	if padding > 0 {
+		body := make([]byte, len(c.body)+padding)
+		copy(body, c.body)
+		c.setBody(body)
+	}
+	c.CalculateChunkRoot()
+	return nil
+}
+
+// UnpadBody returns the collation body without its trailing zero bytes. The
+// body itself is left untouched. Since zero bytes at the end of the original
+// body cannot be told apart from padding, they are stripped as well.
+func (c *Collation) UnpadBody() []byte {
+	return bytes.TrimRight(c.body, "\x00")
+}
+
+// IsChunkRootFresh checks that the header's chunk root still corresponds to the
+// collation body. If the chunk root was calculated through CalculateChunkRoot,
+// this only rehashes the body instead of merklizing it again.
+func (c *Collation) IsChunkRootFresh() bool {
+	if c.header.ChunkRoot() == nil {
+		return false
+	}
+	if c.bodyHash != (common.Hash{}) {
+		return hashutil.Hash(c.body) == c.bodyHash
+	}
+	return chunkRootFromBody(c.body) == *c.header.ChunkRoot()
+}
+
+// VerifyBodyIntegrity recomputes the chunk root of the collation body and
+// checks it against the header's chunk root, returning ErrChunkRootMismatch if
+// they differ. Unlike IsChunkRootFresh, it always merklizes the body, so it
+// must be used on collations whose body was received or read from disk.
+func (c *Collation) VerifyBodyIntegrity() error {
+	if c.header.ChunkRoot() == nil {
+		c.logEvent(EventBodyVerificationFailed, map[string]interface{}{"error": "no chunk root"})
+		return fmt.Errorf("collation header has no chunk root: %w", ErrChunkRootMismatch)
+	}
+	if chunkRoot := chunkRootFromBody(c.body); chunkRoot != *c.header.ChunkRoot() {
+		c.logEvent(EventBodyVerificationFailed, map[string]interface{}{"chunkRoot": chunkRoot.Hex()})
+		return fmt.Errorf("collation body has chunk root %s, header has %s: %w", chunkRoot.Hex(), c.header.ChunkRoot().Hex(), ErrChunkRootMismatch)
+	}
+	c.bodyHash = hashutil.Hash(c.body)
+	c.logEvent(EventBodyVerified, nil)
+	return nil
 }
 
 // CalculatePOC calculates the Proof of Custody given the collation body and
 // some salt, which is appended to each chunk in the collation body before it
 // is hashed.
+//
+// Deprecated: custody challenges use CalculateSaltedChunkRoot, whose root is
+// built from the chunk tree like the header's chunk root.
 func (c *Collation) CalculatePOC(salt []byte) common.Hash {
 	body := make([]byte, 0, len(c.body)*(1+len(salt)))
 
@@ -134,22 +883,17 @@ func (c *Collation) CalculatePOC(salt []byte) common.Hash {
 	if len(c.body) == 0 {
 		body = salt
 	}
-	chunks := BytesToChunks(body)      // wrapper allowing us to merklizing the chunks.
+	chunks := ChunksFromBody(body)     // wrapper allowing us to merklizing the chunks.
 	return gethTypes.DeriveSha(chunks) // merklize the serialized blobs.
 }
 
-// BytesToChunks takes the collation body bytes and wraps it into type Chunks,
-// which can be merklized.
-func BytesToChunks(body []byte) Chunks {
-	return Chunks(body)
-}
-
 // convertTxToRawBlob transactions into RawBlobs. This step encodes transactions uses RLP encoding
-func convertTxToRawBlob(txs []*gethTypes.Transaction) ([]*shardutil.RawBlob, error) {
+// and flags every blob with skipEvm.
+func convertTxToRawBlob(txs []*gethTypes.Transaction, skipEvm bool) ([]*shardutil.RawBlob, error) {
 	blobs := make([]*shardutil.RawBlob, len(txs))
 	for i := 0; i < len(txs); i++ {
 		err := error(nil)
-		blobs[i], err = shardutil.NewRawBlob(txs[i], false)
+		blobs[i], err = shardutil.NewRawBlob(txs[i], skipEvm)
 		if err != nil {
 			return nil, err
 		}
@@ -157,9 +901,233 @@ func convertTxToRawBlob(txs []*gethTypes.Transaction) ([]*shardutil.RawBlob, err
 	return blobs, nil
 }
 
+// TotalGas sums the gas limits of the collation's transactions, returning an
+// error if the sum overflows a uint64.
+func (c *Collation) TotalGas() (uint64, error) {
+	var total uint64
+	for _, tx := range c.transactions {
+		if total+tx.Gas() < total {
+			return 0, errors.New("total gas of collation transactions overflows uint64")
+		}
+		total += tx.Gas()
+	}
+	return total, nil
+}
+
+// AccumulatedFees sums the fees the proposer earns from the collation's
+// transactions, gas price times gas limit for each of them. Fees are computed
+// with big integers as the product easily overflows a uint64, and a nil gas
+// price counts as zero.
+func (c *Collation) AccumulatedFees() (*big.Int, error) {
+	total := new(big.Int)
+	fee := new(big.Int)
+	for i, tx := range c.transactions {
+		if tx == nil {
+			return nil, fmt.Errorf("transaction %d of collation is nil", i)
+		}
+		price := tx.GasPrice()
+		if price == nil {
+			continue
+		}
+		total.Add(total, fee.Mul(price, new(big.Int).SetUint64(tx.Gas())))
+	}
+	return total, nil
+}
+
+// FeeCapExceeded checks whether the collation's accumulated fees exceed maxFee.
+// Collations whose fees cannot be computed are considered to exceed the cap.
+func (c *Collation) FeeCapExceeded(maxFee *big.Int) bool {
+	fees, err := c.AccumulatedFees()
+	if err != nil {
+		return true
+	}
+	return fees.Cmp(maxFee) > 0
+}
+
+// ProposerFee returns the proposer's cut of the collation's accumulated fees,
+// AccumulatedFees() * feeNumerator / feeDenominator rounded down. The fraction
+// lets the protocol configure the cut, which must be non-negative, and the
+// result must fit in the uint256 the reward is paid out as on the main chain.
+func (c *Collation) ProposerFee(feeNumerator, feeDenominator *big.Int) (*big.Int, error) {
+	if feeNumerator == nil || feeDenominator == nil {
+		return nil, errors.New("proposer fee fraction is not set")
+	}
+	if feeDenominator.Sign() == 0 {
+		return nil, errors.New("proposer fee denominator is zero")
+	}
+	if feeNumerator.Sign() < 0 || feeDenominator.Sign() < 0 {
+		return nil, fmt.Errorf("proposer fee fraction %v/%v must be non-negative", feeNumerator, feeDenominator)
+	}
+	fees, err := c.AccumulatedFees()
+	if err != nil {
+		return nil, err
+	}
+	fee := fees.Mul(fees, feeNumerator)
+	fee.Div(fee, feeDenominator)
+	if fee.BitLen() > 256 {
+		return nil, fmt.Errorf("proposer fee %v overflows uint256", fee)
+	}
+	return fee, nil
+}
+
+// Serialize converts the collation's transactions into a serialized blob, enforcing
+// the collation size and gas limits of the collation's shard config. Blobs are
+// flagged according to the header's SkipEvmExecution field.
+func (c *Collation) Serialize() ([]byte, error) {
+	defer observeDuration(serializeDuration, time.Now())
+	serialized, err := c.serialize()
+	if err != nil {
+		c.logEvent(EventSerializeFailed, map[string]interface{}{"error": err.Error()})
+		return nil, err
+	}
+	serializeBytes.Set(float64(len(serialized)))
+	c.logEvent(EventSerialized, map[string]interface{}{"size": len(serialized), "transactions": len(c.transactions)})
+	return serialized, nil
+}
+
+// serialize checks the gas limit and serializes the transactions for Serialize.
+func (c *Collation) serialize() ([]byte, error) {
+	totalGas, err := c.TotalGas()
+	if err != nil {
+		return nil, err
+	}
+	if gasLimit := c.config.gasLimit(); totalGas > gasLimit {
+		return nil, fmt.Errorf("the collation gas %d exceeded the collation gas limit %d", totalGas, gasLimit)
+	}
+	return serializeTxToBlob(c.transactions, c.header.SkipEvmExecution(), c.config.collationSizeLimit())
+}
+
+// EstimatedSize returns the size of the collation body without serializing it.
+// A collation whose body was not serialized yet is estimated from its number of
+// transactions, so the estimate may be off for unusually small or large ones.
+func (c *Collation) EstimatedSize() int {
+	if len(c.body) > 0 {
+		return len(c.body)
+	}
+	return len(c.transactions) * averageTxSize
+}
+
+// ActualSize returns the size of the body the collation's transactions
+// serialize into.
+func (c *Collation) ActualSize() (int, error) {
+	serialized, err := c.Serialize()
+	if err != nil {
+		return 0, err
+	}
+	return len(serialized), nil
+}
+
+// Reserialize re-encodes the collation's transactions into its body and
+// recalculates the header's chunk root. The transactions, body and chunk root
+// are separate fields, so after the transactions change this is the only safe
+// way to bring the body and chunk root back in sync with them. The collation is
+// left untouched if the transactions cannot be serialized.
+func (c *Collation) Reserialize() error {
+	body, err := c.Serialize()
+	if err != nil {
+		return err
+	}
+	c.setBody(body)
+	c.CalculateChunkRoot()
+	return nil
+}
+
+// DeduplicateTransactions removes every transaction whose hash already appeared
+// earlier in the collation, keeping the first occurrence, and reserializes the
+// body when anything was removed. It returns the number of removed duplicates.
+// The collation is left untouched if the remaining transactions cannot be
+// serialized, in which case no duplicates are reported as removed.
+func (c *Collation) DeduplicateTransactions() int {
+	seen := make(map[common.Hash]bool, len(c.transactions))
+	unique := make([]*gethTypes.Transaction, 0, len(c.transactions))
+	for _, tx := range c.transactions {
+		if seen[tx.Hash()] {
+			continue
+		}
+		seen[tx.Hash()] = true
+		unique = append(unique, tx)
+	}
+	removed := len(c.transactions) - len(unique)
+	if removed == 0 {
+		return 0
+	}
+
+	original := c.transactions
+	c.transactions = unique
+	if err := c.Reserialize(); err != nil {
+		log.Warnf("Could not reserialize deduplicated collation: %v", err)
+		c.transactions = original
+		return 0
+	}
+	return removed
+}
+
+// ReplaceTx replaces the transaction with oldHash by newTx, such as for a
+// proposer to bump its fee, then reserializes the body and recalculates the
+// chunk root. newTx must pay a strictly higher gas price than the transaction
+// it replaces. The collation is left untouched if the body cannot be
+// reserialized.
+func (c *Collation) ReplaceTx(oldHash common.Hash, newTx *gethTypes.Transaction) error {
+	if newTx == nil {
+		return errors.New("no replacement transaction provided")
+	}
+	oldTx, i, ok := c.TransactionByHash(oldHash)
+	if !ok {
+		return fmt.Errorf("cannot replace transaction %s: %w", oldHash.Hex(), ErrTxNotFound)
+	}
+	if newTx.GasPrice().Cmp(oldTx.GasPrice()) <= 0 {
+		return fmt.Errorf("replacement gas price %v must exceed %v: %w", newTx.GasPrice(), oldTx.GasPrice(), ErrGasPriceTooLow)
+	}
+
+	original := c.transactions
+	c.transactions = append([]*gethTypes.Transaction(nil), original...)
+	c.transactions[i] = newTx
+	if err := c.Reserialize(); err != nil {
+		c.transactions = original
+		return fmt.Errorf("could not serialize collation body: %w", err)
+	}
+	return nil
+}
+
+// WithTransactions returns a new collation holding txs, with its body
+// serialized and its chunk root calculated, leaving the collation unchanged.
+// The new collation keeps the shardID, period, proposers and config, but its
+// header is unsigned since the signatures covered the old chunk root. An error
+// is returned if the transactions exceed the collation size or gas limit.
+func (c *Collation) WithTransactions(txs []*gethTypes.Transaction) (*Collation, error) {
+	data := c.header.data
+	data.ProposerAddresses = append([]*common.Address(nil), data.ProposerAddresses...)
+	data.ProposerSignatures = nil
+	data.AggregateProposerSignature = nil
+
+	collation := NewCollation(&CollationHeader{data: data}, nil, append([]*gethTypes.Transaction(nil), txs...), WithConfig(c.config), WithEventLogger(c.events))
+	if err := collation.Reserialize(); err != nil {
+		return nil, fmt.Errorf("could not serialize collation body: %w", err)
+	}
+	return collation, nil
+}
+
+// Filter returns a new collation holding only the transactions for which keep
+// returns true, such as to drop transactions that became invalid since they were
+// added. The new collation is built as by WithTransactions.
+func (c *Collation) Filter(keep func(*gethTypes.Transaction) bool) (*Collation, error) {
+	var txs []*gethTypes.Transaction
+	for _, tx := range c.transactions {
+		if keep(tx) {
+			txs = append(txs, tx)
+		}
+	}
+	return c.WithTransactions(txs)
+}
+
 // SerializeTxToBlob converts transactions using two steps. First performs RLP encoding, and then blob encoding.
 func SerializeTxToBlob(txs []*gethTypes.Transaction) ([]byte, error) {
-	blobs, err := convertTxToRawBlob(txs)
+	return serializeTxToBlob(txs, false, params.DefaultCollationSizeLimit())
+}
+
+// serializeTxToBlob serializes transactions and checks the result against the given size limit.
+func serializeTxToBlob(txs []*gethTypes.Transaction, skipEvm bool, csl int64) ([]byte, error) {
+	blobs, err := convertTxToRawBlob(txs, skipEvm)
 	if err != nil {
 		return nil, err
 	}
@@ -169,53 +1137,125 @@ func SerializeTxToBlob(txs []*gethTypes.Transaction) ([]byte, error) {
 		return nil, err
 	}
 
-	csl := params.DefaultCollationSizeLimit()
 	if int64(len(serializedTx)) > csl {
-		return nil, fmt.Errorf("the serialized body size %d exceeded the collation size limit %d", len(serializedTx), csl)
+		return nil, fmt.Errorf("the serialized body size %d exceeded the collation size limit %d: %w", len(serializedTx), csl, ErrCollationTooLarge)
 	}
 
 	return serializedTx, nil
 }
 
-// convertRawBlobToTx converts raw blobs back to their original transactions.
-func convertRawBlobToTx(rawBlobs []shardutil.RawBlob) ([]*gethTypes.Transaction, error) {
-	blobs := make([]*gethTypes.Transaction, len(rawBlobs))
+// Compress serializes the collation's transactions and compresses the result using
+// snappy. The size limit is checked against the uncompressed body, and the header's
+// body content type is set to ContentTypeCompressed so receivers know to decompress
+// the body. The content type is covered by UnsignedHash, so the header needs to be
+// signed after compressing.
+func (c *Collation) Compress() ([]byte, error) {
+	if c.header.BodyContentType() == ContentTypeRawData {
+		return nil, errors.New("cannot compress a raw data body as transactions")
+	}
+	serialized, err := c.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	c.header.WithBodyContentType(ContentTypeCompressed)
+	return snappy.Encode(nil, serialized), nil
+}
 
-	for i := 0; i < len(rawBlobs); i++ {
-		blobs[i] = gethTypes.NewTransaction(0, common.HexToAddress("0x"), nil, 0, nil, nil)
+// DecompressAndDeserialize decompresses a snappy compressed collation body and
+// converts it back to the original txs.
+func DecompressAndDeserialize(data []byte) (*[]*gethTypes.Transaction, error) {
+	serialized, err := snappy.Decode(nil, data)
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress collation body: %v", err)
+	}
+	return DeserializeBlobToTx(BytesBodyReader(serialized))
+}
 
-		err := shardutil.ConvertFromRawBlob(&rawBlobs[i], blobs[i])
-		if err != nil {
-			return nil, fmt.Errorf("creation of transactions from raw blobs failed: %v", err)
-		}
+// Deserialize decodes the collation body into its transactions according to
+// the header's body content type. Raw data bodies hold no transactions.
+func (c *Collation) Deserialize() error {
+	txs, err := deserializeBody(c.header.BodyContentType(), c.body)
+	if err != nil {
+		return err
 	}
-	return blobs, nil
+	c.transactions = txs
+	c.txIndex = nil
+	return nil
 }
 
-// DeserializeBlobToTx takes byte array blob and converts it back
-// to original txs and returns the txs in tx array.
-func DeserializeBlobToTx(serialisedBlob []byte) (*[]*gethTypes.Transaction, error) {
-	deserializedBlobs, err := shardutil.Deserialize(serialisedBlob)
+// deserializeBody decodes the transactions of a body with the given content type.
+func deserializeBody(contentType uint8, body []byte) ([]*gethTypes.Transaction, error) {
+	var txs *[]*gethTypes.Transaction
+	var err error
+	switch contentType {
+	case ContentTypeTransactions:
+		txs, err = DeserializeBlobToTx(BytesBodyReader(body))
+	case ContentTypeRawData:
+		return nil, nil
+	case ContentTypeCompressed:
+		txs, err = DecompressAndDeserialize(body)
+	default:
+		return nil, fmt.Errorf("unknown body content type %d", contentType)
+	}
 	if err != nil {
 		return nil, err
 	}
+	return *txs, nil
+}
 
-	txs, err := convertRawBlobToTx(deserializedBlobs)
+// DeserializeBlobToTx reads the serialized blobs of the body reader and
+// converts them back to the original txs. Closing the reader is left to the
+// caller.
+func DeserializeBlobToTx(body BodyReader) (*[]*gethTypes.Transaction, error) {
+	defer observeDuration(deserializeDuration, time.Now())
+	reader := &CollationBodyReader{blobs: body}
 
-	if err != nil {
-		return nil, err
+	txs := []*gethTypes.Transaction{}
+	for {
+		tx, err := reader.NextTransaction()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
 	}
 
 	return &txs, nil
 }
 
-// Chunks is a wrapper around a chunk array to implement DerivableList,
-// which allows us to Merklize the chunks into the chunkRoot.
-type Chunks []byte
+// chunkSize is the number of bytes in a single chunk of a collation body.
+const chunkSize = 32
+
+// Chunks is a collation body split into fixed-size 32 byte blocks, the leaves
+// of the chunk tree. It implements DerivableList, which allows us to Merklize the
+// chunks into the chunkRoot.
+//
+// Migration note: chunks used to be single bytes of the body, so a chunk tree had
+// one leaf per byte. Chunk roots and chunk proofs computed with the old layout
+// do not match the ones computed now, and bodies saved with the old layout need
+// their chunk roots recalculated through CalculateChunkRoot or SaveBody.
+type Chunks [][chunkSize]byte
+
+// ChunksFromBody packs the collation body into 32 byte chunks, zero-padding
+// the last chunk.
+func ChunksFromBody(body []byte) Chunks {
+	chunks := make(Chunks, (len(body)+chunkSize-1)/chunkSize)
+	for i := range chunks {
+		copy(chunks[i][:], body[i*chunkSize:])
+	}
+	return chunks
+}
 
 // Len returns the number of chunks in this list.
 func (ch Chunks) Len() int { return len(ch) }
 
+// chunk returns the raw bytes of one chunk from the list.
+func (ch Chunks) chunk(i int) []byte {
+	return ch[i][:]
+}
+
 // GetRlp returns the RLP encoding of one chunk from the list.
 func (ch Chunks) GetRlp(i int) []byte {
 	bytes, err := rlp.EncodeToBytes(ch[i])
@@ -0,0 +1,140 @@
+package types
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// headerLengthSize is the size of the big endian header length prefix of a
+// binary encoded collation.
+const headerLengthSize = 4
+
+// Offsets of the fields of the fixed length header encoding returned by Bytes.
+const (
+	headerBytesPeriod    = 32
+	headerBytesChunkRoot = 64
+	headerBytesProposer  = 96
+	headerBytesSignature = 116
+	headerBytesPadding   = 181
+)
+
+// Bytes returns the fixed length encoding of the header, for hashing schemes
+// that need fields at fixed offsets: the 32 byte big-endian shardID and
+// period, the chunk root, the first proposer's address and its 65 byte
+// signature, zero padded to 256 bytes. Missing fields are encoded as zeros.
+// Other proposers, the flags and the aggregate signature are not encoded.
+func (h *CollationHeader) Bytes() [256]byte {
+	var b [256]byte
+	if h.data.ShardID != nil {
+		copy(b[:headerBytesPeriod], common.BigToHash(h.data.ShardID).Bytes())
+	}
+	if h.data.Period != nil {
+		copy(b[headerBytesPeriod:headerBytesChunkRoot], common.BigToHash(h.data.Period).Bytes())
+	}
+	if h.data.ChunkRoot != nil {
+		copy(b[headerBytesChunkRoot:headerBytesProposer], h.data.ChunkRoot.Bytes())
+	}
+	if len(h.data.ProposerAddresses) > 0 && h.data.ProposerAddresses[0] != nil {
+		copy(b[headerBytesProposer:headerBytesSignature], h.data.ProposerAddresses[0].Bytes())
+	}
+	copy(b[headerBytesSignature:headerBytesPadding], h.data.signature(0))
+	return b
+}
+
+// FromBytes decodes a header from the fixed length encoding returned by Bytes.
+// A zero chunk root or signature decodes as a missing field.
+func FromBytes(b [256]byte) (*CollationHeader, error) {
+	if !isZero(b[headerBytesPadding:]) {
+		return nil, errors.New("header encoding has non-zero padding")
+	}
+	var chunkRoot *common.Hash
+	if root := common.BytesToHash(b[headerBytesChunkRoot:headerBytesProposer]); root != (common.Hash{}) {
+		chunkRoot = &root
+	}
+	proposer := common.BytesToAddress(b[headerBytesProposer:headerBytesSignature])
+	var sig []byte
+	if s := b[headerBytesSignature:headerBytesPadding]; !isZero(s) {
+		sig = common.CopyBytes(s)
+	}
+	return NewCollationHeader(
+		new(big.Int).SetBytes(b[:headerBytesPeriod]),
+		chunkRoot,
+		new(big.Int).SetBytes(b[headerBytesPeriod:headerBytesChunkRoot]),
+		&proposer,
+		sig,
+		false,
+	)
+}
+
+// isZero reports whether every byte of b is zero.
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalBinary encodes the collation as the length of its RLP encoded header
+// in 4 big endian bytes, followed by the RLP encoded header and the raw body.
+// It implements encoding.BinaryMarshaler.
+func (c *Collation) MarshalBinary() ([]byte, error) {
+	if c.header == nil {
+		return nil, errors.New("collation has no header")
+	}
+	header, err := c.header.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("could not encode header: %v", err)
+	}
+	if len(header) > math.MaxUint32 {
+		return nil, fmt.Errorf("encoded header of %d bytes does not fit its length prefix", len(header))
+	}
+
+	data := make([]byte, headerLengthSize, headerLengthSize+len(header)+len(c.body))
+	binary.BigEndian.PutUint32(data, uint32(len(header)))
+	data = append(data, header...)
+	return append(data, c.body...), nil
+}
+
+// UnmarshalBinary decodes a collation encoded by MarshalBinary and deserializes
+// the transactions of its body. A body is checked against the header's chunk
+// root when both are present, so that truncated bodies are detected. It
+// implements encoding.BinaryUnmarshaler.
+func (c *Collation) UnmarshalBinary(data []byte) error {
+	if len(data) < headerLengthSize {
+		return fmt.Errorf("binary collation of %d bytes is missing its header length", len(data))
+	}
+	headerLength := uint64(binary.BigEndian.Uint32(data))
+	if headerLength > uint64(len(data)-headerLengthSize) {
+		return fmt.Errorf("binary collation has %d bytes left for a header of %d bytes", len(data)-headerLengthSize, headerLength)
+	}
+	header := &CollationHeader{}
+	if err := header.Decode(data[headerLengthSize : headerLengthSize+headerLength]); err != nil {
+		return fmt.Errorf("could not decode header: %v", err)
+	}
+	// data may be reused by the caller once UnmarshalBinary returns.
+	body := append([]byte(nil), data[headerLengthSize+headerLength:]...)
+
+	decoded := NewCollation(header, body, nil, WithConfig(c.config), WithEventLogger(c.events))
+	if header.ChunkRoot() != nil && len(body) > 0 {
+		if err := decoded.VerifyBodyIntegrity(); err != nil {
+			return err
+		}
+	}
+	if err := decoded.Deserialize(); err != nil {
+		return fmt.Errorf("cannot deserialize body: %v", err)
+	}
+
+	c.header = decoded.header
+	c.body = decoded.body
+	c.transactions = decoded.transactions
+	c.bodyHash = decoded.bodyHash
+	c.txIndex = nil
+	return nil
+}
@@ -0,0 +1,161 @@
+package types
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Collation)(nil)
+	_ encoding.BinaryUnmarshaler = (*Collation)(nil)
+)
+
+func TestCollation_BinaryRoundTrip(t *testing.T) {
+	rawData := NewCollation(newTestCollationHeader(t, big.NewInt(2), nil, big.NewInt(3)), []byte("raw shard data"), nil)
+	rawData.Header().WithBodyContentType(ContentTypeRawData)
+	rawData.CalculateChunkRoot()
+
+	tests := []struct {
+		name      string
+		collation *Collation
+	}{
+		{name: "transactions", collation: makeStoredCollation(t, 1, 10)},
+		{name: "raw data", collation: rawData},
+		{name: "header only", collation: NewCollation(newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1)), nil, nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.collation.MarshalBinary()
+			if err != nil {
+				t.Fatalf("could not marshal collation: %v", err)
+			}
+			decoded := &Collation{}
+			if err := decoded.UnmarshalBinary(data); err != nil {
+				t.Fatalf("could not unmarshal collation: %v", err)
+			}
+			if !decoded.Header().Equal(tt.collation.Header()) {
+				t.Errorf("decoded header does not match. want=%+v. got=%+v", tt.collation.Header().data, decoded.Header().data)
+			}
+			if string(decoded.Body()) != string(tt.collation.Body()) {
+				t.Errorf("decoded body does not match. want=%#x. got=%#x", tt.collation.Body(), decoded.Body())
+			}
+			if len(decoded.Transactions()) != len(tt.collation.Transactions()) {
+				t.Fatalf("decoded transaction count incorrect. want=%d. got=%d", len(tt.collation.Transactions()), len(decoded.Transactions()))
+			}
+			for i, tx := range decoded.Transactions() {
+				if tx.Hash() != tt.collation.Transactions()[i].Hash() {
+					t.Errorf("decoded transaction %d does not match", i)
+				}
+			}
+
+			// the decoded collation must not share memory with the input.
+			for i := range data {
+				data[i] = 0
+			}
+			if string(decoded.Body()) != string(tt.collation.Body()) {
+				t.Errorf("decoded body should not alias the input")
+			}
+		})
+	}
+}
+
+func TestCollation_UnmarshalBinaryTruncated(t *testing.T) {
+	c := makeStoredCollation(t, 1, 10)
+	data, err := c.MarshalBinary()
+	if err != nil {
+		t.Fatalf("could not marshal collation: %v", err)
+	}
+	encodedHeader, err := c.Header().Encode()
+	if err != nil {
+		t.Fatalf("could not encode header: %v", err)
+	}
+
+	// the body is cut by more than a chunk, since trailing zero bytes of the
+	// last chunk do not change the chunk root.
+	for _, size := range []int{0, 2, headerLengthSize, headerLengthSize + len(encodedHeader)/2, len(data) - chunkSize - 1} {
+		decoded := &Collation{}
+		err := decoded.UnmarshalBinary(data[:size])
+		if err == nil {
+			t.Errorf("unmarshaling a collation truncated to %d of %d bytes should fail", size, len(data))
+		}
+		if size > headerLengthSize+len(encodedHeader) && !errors.Is(err, ErrChunkRootMismatch) {
+			t.Errorf("truncated body should fail its chunk root check. want=%v. got=%v", ErrChunkRootMismatch, err)
+		}
+		if decoded.Header() != nil {
+			t.Errorf("failed unmarshaling should leave the collation untouched")
+		}
+	}
+}
+
+func TestCollationHeader_BytesRoundTrip(t *testing.T) {
+	key := generateKeys(t, 1)[0]
+	proposer := crypto.PubkeyToAddress(key.PublicKey)
+	chunkRoot := common.BytesToHash([]byte("chunk root"))
+	header, err := NewCollationHeader(big.NewInt(7), &chunkRoot, big.NewInt(300), &proposer, nil, false)
+	if err != nil {
+		t.Fatalf("could not create header: %v", err)
+	}
+	if err := header.Sign(key); err != nil {
+		t.Fatalf("could not sign header: %v", err)
+	}
+
+	b := header.Bytes()
+	if got := new(big.Int).SetBytes(b[:32]); got.Cmp(big.NewInt(7)) != 0 {
+		t.Errorf("shardID at wrong offset. want=%d. got=%v", 7, got)
+	}
+	if got := new(big.Int).SetBytes(b[32:64]); got.Cmp(big.NewInt(300)) != 0 {
+		t.Errorf("period at wrong offset. want=%d. got=%v", 300, got)
+	}
+	if !bytes.Equal(b[64:96], chunkRoot.Bytes()) || !bytes.Equal(b[96:116], proposer.Bytes()) {
+		t.Errorf("chunk root or proposer at wrong offset. got=%x", b[64:116])
+	}
+	if !bytes.Equal(b[116:181], header.ProposerSignatures()[0]) {
+		t.Errorf("signature at wrong offset. want=%x. got=%x", header.ProposerSignatures()[0], b[116:181])
+	}
+
+	decoded, err := FromBytes(b)
+	if err != nil {
+		t.Fatalf("could not decode header: %v", err)
+	}
+	if decoded.SignedHash() != header.SignedHash() {
+		t.Errorf("decoded header does not match. want=%v. got=%v", header.SignedHash().Hex(), decoded.SignedHash().Hex())
+	}
+	if err := decoded.VerifyProposerSignature(&key.PublicKey); err != nil {
+		t.Errorf("decoded header signature should verify: %v", err)
+	}
+}
+
+func TestCollationHeader_BytesMissingFields(t *testing.T) {
+	header, err := NewCollationHeader(big.NewInt(1), nil, big.NewInt(2), &testProposerAddress, nil, false)
+	if err != nil {
+		t.Fatalf("could not create header: %v", err)
+	}
+	b := header.Bytes()
+	if !isZero(b[64:96]) || !isZero(b[116:]) {
+		t.Errorf("missing fields should be encoded as zeros. got=%x", b[64:])
+	}
+	decoded, err := FromBytes(b)
+	if err != nil {
+		t.Fatalf("could not decode header: %v", err)
+	}
+	if decoded.ChunkRoot() != nil || decoded.ProposerSignatures()[0] != nil {
+		t.Errorf("zero fields should decode as missing fields")
+	}
+
+	padded := b
+	padded[255] = 1
+	if _, err := FromBytes(padded); err == nil {
+		t.Errorf("decoding an encoding with non-zero padding should fail")
+	}
+	// headers need a proposer.
+	copy(b[96:116], make([]byte, 20))
+	if _, err := FromBytes(b); err == nil {
+		t.Errorf("decoding a header without proposer should fail")
+	}
+}
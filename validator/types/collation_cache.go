@@ -0,0 +1,95 @@
+package types
+
+import (
+	"container/list"
+	"math/big"
+	"sync"
+)
+
+// collationCacheKey identifies a cached collation by its shardID and period.
+type collationCacheKey struct {
+	shardID string
+	period  string
+}
+
+// CollationCache is a concurrency safe, fixed capacity cache of collations
+// keyed by their shardID and period. Once full, the least recently used
+// collation is evicted to make room for a new one.
+type CollationCache struct {
+	capacity int
+	order    *list.List // most recently used collations first.
+	entries  map[collationCacheKey]*list.Element
+	lock     sync.Mutex
+}
+
+// NewCollationCache creates a cache holding at most capacity collations.
+func NewCollationCache(capacity int) *CollationCache {
+	return &CollationCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[collationCacheKey]*list.Element),
+	}
+}
+
+func newCollationCacheKey(shardID *big.Int, period *big.Int) collationCacheKey {
+	return collationCacheKey{shardID: shardID.String(), period: period.String()}
+}
+
+// Get returns the cached collation for the shardID and period, marking it as
+// recently used.
+func (c *CollationCache) Get(shardID *big.Int, period *big.Int) (*Collation, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, ok := c.entries[newCollationCacheKey(shardID, period)]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*Collation), true
+}
+
+// Put caches the collation under its header's shardID and period, replacing any
+// collation cached for them and evicting the least recently used collation if
+// the cache is full.
+func (c *CollationCache) Put(collation *Collation) {
+	if c.capacity <= 0 {
+		return
+	}
+	key := newCollationCacheKey(collation.Header().ShardID(), collation.Header().Period())
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = collation
+		c.order.MoveToFront(elem)
+		return
+	}
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		header := oldest.Value.(*Collation).Header()
+		delete(c.entries, newCollationCacheKey(header.ShardID(), header.Period()))
+	}
+	c.entries[key] = c.order.PushFront(collation)
+}
+
+// Evict removes the collation cached for the shardID and period, if any.
+func (c *CollationCache) Evict(shardID *big.Int, period *big.Int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := newCollationCacheKey(shardID, period)
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of cached collations.
+func (c *CollationCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.order.Len()
+}
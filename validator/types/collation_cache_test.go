@@ -0,0 +1,125 @@
+package types
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func newCacheCollation(t testing.TB, shardID, period int64) *Collation {
+	return NewCollation(newTestCollationHeader(t, big.NewInt(shardID), nil, big.NewInt(period)), nil, nil)
+}
+
+func TestCollationCache_GetPut(t *testing.T) {
+	cache := NewCollationCache(2)
+	a := newCacheCollation(t, 1, 1)
+	cache.Put(a)
+
+	got, ok := cache.Get(big.NewInt(1), big.NewInt(1))
+	if !ok || got != a {
+		t.Errorf("cached collation not returned. want=%v. got=%v", a, got)
+	}
+	if _, ok := cache.Get(big.NewInt(1), big.NewInt(2)); ok {
+		t.Errorf("collation should not be cached for another period")
+	}
+	if _, ok := cache.Get(big.NewInt(2), big.NewInt(1)); ok {
+		t.Errorf("collation should not be cached for another shard")
+	}
+
+	replacement := newCacheCollation(t, 1, 1)
+	cache.Put(replacement)
+	if got, _ := cache.Get(big.NewInt(1), big.NewInt(1)); got != replacement {
+		t.Errorf("collation should be replaced. want=%v. got=%v", replacement, got)
+	}
+	if cache.Len() != 1 {
+		t.Errorf("cache length incorrect. want=%d. got=%d", 1, cache.Len())
+	}
+}
+
+func TestCollationCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewCollationCache(2)
+	cache.Put(newCacheCollation(t, 1, 1))
+	cache.Put(newCacheCollation(t, 1, 2))
+
+	// Using period 1 makes period 2 the least recently used collation.
+	cache.Get(big.NewInt(1), big.NewInt(1))
+	cache.Put(newCacheCollation(t, 1, 3))
+
+	if _, ok := cache.Get(big.NewInt(1), big.NewInt(2)); ok {
+		t.Errorf("least recently used collation should be evicted")
+	}
+	for _, period := range []int64{1, 3} {
+		if _, ok := cache.Get(big.NewInt(1), big.NewInt(period)); !ok {
+			t.Errorf("collation for period %d should be cached", period)
+		}
+	}
+	if cache.Len() != 2 {
+		t.Errorf("cache length incorrect. want=%d. got=%d", 2, cache.Len())
+	}
+}
+
+func TestCollationCache_Evict(t *testing.T) {
+	cache := NewCollationCache(2)
+	cache.Put(newCacheCollation(t, 1, 1))
+	cache.Evict(big.NewInt(1), big.NewInt(1))
+	cache.Evict(big.NewInt(1), big.NewInt(2))
+
+	if _, ok := cache.Get(big.NewInt(1), big.NewInt(1)); ok {
+		t.Errorf("evicted collation should not be cached")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("cache length incorrect. want=%d. got=%d", 0, cache.Len())
+	}
+}
+
+func TestCollationCache_ZeroCapacity(t *testing.T) {
+	cache := NewCollationCache(0)
+	cache.Put(newCacheCollation(t, 1, 1))
+	if _, ok := cache.Get(big.NewInt(1), big.NewInt(1)); ok {
+		t.Errorf("zero capacity cache should not cache collations")
+	}
+}
+
+func TestCollationCache_Concurrent(t *testing.T) {
+	cache := NewCollationCache(50)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(shardID int64) {
+			defer wg.Done()
+			for period := int64(0); period < 100; period++ {
+				cache.Put(newCacheCollation(t, shardID, period))
+				cache.Get(big.NewInt(shardID), big.NewInt(period))
+				cache.Evict(big.NewInt(shardID), big.NewInt(period-1))
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+	if cache.Len() > 50 {
+		t.Errorf("cache exceeded its capacity. want<=%d. got=%d", 50, cache.Len())
+	}
+}
+
+// BenchmarkCollationCache_Get looks up collations in a full cache of 10k
+// entries, where 9 out of 10 lookups hit a cached collation.
+func BenchmarkCollationCache_Get(b *testing.B) {
+	const entries = 10000
+	cache := NewCollationCache(entries)
+	shardID := big.NewInt(1)
+	periods := make([]*big.Int, 2*entries)
+	for i := range periods {
+		periods[i] = big.NewInt(int64(i))
+		if i < entries {
+			cache.Put(newCacheCollation(b, 1, int64(i)))
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		period := periods[(i*7919)%entries] // a cached period.
+		if i%10 == 0 {
+			period = periods[entries+i%entries] // a period that was never cached.
+		}
+		cache.Get(shardID, period)
+	}
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// CollationChain holds the collations of a shard sorted by strictly increasing
+// period, so that the collation of a period is found by binary search.
+type CollationChain struct {
+	collations []*Collation
+	lock       sync.RWMutex
+}
+
+// NewCollationChain creates an empty CollationChain.
+func NewCollationChain() *CollationChain {
+	return &CollationChain{}
+}
+
+// Append adds the collation as the new head of the chain. Its period must be
+// strictly greater than the current head's, and it must belong to the same
+// shard.
+func (ch *CollationChain) Append(c *Collation) error {
+	if c == nil || c.Header() == nil || c.Header().Period() == nil {
+		return errors.New("collation has no period")
+	}
+
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+
+	if len(ch.collations) > 0 {
+		head := ch.collations[len(ch.collations)-1].Header()
+		if !bigIntEqual(c.Header().ShardID(), head.ShardID()) {
+			return fmt.Errorf("collation of shard %v cannot follow shard %v: %w", c.Header().ShardID(), head.ShardID(), ErrShardMismatch)
+		}
+		if c.Header().Period().Cmp(head.Period()) <= 0 {
+			return fmt.Errorf("collation period %v must exceed head period %v: %w", c.Header().Period(), head.Period(), ErrInvalidPeriod)
+		}
+	}
+	ch.collations = append(ch.collations, c)
+	return nil
+}
+
+// Find returns the collation of the period, if the chain holds one.
+func (ch *CollationChain) Find(period *big.Int) (*Collation, bool) {
+	if period == nil {
+		return nil, false
+	}
+
+	ch.lock.RLock()
+	defer ch.lock.RUnlock()
+
+	i := sort.Search(len(ch.collations), func(i int) bool {
+		return ch.collations[i].Header().Period().Cmp(period) >= 0
+	})
+	if i == len(ch.collations) || ch.collations[i].Header().Period().Cmp(period) != 0 {
+		return nil, false
+	}
+	return ch.collations[i], true
+}
+
+// Len returns the number of collations in the chain.
+func (ch *CollationChain) Len() int {
+	ch.lock.RLock()
+	defer ch.lock.RUnlock()
+	return len(ch.collations)
+}
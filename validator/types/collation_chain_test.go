@@ -0,0 +1,88 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// makeCollationChain creates a chain of shard 1 holding a collation for every
+// even period below 2n.
+func makeCollationChain(t testing.TB, n int) *CollationChain {
+	chain := NewCollationChain()
+	for i := 0; i < n; i++ {
+		header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(int64(2*i)))
+		if err := chain.Append(NewCollation(header, nil, nil)); err != nil {
+			t.Fatalf("could not append collation: %v", err)
+		}
+	}
+	return chain
+}
+
+func TestCollationChain_Find(t *testing.T) {
+	chain := makeCollationChain(t, 10)
+	for period := int64(0); period < 22; period++ {
+		c, ok := chain.Find(big.NewInt(period))
+		if want := period%2 == 0 && period < 20; ok != want {
+			t.Errorf("collation of period %d found=%v, want %v", period, ok, want)
+			continue
+		}
+		if ok && c.Header().Period().Int64() != period {
+			t.Errorf("found collation of the wrong period. want=%d. got=%v", period, c.Header().Period())
+		}
+	}
+	if _, ok := chain.Find(nil); ok {
+		t.Errorf("no collation should be found for a nil period")
+	}
+	if _, ok := NewCollationChain().Find(big.NewInt(0)); ok {
+		t.Errorf("no collation should be found in an empty chain")
+	}
+}
+
+func TestCollationChain_Append(t *testing.T) {
+	chain := makeCollationChain(t, 3)
+
+	tests := []struct {
+		name    string
+		shardID int64
+		period  int64
+		want    error
+	}{
+		{name: "same period as head", shardID: 1, period: 4, want: ErrInvalidPeriod},
+		{name: "period before head", shardID: 1, period: 1, want: ErrInvalidPeriod},
+		{name: "other shard", shardID: 2, period: 5, want: ErrShardMismatch},
+	}
+	for _, tt := range tests {
+		header := newTestCollationHeader(t, big.NewInt(tt.shardID), nil, big.NewInt(tt.period))
+		if err := chain.Append(NewCollation(header, nil, nil)); !errors.Is(err, tt.want) {
+			t.Errorf("%s: unexpected error. want=%v. got=%v", tt.name, tt.want, err)
+		}
+	}
+	if err := chain.Append(nil); err == nil {
+		t.Errorf("appending a nil collation should fail")
+	}
+	if chain.Len() != 3 {
+		t.Errorf("rejected collations should not be appended. want=%d. got=%d", 3, chain.Len())
+	}
+
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(7))
+	if err := chain.Append(NewCollation(header, nil, nil)); err != nil {
+		t.Fatalf("could not append collation skipping periods: %v", err)
+	}
+	if _, ok := chain.Find(big.NewInt(7)); !ok {
+		t.Errorf("appended collation should be found")
+	}
+}
+
+func BenchmarkCollationChain_Find(b *testing.B) {
+	for _, n := range []int{1000, 10000} {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			chain := makeCollationChain(b, n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				chain.Find(big.NewInt(int64(2 * (i % n))))
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// CollationDiff describes how to rebuild a re-proposed collation from a base
+// collation, so that only the changed transactions need to be transmitted.
+// The transactions of the base collation are kept in order, minus the removed
+// ones, and the added transactions are appended after them.
+type CollationDiff struct {
+	BaseHash        common.Hash              // the signed header hash of the base collation.
+	AddedTxs        []*gethTypes.Transaction // the transactions appended to the kept ones.
+	RemovedTxHashes []common.Hash            // the hashes of the base transactions to drop.
+}
+
+// Diff computes the diff turning base into updated. Base transactions are kept
+// for as long as updated lists them in the same order; everything after is
+// added, so that applying the diff reproduces updated's transaction order.
+func Diff(base, updated *Collation) *CollationDiff {
+	baseIndex := make(map[common.Hash]int, len(base.transactions))
+	for i, tx := range base.transactions {
+		baseIndex[tx.Hash()] = i
+	}
+
+	kept := make(map[common.Hash]bool)
+	last := -1
+	split := len(updated.transactions)
+	for i, tx := range updated.transactions {
+		idx, ok := baseIndex[tx.Hash()]
+		if !ok || idx <= last {
+			split = i
+			break
+		}
+		kept[tx.Hash()] = true
+		last = idx
+	}
+
+	diff := &CollationDiff{BaseHash: base.header.SignedHash()}
+	for _, tx := range base.transactions {
+		if !kept[tx.Hash()] {
+			diff.RemovedTxHashes = append(diff.RemovedTxHashes, tx.Hash())
+		}
+	}
+	diff.AddedTxs = append(diff.AddedTxs, updated.transactions[split:]...)
+	return diff
+}
+
+// Apply builds the collation described by the diff from its base collation.
+// The new collation keeps the base header's shardID, period and proposer, its
+// body is serialized and its chunk root calculated, but its header is unsigned.
+func (d *CollationDiff) Apply(base *Collation) (*Collation, error) {
+	if hash := base.header.SignedHash(); hash != d.BaseHash {
+		return nil, fmt.Errorf("diff applies to collation %s, not %s", d.BaseHash.Hex(), hash.Hex())
+	}
+
+	removed := make(map[common.Hash]bool, len(d.RemovedTxHashes))
+	for _, hash := range d.RemovedTxHashes {
+		removed[hash] = true
+	}
+
+	txs := make([]*gethTypes.Transaction, 0, len(base.transactions)+len(d.AddedTxs))
+	included := make(map[common.Hash]bool)
+	for _, tx := range base.transactions {
+		if removed[tx.Hash()] {
+			delete(removed, tx.Hash())
+			continue
+		}
+		included[tx.Hash()] = true
+		txs = append(txs, tx)
+	}
+	for hash := range removed {
+		return nil, fmt.Errorf("removed transaction %s is not in the base collation", hash.Hex())
+	}
+	for _, tx := range d.AddedTxs {
+		if included[tx.Hash()] {
+			return nil, fmt.Errorf("added transaction %s is already in the collation", tx.Hash().Hex())
+		}
+		included[tx.Hash()] = true
+		txs = append(txs, tx)
+	}
+
+	data := base.header.data
+	data.ProposerSignatures = nil
+	data.AggregateProposerSignature = nil
+	collation := NewCollation(&CollationHeader{data: data}, nil, txs, WithConfig(base.config))
+	if err := collation.Reserialize(); err != nil {
+		return nil, fmt.Errorf("could not serialize collation body: %w", err)
+	}
+	return collation, nil
+}
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// makeUpdatedCollation builds the unsigned re-proposal of base with the given transactions.
+func makeUpdatedCollation(t *testing.T, base *Collation, txs []*gethTypes.Transaction) *Collation {
+	data := base.header.data
+	data.ProposerSignatures = nil
+	updated := NewCollation(&CollationHeader{data: data}, nil, txs)
+	if err := updated.Reserialize(); err != nil {
+		t.Fatalf("could not serialize transactions: %v", err)
+	}
+	return updated
+}
+
+func TestCollationDiff_ApplyDiff(t *testing.T) {
+	base := makeStoredCollation(t, 1, 10)
+	txs := base.Transactions()
+	extra := makeRandomTransactions(2)
+
+	tests := []struct {
+		name        string
+		txs         []*gethTypes.Transaction
+		wantAdded   int
+		wantRemoved int
+	}{
+		{name: "unchanged", txs: txs, wantAdded: 0, wantRemoved: 0},
+		{name: "appended", txs: append(append([]*gethTypes.Transaction{}, txs...), extra...), wantAdded: 2, wantRemoved: 0},
+		{name: "removed", txs: []*gethTypes.Transaction{txs[0], txs[2]}, wantAdded: 0, wantRemoved: 1},
+		{name: "replaced", txs: []*gethTypes.Transaction{txs[0], txs[1], extra[0]}, wantAdded: 1, wantRemoved: 1},
+		{name: "reordered", txs: []*gethTypes.Transaction{txs[1], txs[0], txs[2]}, wantAdded: 2, wantRemoved: 2},
+		{name: "emptied", txs: []*gethTypes.Transaction{}, wantAdded: 0, wantRemoved: 3},
+	}
+	for _, tt := range tests {
+		updated := makeUpdatedCollation(t, base, tt.txs)
+		diff := Diff(base, updated)
+		if len(diff.AddedTxs) != tt.wantAdded || len(diff.RemovedTxHashes) != tt.wantRemoved {
+			t.Errorf("%s: diff size incorrect. want=+%d/-%d. got=+%d/-%d", tt.name, tt.wantAdded, tt.wantRemoved, len(diff.AddedTxs), len(diff.RemovedTxHashes))
+		}
+
+		applied, err := diff.Apply(base)
+		if err != nil {
+			t.Fatalf("%s: could not apply diff: %v", tt.name, err)
+		}
+		if !applied.Header().Equal(updated.Header()) {
+			t.Errorf("%s: applied header does not match. want=%+v. got=%+v", tt.name, updated.Header().data, applied.Header().data)
+		}
+		if !bytes.Equal(applied.Body(), updated.Body()) {
+			t.Errorf("%s: applied body does not match", tt.name)
+		}
+		if len(applied.Transactions()) != len(updated.Transactions()) {
+			t.Fatalf("%s: applied transaction count incorrect. want=%d. got=%d", tt.name, len(updated.Transactions()), len(applied.Transactions()))
+		}
+		for i, tx := range updated.Transactions() {
+			if applied.Transactions()[i].Hash() != tx.Hash() {
+				t.Errorf("%s: applied transaction %d does not match", tt.name, i)
+			}
+		}
+	}
+}
+
+func TestCollationDiff_ApplyInvalid(t *testing.T) {
+	base := makeStoredCollation(t, 1, 10)
+	other := makeStoredCollation(t, 1, 11)
+	extra := makeRandomTransactions(1)
+
+	diff := Diff(base, makeUpdatedCollation(t, base, extra))
+	if _, err := diff.Apply(other); err == nil {
+		t.Errorf("applying a diff to another base collation should fail")
+	}
+
+	missing := &CollationDiff{BaseHash: base.Header().SignedHash(), RemovedTxHashes: append(diff.RemovedTxHashes, extra[0].Hash())}
+	if _, err := missing.Apply(base); err == nil {
+		t.Errorf("removing a transaction missing from the base collation should fail")
+	}
+
+	duplicate := &CollationDiff{BaseHash: base.Header().SignedHash(), AddedTxs: base.Transactions()[:1]}
+	if _, err := duplicate.Apply(base); err == nil {
+		t.Errorf("adding a transaction already in the collation should fail")
+	}
+
+	base.config = ShardConfig{CollationSizeLimit: int64(len(base.Body()))}
+	tooLarge := &CollationDiff{BaseHash: base.Header().SignedHash(), AddedTxs: extra}
+	if _, err := tooLarge.Apply(base); err == nil {
+		t.Errorf("applying a diff over the collation size limit should fail")
+	}
+}
@@ -0,0 +1,86 @@
+package types
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Collation lifecycle event types logged to an EventLogger.
+const (
+	// EventSerialized is logged when the collation's transactions were serialized.
+	EventSerialized = "serialized"
+	// EventSerializeFailed is logged when the collation's transactions could not be serialized.
+	EventSerializeFailed = "serialize_failed"
+	// EventChunkRootCalculated is logged when the collation's chunk root was calculated.
+	EventChunkRootCalculated = "chunk_root_calculated"
+	// EventBodyVerified is logged when the collation body matched its chunk root.
+	EventBodyVerified = "body_verified"
+	// EventBodyVerificationFailed is logged when the collation body did not match its chunk root.
+	EventBodyVerificationFailed = "body_verification_failed"
+)
+
+// CollationEvent is an entry of the audit trail of a collation's lifecycle.
+// CollationHash is the unsigned hash of the header at the time of the event.
+type CollationEvent struct {
+	EventType     string
+	ShardID       *big.Int
+	Period        *big.Int
+	CollationHash common.Hash
+	Timestamp     time.Time
+	Details       map[string]interface{}
+}
+
+// EventLogger collects collation events in the order they are logged. It is
+// attached to collations with WithEventLogger.
+type EventLogger struct {
+	events []CollationEvent
+	lock   sync.Mutex
+}
+
+// NewEventLogger creates an EventLogger without events.
+func NewEventLogger() *EventLogger {
+	return &EventLogger{}
+}
+
+// Log appends the event to the trail, timestamping it if it has no timestamp.
+func (l *EventLogger) Log(e CollationEvent) {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.events = append(l.events, e)
+}
+
+// Flush returns the events logged since the last Flush and clears them.
+func (l *EventLogger) Flush() []CollationEvent {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	events := l.events
+	l.events = nil
+	return events
+}
+
+// WithEventLogger logs the lifecycle events of a collation to the logger.
+func WithEventLogger(l *EventLogger) Option {
+	return func(c *Collation) {
+		c.events = l
+	}
+}
+
+// logEvent logs an event of the collation if it has an event logger.
+func (c *Collation) logEvent(eventType string, details map[string]interface{}) {
+	if c.events == nil {
+		return
+	}
+	c.events.Log(CollationEvent{
+		EventType:     eventType,
+		ShardID:       c.header.ShardID(),
+		Period:        c.header.Period(),
+		CollationHash: c.header.UnsignedHash(),
+		Details:       details,
+	})
+}
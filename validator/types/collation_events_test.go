@@ -0,0 +1,71 @@
+package types
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func eventTypes(events []CollationEvent) []string {
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = e.EventType
+	}
+	return names
+}
+
+func TestEventLogger_CollationLifecycle(t *testing.T) {
+	logger := NewEventLogger()
+	header := newTestCollationHeader(t, big.NewInt(3), nil, big.NewInt(7))
+	c := NewCollation(header, nil, makeRandomTransactions(2), WithEventLogger(logger))
+
+	if err := c.Reserialize(); err != nil {
+		t.Fatalf("could not serialize collation: %v", err)
+	}
+	if err := c.VerifyBodyIntegrity(); err != nil {
+		t.Fatalf("could not verify body: %v", err)
+	}
+	c.body[0] ^= 0xff
+	if err := c.VerifyBodyIntegrity(); err == nil {
+		t.Fatalf("tampered body should fail verification")
+	}
+
+	events := logger.Flush()
+	want := []string{EventSerialized, EventChunkRootCalculated, EventBodyVerified, EventBodyVerificationFailed}
+	if got := eventTypes(events); !reflect.DeepEqual(got, want) {
+		t.Fatalf("events incorrect. want=%v. got=%v", want, got)
+	}
+	for i, e := range events {
+		if e.ShardID.Cmp(big.NewInt(3)) != 0 || e.Period.Cmp(big.NewInt(7)) != 0 {
+			t.Errorf("event %d has shard %v and period %v, want 3 and 7", i, e.ShardID, e.Period)
+		}
+		if i > 0 && e.Timestamp.Before(events[i-1].Timestamp) {
+			t.Errorf("event %d is timestamped before the previous one", i)
+		}
+	}
+	if events[0].Details["transactions"] != 2 || events[0].Details["size"] != len(c.Body()) {
+		t.Errorf("serialized event details incorrect. got=%v", events[0].Details)
+	}
+	if events[2].CollationHash != c.Header().UnsignedHash() {
+		t.Errorf("event collation hash incorrect. want=%x. got=%x", c.Header().UnsignedHash(), events[2].CollationHash)
+	}
+
+	if events := logger.Flush(); len(events) != 0 {
+		t.Errorf("flushing again should return no events. got=%d", len(events))
+	}
+
+	small := NewCollation(header, nil, makeRandomTransactions(5), WithConfig(ShardConfig{CollationSizeLimit: 64}), WithEventLogger(logger))
+	if _, err := small.Serialize(); err == nil {
+		t.Fatalf("serializing beyond the size limit should fail")
+	}
+	if got := eventTypes(logger.Flush()); !reflect.DeepEqual(got, []string{EventSerializeFailed}) {
+		t.Errorf("events incorrect. want=%v. got=%v", []string{EventSerializeFailed}, got)
+	}
+}
+
+func TestEventLogger_NoLogger(t *testing.T) {
+	c := makeStoredCollation(t, 1, 1)
+	if err := c.Reserialize(); err != nil {
+		t.Fatalf("could not serialize collation without event logger: %v", err)
+	}
+}
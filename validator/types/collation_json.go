@@ -0,0 +1,103 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// collationHeaderJSON is the canonical JSON representation of a collation header.
+// Integers and the chunk root are hex encoded, the proposer addresses use their
+// EIP-55 checksum encoding and the signatures are base64 encoded. The first
+// proposer is kept in proposerAddress and any co-proposers are listed separately.
+type collationHeaderJSON struct {
+	ShardID                    *hexutil.Big `json:"shardId"`
+	ChunkRoot                  *common.Hash `json:"chunkRoot"`
+	Period                     *hexutil.Big `json:"period"`
+	ProposerAddress            *string      `json:"proposerAddress"`
+	ProposerSignature          []byte       `json:"proposerSignature"`
+	CoProposerAddresses        []string     `json:"coProposerAddresses,omitempty"`
+	CoProposerSignatures       [][]byte     `json:"coProposerSignatures,omitempty"`
+	SkipEvmExecution           bool         `json:"skipEvmExecution"`
+	Compressed                 bool         `json:"compressed"`
+	AggregateProposerSignature []byte       `json:"aggregateProposerSignature,omitempty"`
+	BodyContentType            uint8        `json:"bodyContentType,omitempty"`
+}
+
+// MarshalJSON encodes the collation header's data fields as JSON.
+func (h *CollationHeader) MarshalJSON() ([]byte, error) {
+	enc := collationHeaderJSON{
+		ShardID:                    (*hexutil.Big)(h.data.ShardID),
+		ChunkRoot:                  h.data.ChunkRoot,
+		Period:                     (*hexutil.Big)(h.data.Period),
+		ProposerSignature:          h.data.signature(0),
+		SkipEvmExecution:           h.data.SkipEvmExecution,
+		Compressed:                 h.data.compressed(),
+		AggregateProposerSignature: h.data.AggregateProposerSignature,
+		BodyContentType:            h.data.BodyContentType,
+	}
+	for i, proposer := range h.data.ProposerAddresses {
+		var addr string
+		if proposer != nil {
+			addr = proposer.Hex()
+		}
+		if i == 0 {
+			enc.ProposerAddress = &addr
+			continue
+		}
+		enc.CoProposerAddresses = append(enc.CoProposerAddresses, addr)
+		enc.CoProposerSignatures = append(enc.CoProposerSignatures, h.data.signature(i))
+	}
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON populates the collation header's data fields from JSON produced by MarshalJSON.
+func (h *CollationHeader) UnmarshalJSON(input []byte) error {
+	var dec collationHeaderJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+
+	var data collationHeaderData
+	data.ShardID = (*big.Int)(dec.ShardID)
+	data.ChunkRoot = dec.ChunkRoot
+	data.Period = (*big.Int)(dec.Period)
+	if dec.ProposerAddress != nil {
+		if !common.IsHexAddress(*dec.ProposerAddress) {
+			return fmt.Errorf("invalid proposer address %q", *dec.ProposerAddress)
+		}
+		addr := common.HexToAddress(*dec.ProposerAddress)
+		data.ProposerAddresses = []*common.Address{&addr}
+		data.ProposerSignatures = [][]byte{dec.ProposerSignature}
+	} else if len(dec.CoProposerAddresses) > 0 {
+		return errors.New("co-proposers require a proposer address")
+	}
+	if len(dec.CoProposerSignatures) > len(dec.CoProposerAddresses) {
+		return fmt.Errorf("got %d co-proposer signatures for %d co-proposers", len(dec.CoProposerSignatures), len(dec.CoProposerAddresses))
+	}
+	for i, hex := range dec.CoProposerAddresses {
+		if !common.IsHexAddress(hex) {
+			return fmt.Errorf("invalid co-proposer address %q", hex)
+		}
+		addr := common.HexToAddress(hex)
+		data.ProposerAddresses = append(data.ProposerAddresses, &addr)
+		var sig []byte
+		if i < len(dec.CoProposerSignatures) {
+			sig = dec.CoProposerSignatures[i]
+		}
+		data.ProposerSignatures = append(data.ProposerSignatures, sig)
+	}
+	data.SkipEvmExecution = dec.SkipEvmExecution
+	data.AggregateProposerSignature = dec.AggregateProposerSignature
+	data.BodyContentType = dec.BodyContentType
+	if dec.Compressed != data.compressed() {
+		return fmt.Errorf("compressed flag %t does not match body content type %d", dec.Compressed, dec.BodyContentType)
+	}
+
+	h.data = data
+	return nil
+}
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCollationHeader_JSONRoundTrip(t *testing.T) {
+	chunkRoot := common.BytesToHash([]byte{0xde, 0xad, 0xbe, 0xef})
+	sig := make([]byte, 65)
+	for i := range sig {
+		sig[i] = byte(i)
+	}
+	header, err := NewCollationHeader(big.NewInt(42), &chunkRoot, big.NewInt(1000000), &testProposerAddress, sig, true)
+	if err != nil {
+		t.Fatalf("could not create collation header: %v", err)
+	}
+
+	encoded, err := json.Marshal(header)
+	if err != nil {
+		t.Fatalf("could not marshal header: %v", err)
+	}
+	if !strings.Contains(string(encoded), testProposerAddress.Hex()) {
+		t.Errorf("encoded header should contain the checksum proposer address %s: %s", testProposerAddress.Hex(), encoded)
+	}
+	if !strings.Contains(string(encoded), `"shardId":"0x2a"`) {
+		t.Errorf("encoded header should contain the hex encoded shardID: %s", encoded)
+	}
+
+	decoded := &CollationHeader{}
+	if err := json.Unmarshal(encoded, decoded); err != nil {
+		t.Fatalf("could not unmarshal header: %v", err)
+	}
+
+	if decoded.ShardID().Cmp(header.ShardID()) != 0 {
+		t.Errorf("shardID mismatch. want=%v. got=%v", header.ShardID(), decoded.ShardID())
+	}
+	if decoded.Period().Cmp(header.Period()) != 0 {
+		t.Errorf("period mismatch. want=%v. got=%v", header.Period(), decoded.Period())
+	}
+	if *decoded.ChunkRoot() != *header.ChunkRoot() {
+		t.Errorf("chunk root mismatch. want=%v. got=%v", header.ChunkRoot().Hex(), decoded.ChunkRoot().Hex())
+	}
+	if *decoded.ProposerAddresses()[0] != *header.ProposerAddresses()[0] {
+		t.Errorf("proposer address mismatch. want=%v. got=%v", header.ProposerAddresses()[0].Hex(), decoded.ProposerAddresses()[0].Hex())
+	}
+	if !bytes.Equal(decoded.Sig(), header.Sig()) {
+		t.Errorf("proposer signature mismatch. want=%x. got=%x", header.Sig(), decoded.Sig())
+	}
+	if decoded.SkipEvmExecution() != header.SkipEvmExecution() {
+		t.Errorf("skip EVM execution mismatch. want=%v. got=%v", header.SkipEvmExecution(), decoded.SkipEvmExecution())
+	}
+	if decoded.Hash() != header.Hash() {
+		t.Errorf("header hash mismatch after JSON round trip. want=%v. got=%v", header.Hash().Hex(), decoded.Hash().Hex())
+	}
+}
+
+func TestCollationHeader_JSONRoundTripMultiProposer(t *testing.T) {
+	keys := generateKeys(t, 3)
+	header := newMultiProposerHeader(t, keys)
+	header.WithBodyContentType(ContentTypeCompressed)
+	if err := header.Sign(keys[2]); err != nil {
+		t.Fatalf("could not sign header: %v", err)
+	}
+
+	encoded, err := json.Marshal(header)
+	if err != nil {
+		t.Fatalf("could not marshal header: %v", err)
+	}
+	decoded := &CollationHeader{}
+	if err := json.Unmarshal(encoded, decoded); err != nil {
+		t.Fatalf("could not unmarshal header: %v", err)
+	}
+	if !decoded.Equal(header) {
+		t.Errorf("round tripped header does not match. want=%+v. got=%+v", header.data, decoded.data)
+	}
+	if err := decoded.VerifyProposerSignature(&keys[2].PublicKey); err != nil {
+		t.Errorf("round tripped co-proposer signature failed verification: %v", err)
+	}
+}
+
+func TestCollationHeader_JSONNilFields(t *testing.T) {
+	header := &CollationHeader{}
+
+	encoded, err := json.Marshal(header)
+	if err != nil {
+		t.Fatalf("could not marshal empty header: %v", err)
+	}
+
+	decoded := &CollationHeader{}
+	if err := json.Unmarshal(encoded, decoded); err != nil {
+		t.Fatalf("could not unmarshal empty header: %v", err)
+	}
+	if decoded.ShardID() != nil || decoded.Period() != nil || decoded.ChunkRoot() != nil || len(decoded.ProposerAddresses()) != 0 {
+		t.Errorf("nil fields should remain nil after JSON round trip: %+v", decoded.data)
+	}
+}
+
+func TestCollationHeader_UnmarshalJSONInvalidAddress(t *testing.T) {
+	decoded := &CollationHeader{}
+	if err := json.Unmarshal([]byte(`{"proposerAddress":"0x1234"}`), decoded); err == nil {
+		t.Errorf("unmarshalling an invalid proposer address should fail")
+	}
+}
@@ -0,0 +1,215 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/prysmaticlabs/prysm/shared/shardutil"
+)
+
+// CollationPool stages pending transactions in the order they were received
+// until a proposer packs them into a collation. It also keeps the bodies of
+// collations announced by peers, which it fetches through a BodyFetcher and
+// checks against the chunk root of their verified header.
+type CollationPool struct {
+	config  ShardConfig
+	pending []*gethTypes.Transaction
+	known   map[common.Hash]bool
+	fetcher *BodyFetcher
+	headers map[common.Hash]*CollationHeader
+	bodies  map[common.Hash][]byte
+	lock    sync.RWMutex
+}
+
+// NewCollationPool creates an empty pool that packs collations according to cfg.
+func NewCollationPool(cfg ShardConfig) *CollationPool {
+	return &CollationPool{
+		config:  cfg,
+		known:   make(map[common.Hash]bool),
+		headers: make(map[common.Hash]*CollationHeader),
+		bodies:  make(map[common.Hash][]byte),
+	}
+}
+
+// SetBodyFetcher sets the fetcher used to request the bodies of announced
+// collations. Bodies delivered to the fetcher are stored in the pool.
+func (p *CollationPool) SetBodyFetcher(fetcher *BodyFetcher) {
+	p.lock.Lock()
+	p.fetcher = fetcher
+	p.lock.Unlock()
+	fetcher.OnBody(p.addBody)
+}
+
+// AddHeader makes the header of a collation known to the pool so that its body
+// can be fetched when announced. The header must carry a chunk root and be
+// signed by all of its proposers.
+func (p *CollationPool) AddHeader(h *CollationHeader) error {
+	if h.ChunkRoot() == nil {
+		return errors.New("collation header has no chunk root")
+	}
+	if err := h.VerifyAllProposerSignatures(); err != nil {
+		return err
+	}
+	hash := h.SignedHash()
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if _, ok := p.bodies[hash]; !ok {
+		p.headers[hash] = h
+	}
+	return nil
+}
+
+// HandleAnnouncement requests the body of an announced collation from the
+// announcing peer. Only collations whose header was added through AddHeader
+// are fetched, and bodies that are already requested or fetched are skipped.
+func (p *CollationPool) HandleAnnouncement(peer string, a *CollationAnnouncement) error {
+	p.lock.RLock()
+	fetcher := p.fetcher
+	_, known := p.headers[a.HeaderHash]
+	_, fetched := p.bodies[a.HeaderHash]
+	p.lock.RUnlock()
+
+	if fetcher == nil {
+		return errors.New("no body fetcher set to request announced collations")
+	}
+	if fetched || fetcher.requested(a.HeaderHash) {
+		return nil
+	}
+	if !known {
+		return fmt.Errorf("announced collation %s has no known header", a.HeaderHash.Hex())
+	}
+	if int64(a.BodySize) > p.config.collationSizeLimit() {
+		return fmt.Errorf("announced body size %d exceeds the collation size limit %d: %w", a.BodySize, p.config.collationSizeLimit(), ErrCollationTooLarge)
+	}
+	return fetcher.Request(peer, a.HeaderHash)
+}
+
+// Body returns the fetched body of an announced collation.
+func (p *CollationPool) Body(hash common.Hash) ([]byte, bool) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	body, ok := p.bodies[hash]
+	return body, ok
+}
+
+// addBody stores a fetched body, checking it against the size limit and the
+// chunk root of its header.
+func (p *CollationPool) addBody(hash common.Hash, body []byte) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	h, ok := p.headers[hash]
+	if !ok {
+		return fmt.Errorf("collation %s has no known header", hash.Hex())
+	}
+	if int64(len(body)) > p.config.collationSizeLimit() {
+		return fmt.Errorf("body size %d exceeds the collation size limit %d: %w", len(body), p.config.collationSizeLimit(), ErrCollationTooLarge)
+	}
+	if chunkRoot := chunkRootFromBody(body); chunkRoot != *h.ChunkRoot() {
+		return fmt.Errorf("body of collation %s has chunk root %s, header has %s: %w", hash.Hex(), chunkRoot.Hex(), h.ChunkRoot().Hex(), ErrChunkRootMismatch)
+	}
+	delete(p.headers, hash)
+	p.bodies[hash] = body
+	return nil
+}
+
+// Add stages a transaction in the pool. Transactions already in the pool are rejected.
+func (p *CollationPool) Add(tx *gethTypes.Transaction) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	hash := tx.Hash()
+	if p.known[hash] {
+		return fmt.Errorf("transaction %s already in pool", hash.Hex())
+	}
+	p.known[hash] = true
+	p.pending = append(p.pending, tx)
+	return nil
+}
+
+// Pending returns a copy of the transactions in the pool in the order they were added.
+func (p *CollationPool) Pending() []*gethTypes.Transaction {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	pending := make([]*gethTypes.Transaction, len(p.pending))
+	copy(pending, p.pending)
+	return pending
+}
+
+// Discard removes the transaction with the given hash from the pool, if present.
+func (p *CollationPool) Discard(hash common.Hash) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if !p.known[hash] {
+		return
+	}
+	delete(p.known, hash)
+	for i, tx := range p.pending {
+		if tx.Hash() == hash {
+			p.pending = append(p.pending[:i], p.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// Pack fills a collation with pending transactions by descending gas price, and
+// in the order they were added for equal gas prices, until the next transaction
+// would exceed the collation size or gas limit. Packed transactions are removed
+// from the pool while the others stay pending.
+// The returned collation has its chunk root calculated but its header is left
+// unsigned, so the proposer needs to sign it through AddSig before submission.
+// The pool is left untouched if the shardID, period or proposer is invalid.
+func (p *CollationPool) Pack(shardID *big.Int, period *big.Int, proposerAddr *common.Address) (*Collation, error) {
+	header, err := NewCollationHeader(shardID, nil, period, proposerAddr, nil, false, p.config)
+	if err != nil {
+		return nil, err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	csl := p.config.collationSizeLimit()
+	gasLimit := p.config.gasLimit()
+	var size int64
+	var gas uint64
+	var txs []*gethTypes.Transaction
+	packed := make(map[common.Hash]bool)
+	queue := NewTxPriorityQueue(p.pending)
+	for queue.Len() > 0 {
+		tx := queue.Pop()
+		blob, err := shardutil.NewRawBlob(tx, false)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert transaction %s to blob: %v", tx.Hash().Hex(), err)
+		}
+		serialized, err := shardutil.Serialize([]*shardutil.RawBlob{blob})
+		if err != nil {
+			return nil, fmt.Errorf("could not serialize transaction %s: %v", tx.Hash().Hex(), err)
+		}
+		if size+int64(len(serialized)) > csl || tx.Gas() > gasLimit-gas {
+			break
+		}
+		size += int64(len(serialized))
+		gas += tx.Gas()
+		txs = append(txs, tx)
+		packed[tx.Hash()] = true
+	}
+
+	collation := NewCollation(header, nil, txs, WithConfig(p.config))
+	collation.DeduplicateTransactions()
+	if err := collation.Reserialize(); err != nil {
+		return nil, fmt.Errorf("could not serialize collation body: %w", err)
+	}
+
+	var pending []*gethTypes.Transaction
+	for _, tx := range p.pending {
+		if packed[tx.Hash()] {
+			delete(p.known, tx.Hash())
+			continue
+		}
+		pending = append(pending, tx)
+	}
+	p.pending = pending
+	return collation, nil
+}
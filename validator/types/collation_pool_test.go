@@ -0,0 +1,338 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prysmaticlabs/prysm/shared/shardutil"
+)
+
+func TestCollationPool_AddPendingDiscard(t *testing.T) {
+	pool := NewCollationPool(ShardConfig{})
+	txs := makeRandomTransactions(3)
+
+	for _, tx := range txs {
+		if err := pool.Add(tx); err != nil {
+			t.Fatalf("could not add transaction to pool: %v", err)
+		}
+	}
+	if err := pool.Add(txs[0]); err == nil {
+		t.Errorf("adding a duplicate transaction should fail")
+	}
+
+	pending := pool.Pending()
+	if len(pending) != len(txs) {
+		t.Fatalf("pending count incorrect. want=%d. got=%d", len(txs), len(pending))
+	}
+	for i, tx := range pending {
+		if tx.Hash() != txs[i].Hash() {
+			t.Errorf("pending transaction %d out of order", i)
+		}
+	}
+
+	pool.Discard(txs[1].Hash())
+	pending = pool.Pending()
+	if len(pending) != 2 || pending[0].Hash() != txs[0].Hash() || pending[1].Hash() != txs[2].Hash() {
+		t.Errorf("discarding a transaction should only remove that transaction")
+	}
+
+	// discarded transactions can be added again.
+	if err := pool.Add(txs[1]); err != nil {
+		t.Errorf("could not re-add discarded transaction: %v", err)
+	}
+}
+
+func TestCollationPool_Pack(t *testing.T) {
+	txs := makeRandomTransactions(10)
+
+	// size the limit so that exactly 4 transactions fit.
+	blob, err := convertTxToRawBlob(txs[:1], false)
+	if err != nil {
+		t.Fatalf("could not convert transaction: %v", err)
+	}
+	serialized, err := shardutil.Serialize(blob)
+	if err != nil {
+		t.Fatalf("could not serialize transaction: %v", err)
+	}
+	limit := int64(len(serialized))*4 + 1
+
+	pool := NewCollationPool(ShardConfig{CollationSizeLimit: limit})
+	for _, tx := range txs {
+		if err := pool.Add(tx); err != nil {
+			t.Fatalf("could not add transaction to pool: %v", err)
+		}
+	}
+
+	collation, err := pool.Pack(big.NewInt(1), big.NewInt(5), &testProposerAddress)
+	if err != nil {
+		t.Fatalf("could not pack collation: %v", err)
+	}
+
+	if len(collation.Transactions()) != 4 {
+		t.Fatalf("packed transaction count incorrect. want=%d. got=%d", 4, len(collation.Transactions()))
+	}
+	if int64(len(collation.Body())) > limit {
+		t.Errorf("packed body exceeds size limit. limit=%d. got=%d", limit, len(collation.Body()))
+	}
+	if collation.Header().ChunkRoot() == nil {
+		t.Errorf("packed collation should have its chunk root calculated")
+	}
+	if collation.Header().ShardID().Cmp(big.NewInt(1)) != 0 || collation.Header().Period().Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("packed collation has wrong shardID or period")
+	}
+
+	// overflowing transactions stay in the pool.
+	pending := pool.Pending()
+	if len(pending) != 6 {
+		t.Fatalf("pending count after pack incorrect. want=%d. got=%d", 6, len(pending))
+	}
+	for i, tx := range pending {
+		if tx.Hash() != txs[i+4].Hash() {
+			t.Errorf("overflow transaction %d not kept in order", i)
+		}
+	}
+
+	// packed transactions are no longer known to the pool.
+	if err := pool.Add(txs[0]); err != nil {
+		t.Errorf("packed transaction should have been removed from the pool: %v", err)
+	}
+}
+
+func TestCollationPool_PackGasLimit(t *testing.T) {
+	pool := NewCollationPool(ShardConfig{GasLimit: 50000})
+	for i := uint64(1); i <= 3; i++ {
+		if err := pool.Add(makeTxWithGasLimit(20000 + i)); err != nil {
+			t.Fatalf("could not add transaction to pool: %v", err)
+		}
+	}
+
+	collation, err := pool.Pack(big.NewInt(1), big.NewInt(5), &testProposerAddress)
+	if err != nil {
+		t.Fatalf("could not pack collation: %v", err)
+	}
+	if len(collation.Transactions()) != 2 {
+		t.Errorf("packed transaction count incorrect. want=%d. got=%d", 2, len(collation.Transactions()))
+	}
+	if len(pool.Pending()) != 1 {
+		t.Errorf("transactions over the gas limit should stay pending. want=%d. got=%d", 1, len(pool.Pending()))
+	}
+}
+
+func TestCollationPool_PackHighestFees(t *testing.T) {
+	// room for 3 transactions of 21000 gas.
+	pool := NewCollationPool(ShardConfig{GasLimit: 3 * 21000})
+	prices := []int64{2, 8, 1, 5, 9}
+	var txs []*gethTypes.Transaction
+	for i, price := range prices {
+		tx := makeTxWithGasPrice(uint64(i), price)
+		txs = append(txs, tx)
+		if err := pool.Add(tx); err != nil {
+			t.Fatalf("could not add transaction to pool: %v", err)
+		}
+	}
+
+	collation, err := pool.Pack(big.NewInt(1), big.NewInt(5), &testProposerAddress)
+	if err != nil {
+		t.Fatalf("could not pack collation: %v", err)
+	}
+	want := []*gethTypes.Transaction{txs[4], txs[1], txs[3]}
+	if len(collation.Transactions()) != len(want) {
+		t.Fatalf("packed transaction count incorrect. want=%d. got=%d", len(want), len(collation.Transactions()))
+	}
+	for i, tx := range collation.Transactions() {
+		if tx != want[i] {
+			t.Errorf("packed transaction %d incorrect. want price=%v. got price=%v", i, want[i].GasPrice(), tx.GasPrice())
+		}
+	}
+
+	// the cheapest transactions stay pending in the order they were added.
+	pending := pool.Pending()
+	if len(pending) != 2 || pending[0] != txs[0] || pending[1] != txs[2] {
+		t.Errorf("cheapest transactions should stay pending in order. got=%v", pending)
+	}
+}
+
+func TestCollationPool_PackEmpty(t *testing.T) {
+	pool := NewCollationPool(ShardConfig{})
+	collation, err := pool.Pack(big.NewInt(1), big.NewInt(1), &testProposerAddress)
+	if err != nil {
+		t.Fatalf("could not pack empty collation: %v", err)
+	}
+	if len(collation.Transactions()) != 0 {
+		t.Errorf("empty pool should pack an empty collation")
+	}
+}
+
+func TestCollationPool_PackInvalidHeader(t *testing.T) {
+	pool := NewCollationPool(ShardConfig{ShardCount: 10})
+	tx := makeRandomTransactions(1)[0]
+	if err := pool.Add(tx); err != nil {
+		t.Fatalf("could not add transaction to pool: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		shardID  *big.Int
+		period   *big.Int
+		proposer *common.Address
+	}{
+		{name: "nil shardID", period: big.NewInt(1), proposer: &testProposerAddress},
+		{name: "out of range shardID", shardID: big.NewInt(10), period: big.NewInt(1), proposer: &testProposerAddress},
+		{name: "negative shardID", shardID: big.NewInt(-1), period: big.NewInt(1), proposer: &testProposerAddress},
+		{name: "nil period", shardID: big.NewInt(1), proposer: &testProposerAddress},
+		{name: "nil proposer", shardID: big.NewInt(1), period: big.NewInt(1)},
+	}
+	for _, tt := range tests {
+		if _, err := pool.Pack(tt.shardID, tt.period, tt.proposer); err == nil {
+			t.Errorf("packing a collation with %s should fail", tt.name)
+		}
+	}
+	if len(pool.Pending()) != 1 {
+		t.Errorf("failed packs should leave the pool untouched. want=%d pending. got=%d", 1, len(pool.Pending()))
+	}
+}
+
+// makeSignedPoolCollation creates a collation whose header carries its chunk
+// root and is signed by its proposer.
+func makeSignedPoolCollation(t *testing.T) *Collation {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	proposerAddress := crypto.PubkeyToAddress(key.PublicKey)
+	header, err := NewCollationHeader(big.NewInt(1), nil, big.NewInt(10), &proposerAddress, make([]byte, 65), false)
+	if err != nil {
+		t.Fatalf("could not create collation header: %v", err)
+	}
+	txs := makeRandomTransactions(3)
+	body, err := SerializeTxToBlob(txs)
+	if err != nil {
+		t.Fatalf("could not serialize transactions: %v", err)
+	}
+	c := NewCollation(header, body, txs)
+	c.CalculateChunkRoot()
+	if err := header.Sign(key); err != nil {
+		t.Fatalf("could not sign header: %v", err)
+	}
+	return c
+}
+
+func TestCollationPool_HandleAnnouncement(t *testing.T) {
+	pool := NewCollationPool(ShardConfig{})
+	c := makeSignedPoolCollation(t)
+	announcement := CollationAnnouncementFromHeader(c.Header(), uint64(len(c.Body())))
+	hash := c.Header().SignedHash()
+
+	if err := pool.HandleAnnouncement("peer1", announcement); err == nil {
+		t.Errorf("handling an announcement without a body fetcher should fail")
+	}
+
+	fetcher, sent := newTestBodyFetcher()
+	pool.SetBodyFetcher(fetcher)
+	if err := pool.AddHeader(c.Header()); err != nil {
+		t.Fatalf("could not add header: %v", err)
+	}
+	if err := pool.HandleAnnouncement("peer1", announcement); err != nil {
+		t.Fatalf("could not handle announcement: %v", err)
+	}
+	if err := pool.HandleAnnouncement("peer2", announcement); err != nil {
+		t.Fatalf("could not handle repeated announcement: %v", err)
+	}
+	if len(*sent) != 1 {
+		t.Errorf("body should be requested once. got %d requests", len(*sent))
+	}
+
+	if err := fetcher.Deliver("peer1", &BodyResponse{HeaderHash: hash, Body: c.Body()}); err != nil {
+		t.Fatalf("could not deliver body: %v", err)
+	}
+	body, ok := pool.Body(hash)
+	if !ok || !bytes.Equal(body, c.Body()) {
+		t.Errorf("pool should store the delivered body")
+	}
+	if err := pool.HandleAnnouncement("peer2", announcement); err != nil || len(*sent) != 1 {
+		t.Errorf("announcements of fetched bodies should be ignored. err=%v, requests=%d", err, len(*sent))
+	}
+}
+
+func TestCollationPool_HandleAnnouncementUnknownHeader(t *testing.T) {
+	pool := NewCollationPool(ShardConfig{})
+	fetcher, sent := newTestBodyFetcher()
+	pool.SetBodyFetcher(fetcher)
+	c := makeSignedPoolCollation(t)
+
+	if err := pool.HandleAnnouncement("peer1", CollationAnnouncementFromHeader(c.Header(), uint64(len(c.Body())))); err == nil {
+		t.Errorf("announcements of collations without a known header should fail")
+	}
+	if len(*sent) != 0 {
+		t.Errorf("bodies of unknown collations should not be requested. got %d requests", len(*sent))
+	}
+}
+
+func TestCollationPool_AddHeader(t *testing.T) {
+	pool := NewCollationPool(ShardConfig{})
+
+	unsigned := makeStoredCollation(t, 1, 10)
+	if err := pool.AddHeader(unsigned.Header()); !errors.Is(err, ErrInvalidProposerSignature) {
+		t.Errorf("headers without a valid proposer signature should be rejected. want=%v. got=%v", ErrInvalidProposerSignature, err)
+	}
+	if err := pool.AddHeader(newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(10))); err == nil {
+		t.Errorf("headers without a chunk root should be rejected")
+	}
+}
+
+func TestCollationPool_HandleAnnouncementWrongSize(t *testing.T) {
+	pool := NewCollationPool(ShardConfig{CollationSizeLimit: 1024})
+	fetcher, _ := newTestBodyFetcher()
+	pool.SetBodyFetcher(fetcher)
+	c := makeSignedPoolCollation(t)
+	if err := pool.AddHeader(c.Header()); err != nil {
+		t.Fatalf("could not add header: %v", err)
+	}
+
+	announcement := CollationAnnouncementFromHeader(c.Header(), 2048)
+	if err := pool.HandleAnnouncement("peer1", announcement); !errors.Is(err, ErrCollationTooLarge) {
+		t.Errorf("announcements of bodies over the collation size limit should fail. want=%v. got=%v", ErrCollationTooLarge, err)
+	}
+	if fetcher.Pending() != 0 {
+		t.Errorf("oversized bodies should not be requested. got %d requests", fetcher.Pending())
+	}
+}
+
+func TestCollationPool_HandleAnnouncementWrongChunkRoot(t *testing.T) {
+	pool := NewCollationPool(ShardConfig{})
+	fetcher, _ := newTestBodyFetcher()
+	pool.SetBodyFetcher(fetcher)
+	c := makeSignedPoolCollation(t)
+	if err := pool.AddHeader(c.Header()); err != nil {
+		t.Fatalf("could not add header: %v", err)
+	}
+	announcement := CollationAnnouncementFromHeader(c.Header(), uint64(len(c.Body())))
+	hash := c.Header().SignedHash()
+
+	if err := pool.HandleAnnouncement("peer1", announcement); err != nil {
+		t.Fatalf("could not handle announcement: %v", err)
+	}
+	garbage := make([]byte, len(c.Body()))
+	if err := fetcher.Deliver("peer1", &BodyResponse{HeaderHash: hash, Body: garbage}); !errors.Is(err, ErrChunkRootMismatch) {
+		t.Errorf("delivering a body that does not match the header's chunk root should fail. want=%v. got=%v", ErrChunkRootMismatch, err)
+	}
+	if _, ok := pool.Body(hash); ok {
+		t.Errorf("pool should not store a body with the wrong chunk root")
+	}
+
+	// The header stays known so the body can be fetched from another peer.
+	if err := pool.HandleAnnouncement("peer2", announcement); err != nil {
+		t.Fatalf("could not handle announcement: %v", err)
+	}
+	if err := fetcher.Deliver("peer2", &BodyResponse{HeaderHash: hash, Body: c.Body()}); err != nil {
+		t.Fatalf("could not deliver body: %v", err)
+	}
+	if _, ok := pool.Body(hash); !ok {
+		t.Errorf("pool should store the body matching the header's chunk root")
+	}
+}
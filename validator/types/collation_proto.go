@@ -0,0 +1,147 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	pb "github.com/prysmaticlabs/prysm/proto/sharding/p2p/v1"
+)
+
+// ToProto converts the collation into its protobuf representation for gRPC
+// APIs. Shard IDs and periods are encoded as uint64, so they are assumed to
+// fit in 64 bits; larger values are truncated.
+func (c *Collation) ToProto() *pb.Collation {
+	return &pb.Collation{
+		Header: c.header.ToProto(),
+		Body:   c.body,
+	}
+}
+
+// ToProto converts the header into its protobuf representation, with the same
+// 64 bit assumption on shard IDs and periods as Collation.ToProto.
+func (h *CollationHeader) ToProto() *pb.CollationHeader {
+	d := h.data
+	header := &pb.CollationHeader{
+		Signature:          d.signature(0),
+		SkipEvmExecution:   d.SkipEvmExecution,
+		Compressed:         d.compressed(),
+		AggregateSignature: d.AggregateProposerSignature,
+		BodyContentType:    uint32(d.BodyContentType),
+	}
+	if d.ShardID != nil {
+		header.ShardId = d.ShardID.Uint64()
+	}
+	if d.ChunkRoot != nil {
+		header.ChunkRoot = d.ChunkRoot.Bytes()
+	}
+	if d.Period != nil {
+		header.Period = d.Period.Uint64()
+	}
+	for i, proposer := range d.ProposerAddresses {
+		var addr string
+		if proposer != nil {
+			addr = proposer.Hex()
+		}
+		if i == 0 {
+			header.ProposerAddress = addr
+			continue
+		}
+		header.CoProposerAddresses = append(header.CoProposerAddresses, addr)
+		header.CoProposerSignatures = append(header.CoProposerSignatures, d.signature(i))
+	}
+	return header
+}
+
+// CollationFromProto converts a protobuf collation back into a Collation,
+// validating its header against the optional shard config, checking its body
+// against the chunk root and deserializing the transactions from its body.
+func CollationFromProto(p *pb.Collation, config ...ShardConfig) (*Collation, error) {
+	if p == nil || p.Header == nil {
+		return nil, errors.New("collation has no header")
+	}
+	header, err := CollationHeaderFromProto(p.Header, config...)
+	if err != nil {
+		return nil, err
+	}
+
+	collation := NewCollation(header, p.Body, nil)
+	// Collations sent without a body only carry their header.
+	if len(p.Body) > 0 {
+		if err := collation.VerifyBodyIntegrity(); err != nil {
+			return nil, err
+		}
+	}
+	if err := collation.Deserialize(); err != nil {
+		return nil, fmt.Errorf("cannot deserialize body: %v", err)
+	}
+	return collation, nil
+}
+
+// CollationHeaderFromProto converts a protobuf collation header back into a
+// CollationHeader and validates it. The shardID is checked against the shard
+// count of the optional shard config, which defaults to the params package
+// shard count.
+func CollationHeaderFromProto(p *pb.CollationHeader, config ...ShardConfig) (*CollationHeader, error) {
+	if p == nil {
+		return nil, errors.New("no collation header provided")
+	}
+	if !common.IsHexAddress(p.ProposerAddress) {
+		return nil, fmt.Errorf("invalid proposer address %q", p.ProposerAddress)
+	}
+	proposerAddress := common.HexToAddress(p.ProposerAddress)
+	proposers := []*common.Address{&proposerAddress}
+	signatures := [][]byte{p.Signature}
+	if len(p.CoProposerSignatures) > len(p.CoProposerAddresses) {
+		return nil, fmt.Errorf("got %d co-proposer signatures for %d co-proposers", len(p.CoProposerSignatures), len(p.CoProposerAddresses))
+	}
+	for i, hex := range p.CoProposerAddresses {
+		if !common.IsHexAddress(hex) {
+			return nil, fmt.Errorf("invalid co-proposer address %q", hex)
+		}
+		addr := common.HexToAddress(hex)
+		proposers = append(proposers, &addr)
+		var sig []byte
+		if i < len(p.CoProposerSignatures) {
+			sig = p.CoProposerSignatures[i]
+		}
+		signatures = append(signatures, sig)
+	}
+
+	var chunkRoot *common.Hash
+	if len(p.ChunkRoot) > 0 {
+		if len(p.ChunkRoot) != common.HashLength {
+			return nil, fmt.Errorf("chunk root has length %d, wanted %d", len(p.ChunkRoot), common.HashLength)
+		}
+		root := common.BytesToHash(p.ChunkRoot)
+		chunkRoot = &root
+	}
+
+	if p.BodyContentType > math.MaxUint8 {
+		return nil, fmt.Errorf("invalid body content type %d", p.BodyContentType)
+	}
+	if p.Compressed != (uint8(p.BodyContentType) == ContentTypeCompressed) {
+		return nil, fmt.Errorf("compressed flag %t does not match body content type %d", p.Compressed, p.BodyContentType)
+	}
+
+	header := &CollationHeader{data: collationHeaderData{
+		ShardID:                    new(big.Int).SetUint64(p.ShardId),
+		ChunkRoot:                  chunkRoot,
+		Period:                     new(big.Int).SetUint64(p.Period),
+		ProposerAddresses:          proposers,
+		ProposerSignatures:         signatures,
+		SkipEvmExecution:           p.SkipEvmExecution,
+		AggregateProposerSignature: p.AggregateSignature,
+		BodyContentType:            uint8(p.BodyContentType),
+	}}
+	var cfg ShardConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if err := header.validate(int64(cfg.shardCount())); err != nil {
+		return nil, fmt.Errorf("invalid collation header: %w", err)
+	}
+	return header, nil
+}
@@ -0,0 +1,153 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	pb "github.com/prysmaticlabs/prysm/proto/sharding/p2p/v1"
+)
+
+func TestCollation_ProtoRoundTrip(t *testing.T) {
+	c := makeStoredCollation(t, 3, 12)
+	c.Header().data.ProposerSignatures[0][0] = 1
+	c.Header().data.AggregateProposerSignature = bytes.Repeat([]byte{2}, blsSignatureLength)
+
+	decoded, err := CollationFromProto(c.ToProto())
+	if err != nil {
+		t.Fatalf("could not convert collation from proto: %v", err)
+	}
+	if !decoded.Header().Equal(c.Header()) {
+		t.Errorf("round tripped header does not match. want=%+v. got=%+v", c.Header().data, decoded.Header().data)
+	}
+	if !bytes.Equal(decoded.Body(), c.Body()) {
+		t.Errorf("round tripped body does not match. want=%x. got=%x", c.Body(), decoded.Body())
+	}
+	if len(decoded.Transactions()) != len(c.Transactions()) {
+		t.Fatalf("transaction count incorrect. want=%d. got=%d", len(c.Transactions()), len(decoded.Transactions()))
+	}
+	for i, tx := range c.Transactions() {
+		if decoded.Transactions()[i].Hash() != tx.Hash() {
+			t.Errorf("transaction %d does not match. want=%x. got=%x", i, tx.Hash(), decoded.Transactions()[i].Hash())
+		}
+	}
+}
+
+func TestCollation_ProtoRoundTripUnsigned(t *testing.T) {
+	header, err := NewCollationHeader(big.NewInt(1), nil, big.NewInt(2), &testProposerAddress, nil, true)
+	if err != nil {
+		t.Fatalf("could not create collation header: %v", err)
+	}
+	c := NewCollation(header, nil, nil)
+
+	p := c.ToProto()
+	if len(p.Header.ChunkRoot) != 0 || len(p.Header.Signature) != 0 {
+		t.Errorf("unset chunk root and signature should be empty. got=%x, %x", p.Header.ChunkRoot, p.Header.Signature)
+	}
+	decoded, err := CollationFromProto(p)
+	if err != nil {
+		t.Fatalf("could not convert collation from proto: %v", err)
+	}
+	if !decoded.Header().Equal(c.Header()) {
+		t.Errorf("round tripped header does not match. want=%+v. got=%+v", c.Header().data, decoded.Header().data)
+	}
+}
+
+func TestCollation_ProtoRoundTripMultiProposer(t *testing.T) {
+	keys := generateKeys(t, 3)
+	header := newMultiProposerHeader(t, keys)
+	if err := header.Sign(keys[1]); err != nil {
+		t.Fatalf("could not sign header: %v", err)
+	}
+	c := NewCollation(header, nil, nil)
+
+	p := c.ToProto()
+	if len(p.Header.CoProposerAddresses) != 2 {
+		t.Errorf("co-proposer count incorrect. want=%d. got=%d", 2, len(p.Header.CoProposerAddresses))
+	}
+	decoded, err := CollationFromProto(p)
+	if err != nil {
+		t.Fatalf("could not convert collation from proto: %v", err)
+	}
+	if !decoded.Header().Equal(header) {
+		t.Errorf("round tripped header does not match. want=%+v. got=%+v", header.data, decoded.Header().data)
+	}
+	if err := decoded.Header().VerifyProposerSignature(&keys[1].PublicKey); err != nil {
+		t.Errorf("round tripped co-proposer signature failed verification: %v", err)
+	}
+}
+
+func TestCollation_ProtoRoundTripRawData(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	header.WithBodyContentType(ContentTypeRawData)
+	c := NewCollation(header, []byte{1, 2, 3}, nil)
+	c.CalculateChunkRoot()
+
+	decoded, err := CollationFromProto(c.ToProto())
+	if err != nil {
+		t.Fatalf("could not convert collation from proto: %v", err)
+	}
+	if decoded.Header().BodyContentType() != ContentTypeRawData {
+		t.Errorf("body content type incorrect. want=%d. got=%d", ContentTypeRawData, decoded.Header().BodyContentType())
+	}
+	if !bytes.Equal(decoded.Body(), c.Body()) || len(decoded.Transactions()) != 0 {
+		t.Errorf("raw data body should be kept without transactions. got body=%x, %d transactions", decoded.Body(), len(decoded.Transactions()))
+	}
+
+	p := c.ToProto()
+	p.Header.BodyContentType = 3
+	if _, err := CollationFromProto(p); err == nil {
+		t.Errorf("converting a collation with an unknown body content type should fail")
+	}
+}
+
+func TestCollationFromProto_Invalid(t *testing.T) {
+	valid := func() *pb.Collation { return makeStoredCollation(t, 1, 10).ToProto() }
+
+	tests := []struct {
+		name   string
+		mutate func(p *pb.Collation)
+	}{
+		{name: "no header", mutate: func(p *pb.Collation) { p.Header = nil }},
+		{name: "bad address", mutate: func(p *pb.Collation) { p.Header.ProposerAddress = "0x1234" }},
+		{name: "zero address", mutate: func(p *pb.Collation) {
+			p.Header.ProposerAddress = "0x0000000000000000000000000000000000000000"
+		}},
+		{name: "short chunk root", mutate: func(p *pb.Collation) { p.Header.ChunkRoot = []byte{1, 2, 3} }},
+		{name: "shard out of range", mutate: func(p *pb.Collation) { p.Header.ShardId = 1 << 40 }},
+		{name: "short signature", mutate: func(p *pb.Collation) { p.Header.Signature = make([]byte, 10) }},
+		{name: "malformed body", mutate: func(p *pb.Collation) {
+			p.Body = append([]byte{0x1f}, bytes.Repeat([]byte{0xff}, 31)...)
+		}},
+	}
+	for _, tt := range tests {
+		p := valid()
+		tt.mutate(p)
+		if _, err := CollationFromProto(p); err == nil {
+			t.Errorf("%s: expected CollationFromProto to fail", tt.name)
+		}
+	}
+	if _, err := CollationFromProto(nil); err == nil {
+		t.Errorf("expected CollationFromProto to fail for a nil collation")
+	}
+}
+
+func TestCollationHeaderFromProto_ConfiguredShardCount(t *testing.T) {
+	cfg := ShardConfig{ShardCount: 200}
+	header, err := NewCollationHeader(big.NewInt(150), nil, big.NewInt(1), &testProposerAddress, make([]byte, 65), false, cfg)
+	if err != nil {
+		t.Fatalf("could not create collation header: %v", err)
+	}
+
+	decoded, err := CollationHeaderFromProto(header.ToProto(), cfg)
+	if err != nil {
+		t.Fatalf("could not convert header within the configured shard count: %v", err)
+	}
+	if !decoded.Equal(header) {
+		t.Errorf("round tripped header does not match. want=%+v. got=%+v", header.data, decoded.data)
+	}
+	if _, err := CollationHeaderFromProto(header.ToProto()); !errors.Is(err, ErrInvalidShardID) {
+		t.Errorf("header beyond the default shard count should be invalid. want=%v. got=%v", ErrInvalidShardID, err)
+	}
+}
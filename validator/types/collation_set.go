@@ -0,0 +1,38 @@
+package types
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// CollationSet is a concurrency safe set of collations keyed by their
+// fingerprint, used to detect collations that were already seen.
+type CollationSet struct {
+	fingerprints sync.Map
+	size         int64
+}
+
+// NewCollationSet creates an empty CollationSet.
+func NewCollationSet() *CollationSet {
+	return &CollationSet{}
+}
+
+// Add inserts the collation into the set, returning false if it was already present.
+func (s *CollationSet) Add(c *Collation) bool {
+	if _, loaded := s.fingerprints.LoadOrStore(c.Fingerprint(), struct{}{}); loaded {
+		return false
+	}
+	atomic.AddInt64(&s.size, 1)
+	return true
+}
+
+// Contains checks whether a collation with the same fingerprint was added to the set.
+func (s *CollationSet) Contains(c *Collation) bool {
+	_, ok := s.fingerprints.Load(c.Fingerprint())
+	return ok
+}
+
+// Size returns the number of collations in the set.
+func (s *CollationSet) Size() int {
+	return int(atomic.LoadInt64(&s.size))
+}
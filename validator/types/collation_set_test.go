@@ -0,0 +1,100 @@
+package types
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCollation_Fingerprint(t *testing.T) {
+	chunkRoot := common.BytesToHash([]byte{1})
+	otherRoot := common.BytesToHash([]byte{2})
+	base := NewCollation(newTestCollationHeader(t, big.NewInt(1), &chunkRoot, big.NewInt(2)), nil, nil)
+
+	same := NewCollation(newTestCollationHeader(t, big.NewInt(1), &chunkRoot, big.NewInt(2)), []byte{1}, nil)
+	same.Header().data.ProposerSignatures[0][0] = 1
+	if base.Fingerprint() != same.Fingerprint() {
+		t.Errorf("fingerprint should only depend on the shardID, period and chunk root")
+	}
+
+	different := []*Collation{
+		NewCollation(newTestCollationHeader(t, big.NewInt(2), &chunkRoot, big.NewInt(2)), nil, nil),
+		NewCollation(newTestCollationHeader(t, big.NewInt(1), &chunkRoot, big.NewInt(3)), nil, nil),
+		NewCollation(newTestCollationHeader(t, big.NewInt(1), &otherRoot, big.NewInt(2)), nil, nil),
+		NewCollation(newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(2)), nil, nil),
+		// shardID and period are padded so that they cannot be swapped.
+		NewCollation(newTestCollationHeader(t, big.NewInt(2), &chunkRoot, big.NewInt(1)), nil, nil),
+	}
+	for i, c := range different {
+		if c.Fingerprint() == base.Fingerprint() {
+			t.Errorf("collation %d should have a different fingerprint", i)
+		}
+	}
+}
+
+func TestCollationSet(t *testing.T) {
+	set := NewCollationSet()
+	a := makeStoredCollation(t, 1, 1)
+	b := makeStoredCollation(t, 1, 2)
+
+	if set.Contains(a) {
+		t.Errorf("empty set should not contain a collation")
+	}
+	if !set.Add(a) {
+		t.Errorf("adding a new collation should return true")
+	}
+	if set.Add(a) {
+		t.Errorf("adding a collation twice should return false")
+	}
+	if !set.Contains(a) || set.Contains(b) {
+		t.Errorf("set should only contain the added collation")
+	}
+	set.Add(b)
+	if set.Size() != 2 {
+		t.Errorf("set size incorrect. want=%d. got=%d", 2, set.Size())
+	}
+}
+
+func TestCollationSet_Concurrent(t *testing.T) {
+	set := NewCollationSet()
+	collations := make([]*Collation, 20)
+	for i := range collations {
+		collations[i] = makeStoredCollation(t, 1, int64(i))
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, c := range collations {
+				set.Add(c)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if set.Size() != len(collations) {
+		t.Errorf("set size incorrect. want=%d. got=%d", len(collations), set.Size())
+	}
+}
+
+func BenchmarkCollation_Fingerprint(b *testing.B) {
+	chunkRoot := common.BytesToHash([]byte{1})
+	c := NewCollation(newTestCollationHeader(b, big.NewInt(1), &chunkRoot, big.NewInt(2)), nil, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Fingerprint()
+	}
+}
+
+func BenchmarkCollationHeader_SignedHash(b *testing.B) {
+	chunkRoot := common.BytesToHash([]byte{1})
+	h := newTestCollationHeader(b, big.NewInt(1), &chunkRoot, big.NewInt(2))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.SignedHash()
+	}
+}
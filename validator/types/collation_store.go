@@ -0,0 +1,239 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// ErrCollationNotFound is returned by a CollationStore when no collation is
+// stored for a shardID, period pair.
+var ErrCollationNotFound = errors.New("collation not found")
+
+// CollationStore persists collations by their shardID and period.
+type CollationStore interface {
+	Put(c *Collation) error
+	Get(shardID *big.Int, period *big.Int) (*Collation, error)
+	Delete(shardID *big.Int, period *big.Int) error
+	Has(shardID *big.Int, period *big.Int) (bool, error)
+}
+
+// collationStoreKey computes keccak256(shardID || period), with both integers
+// encoded as 32 byte big-endian values. The encoding drops the sign and any
+// bytes past 32, so negative and oversized integers are rejected rather than
+// sharing the key of another shardID or period.
+func collationStoreKey(shardID *big.Int, period *big.Int) (common.Hash, error) {
+	if shardID == nil || period == nil {
+		return common.Hash{}, errors.New("collation store key requires a shardID and a period")
+	}
+	if shardID.Sign() < 0 || shardID.BitLen() > 256 {
+		return common.Hash{}, fmt.Errorf("shardID %v out of range for a collation store key: %w", shardID, ErrInvalidShardID)
+	}
+	if period.Sign() < 0 || period.BitLen() > 256 {
+		return common.Hash{}, fmt.Errorf("period %v out of range for a collation store key: %w", period, ErrInvalidPeriod)
+	}
+	return crypto.Keccak256Hash(common.BigToHash(shardID).Bytes(), common.BigToHash(period).Bytes()), nil
+}
+
+// decodeStoredCollation rebuilds a collation from its RLP encoded header and raw body.
+func decodeStoredCollation(encodedHeader []byte, body []byte) (*Collation, error) {
+	var header CollationHeader
+	if err := header.Decode(encodedHeader); err != nil {
+		return nil, fmt.Errorf("could not decode RLP header: %v", err)
+	}
+
+	collation := NewCollation(&header, body, nil)
+	if err := collation.VerifyBodyIntegrity(); err != nil {
+		return nil, err
+	}
+	if err := collation.Deserialize(); err != nil {
+		return nil, fmt.Errorf("cannot deserialize body: %v", err)
+	}
+	return collation, nil
+}
+
+// LevelDBCollationStore is a CollationStore persisting collations in LevelDB.
+// Headers are stored RLP encoded and bodies are stored raw, under the
+// keccak256(shardID || period) key prefixed by headerPrefix and bodyPrefix.
+type LevelDBCollationStore struct {
+	db *ethdb.LDBDatabase
+}
+
+var (
+	headerPrefix = []byte("h")
+	bodyPrefix   = []byte("b")
+)
+
+// prefixedKey prepends prefix to key in a newly allocated slice.
+func prefixedKey(prefix []byte, key common.Hash) []byte {
+	return append(append(make([]byte, 0, len(prefix)+common.HashLength), prefix...), key.Bytes()...)
+}
+
+// NewLevelDBCollationStore opens or creates a LevelDB collation store at path.
+func NewLevelDBCollationStore(path string) (*LevelDBCollationStore, error) {
+	db, err := ethdb.NewLDBDatabase(path, 16, 16)
+	if err != nil {
+		return nil, fmt.Errorf("could not open collation store: %v", err)
+	}
+	return &LevelDBCollationStore{db: db}, nil
+}
+
+// Close closes the underlying LevelDB database.
+func (s *LevelDBCollationStore) Close() {
+	s.db.Close()
+}
+
+// Put stores the collation's header and body in a single batch.
+func (s *LevelDBCollationStore) Put(c *Collation) error {
+	key, err := collationStoreKey(c.Header().ShardID(), c.Header().Period())
+	if err != nil {
+		return err
+	}
+	encoded, err := c.Header().Encode()
+	if err != nil {
+		return fmt.Errorf("cannot encode header: %v", err)
+	}
+
+	batch := s.db.NewBatch()
+	if err := batch.Put(prefixedKey(headerPrefix, key), encoded); err != nil {
+		return err
+	}
+	if err := batch.Put(prefixedKey(bodyPrefix, key), c.Body()); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Get fetches the collation stored for the shardID, period pair, returning
+// ErrCollationNotFound if there is none.
+func (s *LevelDBCollationStore) Get(shardID *big.Int, period *big.Int) (*Collation, error) {
+	key, err := collationStoreKey(shardID, period)
+	if err != nil {
+		return nil, err
+	}
+	has, err := s.db.Has(prefixedKey(headerPrefix, key))
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrCollationNotFound
+	}
+
+	encoded, err := s.db.Get(prefixedKey(headerPrefix, key))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch header: %v", err)
+	}
+	body, err := s.db.Get(prefixedKey(bodyPrefix, key))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch body: %v", err)
+	}
+	return decodeStoredCollation(encoded, body)
+}
+
+// Delete removes the collation stored for the shardID, period pair.
+func (s *LevelDBCollationStore) Delete(shardID *big.Int, period *big.Int) error {
+	key, err := collationStoreKey(shardID, period)
+	if err != nil {
+		return err
+	}
+	batch := s.db.NewBatch()
+	if err := batch.Delete(prefixedKey(headerPrefix, key)); err != nil {
+		return err
+	}
+	if err := batch.Delete(prefixedKey(bodyPrefix, key)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Has checks if a collation is stored for the shardID, period pair.
+func (s *LevelDBCollationStore) Has(shardID *big.Int, period *big.Int) (bool, error) {
+	key, err := collationStoreKey(shardID, period)
+	if err != nil {
+		return false, err
+	}
+	return s.db.Has(prefixedKey(headerPrefix, key))
+}
+
+// storedCollation is an encoded header and raw body kept by MemoryCollationStore.
+type storedCollation struct {
+	header []byte
+	body   []byte
+}
+
+// MemoryCollationStore is an in-memory CollationStore, useful for testing.
+type MemoryCollationStore struct {
+	collations map[common.Hash]storedCollation
+	lock       sync.RWMutex
+}
+
+// NewMemoryCollationStore creates an empty in-memory collation store.
+func NewMemoryCollationStore() *MemoryCollationStore {
+	return &MemoryCollationStore{collations: make(map[common.Hash]storedCollation)}
+}
+
+// Put stores a copy of the collation's encoded header and body.
+func (s *MemoryCollationStore) Put(c *Collation) error {
+	key, err := collationStoreKey(c.Header().ShardID(), c.Header().Period())
+	if err != nil {
+		return err
+	}
+	encoded, err := c.Header().Encode()
+	if err != nil {
+		return fmt.Errorf("cannot encode header: %v", err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.collations[key] = storedCollation{
+		header: encoded,
+		body:   append([]byte{}, c.Body()...),
+	}
+	return nil
+}
+
+// Get fetches the collation stored for the shardID, period pair, returning
+// ErrCollationNotFound if there is none.
+func (s *MemoryCollationStore) Get(shardID *big.Int, period *big.Int) (*Collation, error) {
+	key, err := collationStoreKey(shardID, period)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.RLock()
+	stored, ok := s.collations[key]
+	s.lock.RUnlock()
+	if !ok {
+		return nil, ErrCollationNotFound
+	}
+	return decodeStoredCollation(stored.header, append([]byte{}, stored.body...))
+}
+
+// Delete removes the collation stored for the shardID, period pair.
+func (s *MemoryCollationStore) Delete(shardID *big.Int, period *big.Int) error {
+	key, err := collationStoreKey(shardID, period)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.collations, key)
+	return nil
+}
+
+// Has checks if a collation is stored for the shardID, period pair.
+func (s *MemoryCollationStore) Has(shardID *big.Int, period *big.Int) (bool, error) {
+	key, err := collationStoreKey(shardID, period)
+	if err != nil {
+		return false, err
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	_, ok := s.collations[key]
+	return ok, nil
+}
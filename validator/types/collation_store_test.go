@@ -0,0 +1,169 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+	"testing"
+)
+
+func setupLevelDBCollationStore(t *testing.T) (*LevelDBCollationStore, func()) {
+	dir, err := ioutil.TempDir("", "collationstore")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	store, err := NewLevelDBCollationStore(dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("could not create collation store: %v", err)
+	}
+	return store, func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func makeStoredCollation(t *testing.T, shardID int64, period int64) *Collation {
+	header := newTestCollationHeader(t, big.NewInt(shardID), nil, big.NewInt(period))
+	txs := makeRandomTransactions(3)
+	body, err := SerializeTxToBlob(txs)
+	if err != nil {
+		t.Fatalf("could not serialize transactions: %v", err)
+	}
+	c := NewCollation(header, body, txs)
+	c.CalculateChunkRoot()
+	return c
+}
+
+func testCollationStoreRoundTrip(t *testing.T, store CollationStore) {
+	c := makeStoredCollation(t, 1, 10)
+
+	if has, err := store.Has(big.NewInt(1), big.NewInt(10)); err != nil || has {
+		t.Fatalf("store should not have collation before Put. has=%v, err=%v", has, err)
+	}
+	if _, err := store.Get(big.NewInt(1), big.NewInt(10)); err != ErrCollationNotFound {
+		t.Errorf("expected ErrCollationNotFound, got %v", err)
+	}
+
+	if err := store.Put(c); err != nil {
+		t.Fatalf("could not put collation: %v", err)
+	}
+	if has, err := store.Has(big.NewInt(1), big.NewInt(10)); err != nil || !has {
+		t.Fatalf("store should have collation after Put. has=%v, err=%v", has, err)
+	}
+
+	stored, err := store.Get(big.NewInt(1), big.NewInt(10))
+	if err != nil {
+		t.Fatalf("could not get collation: %v", err)
+	}
+	if !stored.Header().Equal(c.Header()) {
+		t.Errorf("stored header does not match. want=%+v. got=%+v", c.Header().data, stored.Header().data)
+	}
+	if !bytes.Equal(stored.Body(), c.Body()) {
+		t.Errorf("stored body does not match")
+	}
+	if len(stored.Transactions()) != len(c.Transactions()) {
+		t.Fatalf("stored transaction count incorrect. want=%d. got=%d", len(c.Transactions()), len(stored.Transactions()))
+	}
+	for i, tx := range stored.Transactions() {
+		if tx.Hash() != c.Transactions()[i].Hash() {
+			t.Errorf("stored transaction %d does not match", i)
+		}
+	}
+
+	if has, _ := store.Has(big.NewInt(1), big.NewInt(11)); has {
+		t.Errorf("store should not have a collation for another period")
+	}
+
+	if err := store.Delete(big.NewInt(1), big.NewInt(10)); err != nil {
+		t.Fatalf("could not delete collation: %v", err)
+	}
+	if _, err := store.Get(big.NewInt(1), big.NewInt(10)); err != ErrCollationNotFound {
+		t.Errorf("expected ErrCollationNotFound after Delete, got %v", err)
+	}
+	if _, err := store.Has(nil, big.NewInt(10)); err == nil {
+		t.Errorf("Has with a nil shardID should fail")
+	}
+}
+
+func testCollationStoreConcurrency(t *testing.T, store CollationStore) {
+	var wg sync.WaitGroup
+	for i := int64(0); i < 20; i++ {
+		c := makeStoredCollation(t, i%5, i)
+		wg.Add(1)
+		go func(c *Collation) {
+			defer wg.Done()
+			if err := store.Put(c); err != nil {
+				t.Errorf("could not put collation: %v", err)
+			}
+			if _, err := store.Get(c.Header().ShardID(), c.Header().Period()); err != nil {
+				t.Errorf("could not get collation: %v", err)
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	for i := int64(0); i < 20; i++ {
+		if has, err := store.Has(big.NewInt(i%5), big.NewInt(i)); err != nil || !has {
+			t.Errorf("store should have collation for shard %d, period %d", i%5, i)
+		}
+	}
+}
+
+func TestCollationStoreKey(t *testing.T) {
+	key, err := collationStoreKey(big.NewInt(1), big.NewInt(5))
+	if err != nil {
+		t.Fatalf("could not compute collation store key: %v", err)
+	}
+	oversized := new(big.Int).Lsh(big.NewInt(1), 256)
+
+	tests := []struct {
+		name    string
+		shardID *big.Int
+		period  *big.Int
+		wantErr error
+	}{
+		{name: "negative shardID", shardID: big.NewInt(-1), period: big.NewInt(5), wantErr: ErrInvalidShardID},
+		{name: "negative period", shardID: big.NewInt(1), period: big.NewInt(-5), wantErr: ErrInvalidPeriod},
+		{name: "oversized shardID", shardID: new(big.Int).Add(oversized, big.NewInt(1)), period: big.NewInt(5), wantErr: ErrInvalidShardID},
+		{name: "oversized period", shardID: big.NewInt(1), period: new(big.Int).Add(oversized, big.NewInt(5)), wantErr: ErrInvalidPeriod},
+	}
+	for _, tt := range tests {
+		got, err := collationStoreKey(tt.shardID, tt.period)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: want=%v. got=%v", tt.name, tt.wantErr, err)
+		}
+		if got == key {
+			t.Errorf("%s: should not share the key of shardID 1, period 5", tt.name)
+		}
+	}
+}
+
+func testCollationStoreNegativeShardID(t *testing.T, store CollationStore) {
+	c := makeStoredCollation(t, 1, 12)
+	c.header.data.ShardID = big.NewInt(-1)
+	if err := store.Put(c); !errors.Is(err, ErrInvalidShardID) {
+		t.Errorf("putting a collation with a negative shardID should fail. want=%v. got=%v", ErrInvalidShardID, err)
+	}
+	if has, _ := store.Has(big.NewInt(1), big.NewInt(12)); has {
+		t.Errorf("collation with a negative shardID should not be stored under its absolute value")
+	}
+}
+
+func TestLevelDBCollationStore(t *testing.T) {
+	store, teardown := setupLevelDBCollationStore(t)
+	defer teardown()
+	testCollationStoreRoundTrip(t, store)
+	testCollationStoreConcurrency(t, store)
+	testCollationStoreNegativeShardID(t, store)
+}
+
+func TestMemoryCollationStore(t *testing.T) {
+	store := NewMemoryCollationStore()
+	testCollationStoreRoundTrip(t, store)
+	testCollationStoreConcurrency(t, store)
+	testCollationStoreNegativeShardID(t, store)
+}
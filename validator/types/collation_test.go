@@ -2,18 +2,547 @@ package types
 
 import (
 	"bytes"
+	"crypto/ecdsa"
 	"crypto/rand"
+	"errors"
+	"math"
 	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/prysmaticlabs/prysm/shared/hashutil"
 	"github.com/prysmaticlabs/prysm/shared/shardutil"
+	"github.com/prysmaticlabs/prysm/validator/params"
 )
 
+// testProposerAddress is a non-zero proposer address used to build valid headers in tests.
+var testProposerAddress = common.HexToAddress("0x0ee3e8ae19a3c2a6fce4a4d5a5e8d4e76f63e0c6")
+
+// newTestCollationHeader builds a valid collation header with a placeholder proposer
+// signature, failing the test if the header cannot be created.
+func newTestCollationHeader(t testing.TB, shardID *big.Int, chunkRoot *common.Hash, period *big.Int) *CollationHeader {
+	header, err := NewCollationHeader(shardID, chunkRoot, period, &testProposerAddress, make([]byte, 65), false)
+	if err != nil {
+		t.Fatalf("could not create collation header: %v", err)
+	}
+	return header
+}
+
+func TestCollationHeader_Validate(t *testing.T) {
+	zeroAddress := common.Address{}
+	tests := []struct {
+		name            string
+		shardID         *big.Int
+		period          *big.Int
+		proposerAddress *common.Address
+		signature       []byte
+	}{
+		{
+			name:            "negative shardID",
+			shardID:         big.NewInt(-1),
+			period:          big.NewInt(1),
+			proposerAddress: &testProposerAddress,
+			signature:       make([]byte, 65),
+		},
+		{
+			name:            "shardID out of range",
+			shardID:         big.NewInt(100),
+			period:          big.NewInt(1),
+			proposerAddress: &testProposerAddress,
+			signature:       make([]byte, 65),
+		},
+		{
+			name:            "nil shardID",
+			shardID:         nil,
+			period:          big.NewInt(1),
+			proposerAddress: &testProposerAddress,
+			signature:       make([]byte, 65),
+		},
+		{
+			name:            "negative period",
+			shardID:         big.NewInt(1),
+			period:          big.NewInt(-1),
+			proposerAddress: &testProposerAddress,
+			signature:       make([]byte, 65),
+		},
+		{
+			name:            "nil proposer address",
+			shardID:         big.NewInt(1),
+			period:          big.NewInt(1),
+			proposerAddress: nil,
+			signature:       make([]byte, 65),
+		},
+		{
+			name:            "zero proposer address",
+			shardID:         big.NewInt(1),
+			period:          big.NewInt(1),
+			proposerAddress: &zeroAddress,
+			signature:       make([]byte, 65),
+		},
+		{
+			name:            "short signature",
+			shardID:         big.NewInt(1),
+			period:          big.NewInt(1),
+			proposerAddress: &testProposerAddress,
+			signature:       make([]byte, 32),
+		},
+		{
+			name:            "long signature",
+			shardID:         big.NewInt(1),
+			period:          big.NewInt(1),
+			proposerAddress: &testProposerAddress,
+			signature:       make([]byte, 66),
+		},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewCollationHeader(tt.shardID, nil, tt.period, tt.proposerAddress, tt.signature, false); err == nil {
+			t.Errorf("%s: expected header creation to fail", tt.name)
+		}
+	}
+
+	header, err := NewCollationHeader(big.NewInt(99), nil, big.NewInt(0), &testProposerAddress, make([]byte, 65), false)
+	if err != nil {
+		t.Fatalf("could not create valid collation header: %v", err)
+	}
+	if err := header.Validate(); err != nil {
+		t.Errorf("valid header failed validation: %v", err)
+	}
+}
+
+func TestCollationHeader_VerifyProposerSignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	otherKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	proposerAddress := crypto.PubkeyToAddress(key.PublicKey)
+	chunkRoot := common.BytesToHash([]byte{1, 2, 3})
+
+	header, err := NewCollationHeader(big.NewInt(1), &chunkRoot, big.NewInt(2), &proposerAddress, make([]byte, 65), false)
+	if err != nil {
+		t.Fatalf("could not create collation header: %v", err)
+	}
+
+	if err := header.Sign(key); err != nil {
+		t.Fatalf("could not sign header: %v", err)
+	}
+
+	if err := header.VerifyProposerSignature(&key.PublicKey); err != nil {
+		t.Errorf("valid proposer signature failed verification: %v", err)
+	}
+	if err := header.VerifyProposerSignature(&otherKey.PublicKey); err == nil {
+		t.Errorf("signature verification should fail for a different public key")
+	}
+	if err := header.VerifyProposerSignature(nil); err == nil {
+		t.Errorf("signature verification should fail without a public key")
+	}
+
+	otherSig, err := crypto.Sign(header.UnsignedHash().Bytes(), otherKey)
+	if err != nil {
+		t.Fatalf("could not sign header: %v", err)
+	}
+	header.AddSig(otherSig)
+	if err := header.VerifyProposerSignature(&otherKey.PublicKey); err == nil {
+		t.Errorf("signature verification should fail when signer is not the proposer")
+	}
+}
+
+func TestCollationHeader_SetProposerSignature(t *testing.T) {
+	header, err := NewCollationHeader(big.NewInt(1), nil, big.NewInt(2), &testProposerAddress, nil, false)
+	if err != nil {
+		t.Fatalf("unsigned header should be valid: %v", err)
+	}
+
+	if err := header.SetProposerSignature(make([]byte, 64)); err == nil {
+		t.Errorf("setting a signature with the wrong length should fail")
+	}
+	sig := make([]byte, 65)
+	sig[0] = 1
+	if err := header.SetProposerSignature(sig); err != nil {
+		t.Fatalf("could not set proposer signature: %v", err)
+	}
+	if !bytes.Equal(header.Sig(), sig) {
+		t.Errorf("proposer signature incorrect. want=%x. got=%x", sig, header.Sig())
+	}
+	if err := header.SetProposerSignature(make([]byte, 65)); err == nil {
+		t.Errorf("setting the signature of a signed header should fail")
+	}
+
+	header.ClearSignature()
+	if len(header.Sig()) != 0 {
+		t.Errorf("signature should be empty after ClearSignature. got=%x", header.Sig())
+	}
+	if err := header.SetProposerSignature(sig); err != nil {
+		t.Errorf("could not set proposer signature after ClearSignature: %v", err)
+	}
+}
+
+func TestCollationHeader_UnsignedHash(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	proposerAddress := crypto.PubkeyToAddress(key.PublicKey)
+	chunkRoot := common.BytesToHash([]byte{1, 2, 3})
+	header, err := NewCollationHeader(big.NewInt(1), &chunkRoot, big.NewInt(2), &proposerAddress, make([]byte, 65), false)
+	if err != nil {
+		t.Fatalf("could not create collation header: %v", err)
+	}
+
+	unsignedHash := header.UnsignedHash()
+	signedHash := header.SignedHash()
+	if err := header.Sign(key); err != nil {
+		t.Fatalf("could not sign header: %v", err)
+	}
+
+	if header.UnsignedHash() != unsignedHash {
+		t.Errorf("unsigned hash changed after signing. want=%v. got=%v", unsignedHash.Hex(), header.UnsignedHash().Hex())
+	}
+	if header.HashNoSig() != header.UnsignedHash() {
+		t.Errorf("HashNoSig should equal UnsignedHash")
+	}
+	if header.SignedHash() == signedHash {
+		t.Errorf("signed hash should change after signing")
+	}
+	if header.Hash() != header.SignedHash() {
+		t.Errorf("Hash should equal SignedHash")
+	}
+}
+
+// newMultiProposerHeader creates an unsigned header proposed by the owners of the keys, in order.
+func newMultiProposerHeader(t *testing.T, keys []*ecdsa.PrivateKey) *CollationHeader {
+	chunkRoot := common.BytesToHash([]byte{1, 2, 3})
+	first := crypto.PubkeyToAddress(keys[0].PublicKey)
+	header, err := NewCollationHeader(big.NewInt(1), &chunkRoot, big.NewInt(2), &first, nil, false)
+	if err != nil {
+		t.Fatalf("could not create collation header: %v", err)
+	}
+	for _, key := range keys[1:] {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		if err := header.AddProposer(&addr); err != nil {
+			t.Fatalf("could not add proposer: %v", err)
+		}
+	}
+	return header
+}
+
+func generateKeys(t *testing.T, n int) []*ecdsa.PrivateKey {
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("could not generate key: %v", err)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+func TestCollationHeader_MultiProposer(t *testing.T) {
+	keys := generateKeys(t, 4)
+	header := newMultiProposerHeader(t, keys[:3])
+
+	if err := header.AddProposer(header.ProposerAddresses()[1]); err == nil {
+		t.Errorf("adding a proposer twice should fail")
+	}
+	if err := header.AddProposer(&common.Address{}); err == nil {
+		t.Errorf("adding the zero address as a proposer should fail")
+	}
+	if len(header.ProposerAddresses()) != 3 {
+		t.Fatalf("proposer count incorrect. want=%d. got=%d", 3, len(header.ProposerAddresses()))
+	}
+
+	for i, key := range keys[:3] {
+		if err := header.VerifyAllProposerSignatures(); err == nil {
+			t.Errorf("verification should fail with %d of 3 signatures", i)
+		}
+		if err := header.Sign(key); err != nil {
+			t.Fatalf("could not sign header: %v", err)
+		}
+	}
+	if err := header.VerifyAllProposerSignatures(); err != nil {
+		t.Errorf("valid proposer signatures failed verification: %v", err)
+	}
+	if err := header.Validate(); err != nil {
+		t.Errorf("signed multi-proposer header should be valid: %v", err)
+	}
+	for _, key := range keys[:3] {
+		if err := header.VerifyProposerSignature(&key.PublicKey); err != nil {
+			t.Errorf("valid proposer signature failed verification: %v", err)
+		}
+	}
+	if err := header.Sign(keys[3]); err == nil {
+		t.Errorf("signing by a key that is not a proposer should fail")
+	}
+
+	// A signature swapped between proposers no longer matches the address at its index.
+	sigs := header.ProposerSignatures()
+	header.data.ProposerSignatures[0], header.data.ProposerSignatures[1] = sigs[1], sigs[0]
+	if err := header.VerifyAllProposerSignatures(); !errors.Is(err, ErrInvalidProposerSignature) {
+		t.Errorf("swapped signatures should fail verification. want=%v. got=%v", ErrInvalidProposerSignature, err)
+	}
+}
+
+func TestCollationHeader_MultiProposerHashOrder(t *testing.T) {
+	keys := generateKeys(t, 3)
+	a := newMultiProposerHeader(t, keys)
+	b := newMultiProposerHeader(t, []*ecdsa.PrivateKey{keys[2], keys[0], keys[1]})
+
+	if a.UnsignedHash() != b.UnsignedHash() {
+		t.Errorf("unsigned hash should not depend on the proposer order. want=%v. got=%v", a.UnsignedHash().Hex(), b.UnsignedHash().Hex())
+	}
+	for _, key := range keys {
+		if err := a.Sign(key); err != nil {
+			t.Fatalf("could not sign header: %v", err)
+		}
+		if err := b.Sign(key); err != nil {
+			t.Fatalf("could not sign header: %v", err)
+		}
+	}
+	if a.SignedHash() != b.SignedHash() {
+		t.Errorf("signed hash should not depend on the proposer order. want=%v. got=%v", a.SignedHash().Hex(), b.SignedHash().Hex())
+	}
+	if !a.Equal(b) {
+		t.Errorf("headers with the same proposers in another order should be equal")
+	}
+
+	single := newMultiProposerHeader(t, keys[:1])
+	if single.UnsignedHash() == a.UnsignedHash() {
+		t.Errorf("unsigned hash should cover every proposer")
+	}
+}
+
+func TestCollationHeader_MultiProposerRLP(t *testing.T) {
+	keys := generateKeys(t, 3)
+	header := newMultiProposerHeader(t, keys)
+	for _, key := range keys {
+		if err := header.Sign(key); err != nil {
+			t.Fatalf("could not sign header: %v", err)
+		}
+	}
+
+	encoded, err := header.EncodeRLP()
+	if err != nil {
+		t.Fatalf("could not encode header: %v", err)
+	}
+	decoded := &CollationHeader{}
+	if err := rlp.DecodeBytes(encoded, decoded); err != nil {
+		t.Fatalf("could not decode header: %v", err)
+	}
+	if !decoded.Equal(header) {
+		t.Errorf("decoded header does not match. want=%+v. got=%+v", header.data, decoded.data)
+	}
+	if err := decoded.VerifyAllProposerSignatures(); err != nil {
+		t.Errorf("decoded signatures failed verification: %v", err)
+	}
+}
+
+func TestCollationHeader_ShardIDUint64(t *testing.T) {
+	tests := []struct {
+		shardID *big.Int
+		want    uint64
+		ok      bool
+	}{
+		{shardID: big.NewInt(5), want: 5, ok: true},
+		{shardID: new(big.Int).SetUint64(math.MaxUint64), want: math.MaxUint64, ok: true},
+		{shardID: new(big.Int).Lsh(big.NewInt(1), 64)},
+		{shardID: big.NewInt(-1)},
+		{},
+	}
+	for _, tt := range tests {
+		header := &CollationHeader{data: collationHeaderData{ShardID: tt.shardID}}
+		got, ok := header.ShardIDUint64()
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("shardID %v converted incorrectly. want=%d, %v. got=%d, %v", tt.shardID, tt.want, tt.ok, got, ok)
+		}
+	}
+}
+
+func TestCollationHeader_ProposerAddressString(t *testing.T) {
+	// EIP-55 test vector.
+	want := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	proposer := common.HexToAddress(strings.ToLower(want))
+	header, err := NewCollationHeader(big.NewInt(1), nil, big.NewInt(1), &proposer, nil, false)
+	if err != nil {
+		t.Fatalf("could not create header: %v", err)
+	}
+	if got := header.ProposerAddressString(); got != want {
+		t.Errorf("proposer address should be checksum encoded. want=%s. got=%s", want, got)
+	}
+	if got := (&CollationHeader{}).ProposerAddressString(); got != "" {
+		t.Errorf("header without proposer should return an empty string. got=%s", got)
+	}
+}
+
+func TestCollationHeader_EncodeDecode(t *testing.T) {
+	keys := generateKeys(t, 3)
+	multiProposer := newMultiProposerHeader(t, keys)
+	for _, key := range keys {
+		if err := multiProposer.Sign(key); err != nil {
+			t.Fatalf("could not sign header: %v", err)
+		}
+	}
+	chunkRoot := common.BytesToHash([]byte("chunk root"))
+	withChunkRoot := newTestCollationHeader(t, big.NewInt(2), &chunkRoot, big.NewInt(7))
+	withChunkRoot.WithSkipEvm(true)
+	withChunkRoot.WithBodyContentType(ContentTypeRawData)
+
+	tests := []struct {
+		name   string
+		header *CollationHeader
+	}{
+		{name: "unsigned header", header: newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))},
+		{name: "header with chunk root", header: withChunkRoot},
+		{name: "multi proposer header", header: multiProposer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.header.Encode()
+			if err != nil {
+				t.Fatalf("could not encode header: %v", err)
+			}
+			legacy, err := tt.header.EncodeRLP()
+			if err != nil {
+				t.Fatalf("could not encode header: %v", err)
+			}
+			if !bytes.Equal(encoded, legacy) {
+				t.Errorf("Encode should match EncodeRLP. want=%#x. got=%#x", legacy, encoded)
+			}
+			decoded := &CollationHeader{}
+			if err := decoded.Decode(encoded); err != nil {
+				t.Fatalf("could not decode header: %v", err)
+			}
+			if !decoded.Equal(tt.header) {
+				t.Errorf("decoded header does not match. want=%+v. got=%+v", tt.header.data, decoded.data)
+			}
+		})
+	}
+
+	if err := (&CollationHeader{}).Decode([]byte{0x01, 0x02}); err == nil {
+		t.Errorf("decoding malformed data should fail")
+	}
+}
+
+func TestCollationHeader_SingleProposerLegacyRLP(t *testing.T) {
+	// legacyHeader is the RLP layout of headers before multi-proposer collations.
+	type legacyHeader struct {
+		ShardID                    *big.Int
+		ChunkRoot                  *common.Hash
+		Period                     *big.Int
+		ProposerAddress            *common.Address
+		ProposerSignature          []byte
+		SkipEvmExecution           bool
+		Compressed                 bool
+		AggregateProposerSignature []byte
+	}
+	key := generateKeys(t, 1)[0]
+	header := newMultiProposerHeader(t, []*ecdsa.PrivateKey{key})
+	if err := header.Sign(key); err != nil {
+		t.Fatalf("could not sign header: %v", err)
+	}
+	legacy := legacyHeader{
+		ShardID:           header.ShardID(),
+		ChunkRoot:         header.ChunkRoot(),
+		Period:            header.Period(),
+		ProposerAddress:   header.ProposerAddresses()[0],
+		ProposerSignature: header.Sig(),
+	}
+
+	want, err := rlp.EncodeToBytes(&legacy)
+	if err != nil {
+		t.Fatalf("could not encode legacy header: %v", err)
+	}
+	got, err := header.EncodeRLP()
+	if err != nil {
+		t.Fatalf("could not encode header: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("single proposer encoding should match the legacy layout. want=%x. got=%x", want, got)
+	}
+
+	decoded := &CollationHeader{}
+	if err := rlp.DecodeBytes(want, decoded); err != nil {
+		t.Fatalf("could not decode legacy header: %v", err)
+	}
+	if !decoded.Equal(header) {
+		t.Errorf("decoded legacy header does not match. want=%+v. got=%+v", header.data, decoded.data)
+	}
+
+	legacyUnsigned, err := rlp.EncodeToBytes([]interface{}{legacy.ShardID, legacy.ChunkRoot, legacy.Period, legacy.ProposerAddress})
+	if err != nil {
+		t.Fatalf("could not encode legacy unsigned header: %v", err)
+	}
+	if header.UnsignedHash() != hashutil.Hash(legacyUnsigned) {
+		t.Errorf("single proposer unsigned hash should match the legacy hash")
+	}
+}
+
+func TestCollationHeader_Equal(t *testing.T) {
+	chunkRoot := common.BytesToHash([]byte{1})
+	otherRoot := common.BytesToHash([]byte{2})
+	otherAddress := common.HexToAddress("0x2")
+	otherSig := make([]byte, 65)
+	otherSig[0] = 1
+
+	header := newTestCollationHeader(t, big.NewInt(1), &chunkRoot, big.NewInt(2))
+	same := newTestCollationHeader(t, big.NewInt(1), &chunkRoot, big.NewInt(2))
+	if !header.Equal(same) {
+		t.Errorf("headers with the same fields should be equal")
+	}
+
+	different := []*CollationHeader{
+		newTestCollationHeader(t, big.NewInt(2), &chunkRoot, big.NewInt(2)),
+		newTestCollationHeader(t, big.NewInt(1), &otherRoot, big.NewInt(2)),
+		newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(2)),
+		newTestCollationHeader(t, big.NewInt(1), &chunkRoot, big.NewInt(3)),
+	}
+	withAddress, err := NewCollationHeader(big.NewInt(1), &chunkRoot, big.NewInt(2), &otherAddress, make([]byte, 65), false)
+	if err != nil {
+		t.Fatalf("could not create collation header: %v", err)
+	}
+	withSig, err := NewCollationHeader(big.NewInt(1), &chunkRoot, big.NewInt(2), &testProposerAddress, otherSig, false)
+	if err != nil {
+		t.Fatalf("could not create collation header: %v", err)
+	}
+	different = append(different, withAddress, withSig)
+
+	for i, other := range different {
+		if header.Equal(other) {
+			t.Errorf("header %d should not be equal: %+v", i, other.data)
+		}
+	}
+	if header.Equal(nil) {
+		t.Errorf("header should not be equal to nil")
+	}
+}
+
+func TestCollationHeader_Less(t *testing.T) {
+	a := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(5))
+	b := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(6))
+	c := newTestCollationHeader(t, big.NewInt(2), nil, big.NewInt(0))
+
+	if !a.Less(b) || b.Less(a) {
+		t.Errorf("headers in the same shard should be ordered by period")
+	}
+	if !b.Less(c) || c.Less(b) {
+		t.Errorf("headers should be ordered by shardID first")
+	}
+	if a.Less(a) {
+		t.Errorf("a header should not be less than itself")
+	}
+}
+
 func TestCollation_Transactions(t *testing.T) {
-	header := NewCollationHeader(big.NewInt(1), nil, big.NewInt(1), nil, [32]byte{})
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
 	body := []byte{}
 	transactions := []*gethTypes.Transaction{
 		makeTxWithGasLimit(0),
@@ -32,9 +561,196 @@ func TestCollation_Transactions(t *testing.T) {
 }
 
 // Tests that Transactions can be serialised
+func TestCollation_TransactionAt(t *testing.T) {
+	c := makeStoredCollation(t, 1, 1)
+	if c.TransactionCount() != len(c.Transactions()) {
+		t.Fatalf("transaction count incorrect. want=%d. got=%d", len(c.Transactions()), c.TransactionCount())
+	}
+	for i, want := range c.Transactions() {
+		tx, err := c.TransactionAt(i)
+		if err != nil {
+			t.Fatalf("could not get transaction %d: %v", i, err)
+		}
+		if tx != want {
+			t.Errorf("transaction %d incorrect. want=%v. got=%v", i, want.Hash().Hex(), tx.Hash().Hex())
+		}
+	}
+	for _, index := range []int{-1, c.TransactionCount()} {
+		if _, err := c.TransactionAt(index); err == nil {
+			t.Errorf("getting the transaction at out of range index %d should fail", index)
+		}
+	}
+
+	empty := NewCollation(nil, nil, nil)
+	if empty.TransactionCount() != 0 {
+		t.Errorf("collation without transactions should have a count of 0. got=%d", empty.TransactionCount())
+	}
+	if _, err := empty.TransactionAt(0); err == nil {
+		t.Errorf("getting a transaction of an empty collation should fail")
+	}
+}
+
+func TestCollation_TransactionByHash(t *testing.T) {
+	c := makeStoredCollation(t, 1, 1)
+	for i, want := range c.Transactions() {
+		tx, index, ok := c.TransactionByHash(want.Hash())
+		if !ok || tx != want || index != i {
+			t.Errorf("transaction %d lookup incorrect. want=%v,%d. got=%v,%d,%v", i, want.Hash().Hex(), i, tx, index, ok)
+		}
+	}
+	missing := makeRandomTransactions(1)[0]
+	if _, _, ok := c.TransactionByHash(missing.Hash()); ok {
+		t.Errorf("transaction outside the collation should not be found")
+	}
+
+	// Replacing the transactions and body drops the stale index.
+	c.transactions = append(c.transactions, missing)
+	body, err := c.Serialize()
+	if err != nil {
+		t.Fatalf("could not serialize transactions: %v", err)
+	}
+	c.setBody(body)
+	tx, index, ok := c.TransactionByHash(missing.Hash())
+	if !ok || tx != missing || index != len(c.transactions)-1 {
+		t.Errorf("added transaction lookup incorrect. want=%d. got=%d,%v", len(c.transactions)-1, index, ok)
+	}
+}
+
+func TestCollation_ProposerFee(t *testing.T) {
+	makeTx := func(gas uint64, gasPrice *big.Int) *gethTypes.Transaction {
+		return gethTypes.NewTransaction(0 /*nonce*/, common.HexToAddress("0x0") /*to*/, nil /*amount*/, gas, gasPrice, nil /*data*/)
+	}
+	maxPrice, _ := new(big.Int).SetString("ffffffffffffffffffffffffffffffff", 16)
+	// 2^128-1 times 2^64-1 times 2^80 does not fit in 256 bits.
+	hugeNumerator := new(big.Int).Lsh(big.NewInt(1), 80)
+
+	tests := []struct {
+		name         string
+		transactions []*gethTypes.Transaction
+		numerator    *big.Int
+		denominator  *big.Int
+		want         *big.Int
+		wantErr      bool
+	}{
+		{name: "no transactions", transactions: nil, numerator: big.NewInt(1), denominator: big.NewInt(2), want: big.NewInt(0)},
+		{name: "half", transactions: []*gethTypes.Transaction{makeTx(21000, big.NewInt(2)), makeTx(100, big.NewInt(3))}, numerator: big.NewInt(1), denominator: big.NewInt(2), want: big.NewInt(21150)},
+		{name: "rounded down", transactions: []*gethTypes.Transaction{makeTx(100, big.NewInt(1))}, numerator: big.NewInt(1), denominator: big.NewInt(3), want: big.NewInt(33)},
+		{name: "whole", transactions: []*gethTypes.Transaction{makeTx(21000, big.NewInt(2))}, numerator: big.NewInt(7), denominator: big.NewInt(7), want: big.NewInt(42000)},
+		{name: "zero denominator", transactions: []*gethTypes.Transaction{makeTx(21000, big.NewInt(2))}, numerator: big.NewInt(1), denominator: big.NewInt(0), wantErr: true},
+		{name: "negative fraction", transactions: []*gethTypes.Transaction{makeTx(21000, big.NewInt(2))}, numerator: big.NewInt(-1), denominator: big.NewInt(2), wantErr: true},
+		{name: "nil fraction", transactions: []*gethTypes.Transaction{makeTx(21000, big.NewInt(2))}, numerator: nil, denominator: big.NewInt(2), wantErr: true},
+		{name: "overflow", transactions: []*gethTypes.Transaction{makeTx(math.MaxUint64, maxPrice)}, numerator: hugeNumerator, denominator: big.NewInt(1), wantErr: true},
+		{name: "nil transaction", transactions: []*gethTypes.Transaction{nil}, numerator: big.NewInt(1), denominator: big.NewInt(2), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		c := NewCollation(newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1)), nil, tt.transactions)
+		fee, err := c.ProposerFee(tt.numerator, tt.denominator)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: computing the proposer fee should fail", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: could not compute proposer fee: %v", tt.name, err)
+		}
+		if fee.Cmp(tt.want) != 0 {
+			t.Errorf("%s: proposer fee incorrect. want=%v. got=%v", tt.name, tt.want, fee)
+		}
+	}
+}
+
+func TestCollation_VerifyBodyIntegrity(t *testing.T) {
+	c := makeStoredCollation(t, 1, 1)
+	if err := c.VerifyBodyIntegrity(); err != nil {
+		t.Errorf("untampered body failed verification: %v", err)
+	}
+
+	// Tampering with the body in place leaves the cached body hash untouched, so
+	// the chunk root must be recomputed to notice.
+	c.body[0] ^= 0xff
+	if err := c.VerifyBodyIntegrity(); !errors.Is(err, ErrChunkRootMismatch) {
+		t.Errorf("tampered body error incorrect. want=%v. got=%v", ErrChunkRootMismatch, err)
+	}
+
+	unset := NewCollation(newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1)), []byte{1}, nil)
+	if err := unset.VerifyBodyIntegrity(); !errors.Is(err, ErrChunkRootMismatch) {
+		t.Errorf("body without chunk root error incorrect. want=%v. got=%v", ErrChunkRootMismatch, err)
+	}
+}
+
+func TestCollation_TamperedBodyRejected(t *testing.T) {
+	c := makeStoredCollation(t, 1, 1)
+	p := c.ToProto()
+	p.Body = append([]byte{}, p.Body...)
+	p.Body[len(p.Body)-1] ^= 0xff
+	if _, err := CollationFromProto(p); !errors.Is(err, ErrChunkRootMismatch) {
+		t.Errorf("tampered proto body error incorrect. want=%v. got=%v", ErrChunkRootMismatch, err)
+	}
+
+	encoded, err := c.Header().EncodeRLP()
+	if err != nil {
+		t.Fatalf("could not encode header: %v", err)
+	}
+	if _, err := decodeStoredCollation(encoded, p.Body); !errors.Is(err, ErrChunkRootMismatch) {
+		t.Errorf("tampered stored body error incorrect. want=%v. got=%v", ErrChunkRootMismatch, err)
+	}
+}
+
+func TestCollation_Reserialize(t *testing.T) {
+	c := makeStoredCollation(t, 1, 1)
+	staleRoot := *c.Header().ChunkRoot()
+
+	added := makeRandomTransactions(2)
+	c.transactions = append(c.transactions[1:], added...)
+	// The chunk root only tracks the body, so it still looks fresh.
+	if !c.IsChunkRootFresh() {
+		t.Fatalf("chunk root should not track transaction changes before reserializing")
+	}
+	if err := c.Reserialize(); err != nil {
+		t.Fatalf("could not reserialize collation: %v", err)
+	}
+
+	want, err := SerializeTxToBlob(c.transactions)
+	if err != nil {
+		t.Fatalf("could not serialize transactions: %v", err)
+	}
+	if !bytes.Equal(c.Body(), want) {
+		t.Errorf("reserialized body incorrect. want=%x. got=%x", want, c.Body())
+	}
+	if *c.Header().ChunkRoot() == staleRoot || !c.IsChunkRootFresh() {
+		t.Errorf("chunk root should be recalculated from the new body")
+	}
+	txs, err := DeserializeBlobToTx(BytesBodyReader(c.Body()))
+	if err != nil {
+		t.Fatalf("could not deserialize body: %v", err)
+	}
+	if len(*txs) != len(c.transactions) {
+		t.Fatalf("deserialized transaction count incorrect. want=%d. got=%d", len(c.transactions), len(*txs))
+	}
+	for i, tx := range *txs {
+		if tx.Hash() != c.transactions[i].Hash() {
+			t.Errorf("deserialized transaction %d does not match", i)
+		}
+	}
+	if _, index, ok := c.TransactionByHash(added[1].Hash()); !ok || index != len(c.transactions)-1 {
+		t.Errorf("transaction index should reflect the new body. want=%d. got=%d,%v", len(c.transactions)-1, index, ok)
+	}
+
+	body, root := c.Body(), *c.Header().ChunkRoot()
+	c.config = ShardConfig{CollationSizeLimit: 1}
+	if err := c.Reserialize(); err == nil {
+		t.Errorf("reserializing over the collation size limit should fail")
+	}
+	if !bytes.Equal(c.Body(), body) || *c.Header().ChunkRoot() != root {
+		t.Errorf("a failed reserialization should leave the collation untouched")
+	}
+}
+
 func TestSerialize_Deserialize(t *testing.T) {
 
-	header := NewCollationHeader(big.NewInt(1), nil, big.NewInt(1), nil, [32]byte{})
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
 	body := []byte{}
 	transactions := []*gethTypes.Transaction{
 		makeTxWithGasLimit(0),
@@ -53,7 +769,7 @@ func TestSerialize_Deserialize(t *testing.T) {
 		t.Errorf("Unable to Serialize transactions, %v", err)
 	}
 
-	deserializedTxs, err := DeserializeBlobToTx(results)
+	deserializedTxs, err := DeserializeBlobToTx(BytesBodyReader(results))
 
 	if err != nil {
 		t.Errorf("Unable to deserialize collation body, %v", err)
@@ -114,12 +830,763 @@ func TestSerialize_Deserialize(t *testing.T) {
 
 }
 
+func TestCollation_SerializeSizeLimit(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	transactions := makeRandomTransactions(2)
+
+	small := NewCollation(header, nil, transactions, WithConfig(ShardConfig{CollationSizeLimit: 64}))
+	if _, err := small.Serialize(); err == nil {
+		t.Errorf("serializing a body larger than a 64 byte collation size limit should fail")
+	}
+
+	production := NewCollation(header, nil, transactions, WithConfig(ShardConfig{}))
+	blob, err := production.Serialize()
+	if err != nil {
+		t.Fatalf("could not serialize collation with default size limit: %v", err)
+	}
+
+	expected, err := SerializeTxToBlob(transactions)
+	if err != nil {
+		t.Fatalf("could not serialize transactions: %v", err)
+	}
+	if !bytes.Equal(blob, expected) {
+		t.Errorf("collation serialization does not match serialized transactions")
+	}
+}
+
+func TestEncodeDecodeHeaders(t *testing.T) {
+	chunkRoot := common.BytesToHash([]byte{1})
+	headers := make([]*CollationHeader, 1000)
+	for i := range headers {
+		headers[i] = newTestCollationHeader(t, big.NewInt(int64(i%100)), &chunkRoot, big.NewInt(int64(i)))
+	}
+
+	for _, batch := range [][]*CollationHeader{headers, headers[:1], {}} {
+		encoded, err := EncodeHeaders(batch)
+		if err != nil {
+			t.Fatalf("could not encode %d headers: %v", len(batch), err)
+		}
+		decoded, err := DecodeHeaders(encoded)
+		if err != nil {
+			t.Fatalf("could not decode %d headers: %v", len(batch), err)
+		}
+		if len(decoded) != len(batch) {
+			t.Fatalf("decoded header count incorrect. want=%d. got=%d", len(batch), len(decoded))
+		}
+		for i := range batch {
+			if !decoded[i].Equal(batch[i]) {
+				t.Errorf("decoded header %d does not match. want=%+v. got=%+v", i, batch[i].data, decoded[i].data)
+			}
+		}
+	}
+
+	if _, err := EncodeHeaders([]*CollationHeader{headers[0], nil}); err == nil {
+		t.Errorf("encoding a nil header should fail")
+	}
+}
+
+func TestDecodeHeaders_SingleHeader(t *testing.T) {
+	chunkRoot := common.BytesToHash([]byte{1})
+	header := newTestCollationHeader(t, big.NewInt(1), &chunkRoot, big.NewInt(2))
+	encoded, err := header.EncodeRLP()
+	if err != nil {
+		t.Fatalf("could not encode header: %v", err)
+	}
+
+	decoded, err := DecodeHeaders(encoded)
+	if err != nil {
+		t.Fatalf("could not decode single header: %v", err)
+	}
+	if len(decoded) != 1 || !decoded[0].Equal(header) {
+		t.Errorf("decoded single header does not match. got=%v", decoded)
+	}
+
+	if _, err := DecodeHeaders(encoded[:len(encoded)-1]); err == nil {
+		t.Errorf("decoding a truncated header should fail")
+	}
+	if _, err := DecodeHeaders([]byte{0x01}); err == nil {
+		t.Errorf("decoding a non list payload should fail")
+	}
+}
+
+func TestCollation_TotalGas(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	c := NewCollation(header, nil, []*gethTypes.Transaction{
+		makeTxWithGasLimit(21000),
+		makeTxWithGasLimit(50000),
+	})
+	total, err := c.TotalGas()
+	if err != nil {
+		t.Fatalf("could not calculate total gas: %v", err)
+	}
+	if total != 71000 {
+		t.Errorf("total gas incorrect. want=%d. got=%d", 71000, total)
+	}
+
+	overflow := NewCollation(header, nil, []*gethTypes.Transaction{
+		makeTxWithGasLimit(math.MaxUint64),
+		makeTxWithGasLimit(1),
+	})
+	if _, err := overflow.TotalGas(); err == nil {
+		t.Errorf("total gas overflowing uint64 should fail")
+	}
+}
+
+func TestCollation_SerializeGasLimit(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	transactions := []*gethTypes.Transaction{
+		makeTxWithGasLimit(21000),
+		makeTxWithGasLimit(21000),
+	}
+
+	limited := NewCollation(header, nil, transactions, WithConfig(ShardConfig{GasLimit: 30000}))
+	if _, err := limited.Serialize(); err == nil {
+		t.Errorf("serializing transactions over the gas limit should fail")
+	}
+
+	exact := NewCollation(header, nil, transactions, WithConfig(ShardConfig{GasLimit: 42000}))
+	if _, err := exact.Serialize(); err != nil {
+		t.Errorf("could not serialize transactions within the gas limit: %v", err)
+	}
+
+	overDefault := NewCollation(header, nil, []*gethTypes.Transaction{makeTxWithGasLimit(params.DefaultCollationGasLimit() + 1)})
+	if _, err := overDefault.Serialize(); err == nil {
+		t.Errorf("serializing transactions over the default gas limit should fail")
+	}
+}
+
+func TestCollation_SerializeSkipEvm(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	transactions := []*gethTypes.Transaction{
+		makeTxWithGasLimit(0),
+		makeTxWithGasLimit(5),
+	}
+	c := NewCollation(header, nil, transactions)
+
+	if header.SkipEvmExecution() {
+		t.Fatalf("header should not skip EVM execution by default")
+	}
+	body, err := c.Serialize()
+	if err != nil {
+		t.Fatalf("could not serialize collation: %v", err)
+	}
+
+	header.WithSkipEvm(true)
+	if !header.SkipEvmExecution() {
+		t.Fatalf("header should skip EVM execution after WithSkipEvm(true)")
+	}
+	skipBody, err := c.Serialize()
+	if err != nil {
+		t.Fatalf("could not serialize collation: %v", err)
+	}
+	if bytes.Equal(body, skipBody) {
+		t.Errorf("serialized body should change when EVM execution is skipped")
+	}
+
+	// flagged blobs should still deserialize into one blob per transaction.
+	blobs, err := shardutil.Deserialize(skipBody)
+	if err != nil {
+		t.Fatalf("could not deserialize body: %v", err)
+	}
+	if len(blobs) != len(transactions) {
+		t.Errorf("number of blobs incorrect. want=%d. got=%d", len(transactions), len(blobs))
+	}
+
+	c.CalculateChunkRoot()
+	skipRoot := *header.ChunkRoot()
+	c.body = body
+	c.CalculateChunkRoot()
+	if skipRoot == *header.ChunkRoot() {
+		t.Errorf("chunk root should differ between bodies with and without skipped EVM execution")
+	}
+}
+
+func TestCollation_CompressDecompress(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	transactions := []*gethTypes.Transaction{
+		makeTxWithGasLimit(0),
+		makeTxWithGasLimit(5),
+		makeTxWithGasLimit(20),
+		makeTxWithGasLimit(100),
+	}
+	c := NewCollation(header, nil, transactions)
+
+	if header.Compressed() {
+		t.Fatalf("header should not be flagged as compressed before compression")
+	}
+	compressed, err := c.Compress()
+	if err != nil {
+		t.Fatalf("could not compress collation: %v", err)
+	}
+	if !header.Compressed() || header.BodyContentType() != ContentTypeCompressed {
+		t.Errorf("header should have the compressed content type after compression. got=%d", header.BodyContentType())
+	}
+
+	// receivers decode the body according to the header's content type.
+	received := NewCollation(header, compressed, nil)
+	if err := received.Deserialize(); err != nil {
+		t.Fatalf("could not deserialize compressed body: %v", err)
+	}
+	if len(received.Transactions()) != len(transactions) {
+		t.Errorf("deserialized transaction count incorrect. want=%d. got=%d", len(transactions), len(received.Transactions()))
+	}
+
+	serialized, err := c.Serialize()
+	if err != nil {
+		t.Fatalf("could not serialize collation: %v", err)
+	}
+	if len(compressed) >= len(serialized) {
+		t.Errorf("compressed body should be smaller than serialized body. compressed=%d. serialized=%d", len(compressed), len(serialized))
+	}
+
+	txs, err := DecompressAndDeserialize(compressed)
+	if err != nil {
+		t.Fatalf("could not decompress collation body: %v", err)
+	}
+	if len(*txs) != len(transactions) {
+		t.Fatalf("transaction count incorrect. want=%d. got=%d", len(transactions), len(*txs))
+	}
+	for i, tx := range *txs {
+		if tx.Hash() != transactions[i].Hash() {
+			t.Errorf("transaction %d differs after decompression. want=%v. got=%v", i, transactions[i].Hash().Hex(), tx.Hash().Hex())
+		}
+	}
+
+	if _, err := DecompressAndDeserialize([]byte{0xff, 0xff, 0xff}); err == nil {
+		t.Errorf("decompressing invalid data should fail")
+	}
+}
+
+func TestCollation_CompressSizeLimit(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	c := NewCollation(header, nil, makeRandomTransactions(2), WithConfig(ShardConfig{CollationSizeLimit: 64}))
+
+	if _, err := c.Compress(); err == nil {
+		t.Errorf("compression should fail if the uncompressed body exceeds the size limit")
+	}
+	if header.Compressed() {
+		t.Errorf("header should not be flagged as compressed if compression failed")
+	}
+}
+
+func TestCollation_CompressRawData(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	header.WithBodyContentType(ContentTypeRawData)
+	c := NewCollation(header, []byte{1, 2, 3}, nil)
+
+	if _, err := c.Compress(); err == nil {
+		t.Errorf("compressing a raw data body should fail")
+	}
+	if header.BodyContentType() != ContentTypeRawData {
+		t.Errorf("body content type should be unchanged. want=%d. got=%d", ContentTypeRawData, header.BodyContentType())
+	}
+}
+
+func TestCollationHeader_CompressedFlagMismatch(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	enc, err := header.EncodeRLP()
+	if err != nil {
+		t.Fatalf("could not encode header: %v", err)
+	}
+	var layout collationHeaderRLP
+	if err := rlp.DecodeBytes(enc, &layout); err != nil {
+		t.Fatalf("could not decode header layout: %v", err)
+	}
+	layout.Compressed = true
+	flipped, err := rlp.EncodeToBytes(&layout)
+	if err != nil {
+		t.Fatalf("could not encode header layout: %v", err)
+	}
+	if err := (&CollationHeader{}).Decode(flipped); err == nil {
+		t.Errorf("decoding a compressed flag that does not match the content type should fail")
+	}
+}
+
+func TestCollation_PeriodWindow(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(10))
+
+	tests := []struct {
+		window        int64
+		currentPeriod int64
+		expired       bool
+		premature     bool
+	}{
+		{window: 0, currentPeriod: 9, expired: false, premature: true},
+		{window: 0, currentPeriod: 10, expired: false, premature: false},
+		{window: 0, currentPeriod: 11, expired: true, premature: false},
+		{window: 3, currentPeriod: 12, expired: false, premature: false},
+		{window: 3, currentPeriod: 13, expired: true, premature: false},
+	}
+
+	for _, tt := range tests {
+		c := NewCollation(header, nil, nil, WithConfig(ShardConfig{PeriodWindow: tt.window}))
+		current := big.NewInt(tt.currentPeriod)
+		if c.IsExpired(current) != tt.expired {
+			t.Errorf("IsExpired(%d) with window %d incorrect. want=%v. got=%v", tt.currentPeriod, tt.window, tt.expired, c.IsExpired(current))
+		}
+		if c.IsPrematurely(current) != tt.premature {
+			t.Errorf("IsPrematurely(%d) with window %d incorrect. want=%v. got=%v", tt.currentPeriod, tt.window, tt.premature, c.IsPrematurely(current))
+		}
+	}
+}
+
 func makeTxWithGasLimit(gl uint64) *gethTypes.Transaction {
 	return gethTypes.NewTransaction(0 /*nonce*/, common.HexToAddress("0x0") /*to*/, nil /*amount*/, gl, nil /*gasPrice*/, nil /*data*/)
 }
 
+func TestCollation_AccumulatedFees(t *testing.T) {
+	makeTx := func(gas uint64, gasPrice *big.Int) *gethTypes.Transaction {
+		return gethTypes.NewTransaction(0 /*nonce*/, common.HexToAddress("0x0") /*to*/, nil /*amount*/, gas, gasPrice, nil /*data*/)
+	}
+	maxPrice, _ := new(big.Int).SetString("ffffffffffffffffffffffffffffffff", 16)
+	overflowing := new(big.Int).Mul(maxPrice, new(big.Int).SetUint64(math.MaxUint64))
+
+	tests := []struct {
+		transactions []*gethTypes.Transaction
+		fees         *big.Int
+	}{
+		{transactions: nil, fees: big.NewInt(0)},
+		{transactions: []*gethTypes.Transaction{makeTx(21000, big.NewInt(2))}, fees: big.NewInt(42000)},
+		{transactions: []*gethTypes.Transaction{makeTx(21000, big.NewInt(2)), makeTx(100, big.NewInt(3))}, fees: big.NewInt(42300)},
+		{transactions: []*gethTypes.Transaction{makeTx(21000, nil), makeTx(100, big.NewInt(3))}, fees: big.NewInt(300)},
+		{transactions: []*gethTypes.Transaction{makeTx(math.MaxUint64, maxPrice)}, fees: overflowing},
+	}
+
+	for _, tt := range tests {
+		header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+		c := NewCollation(header, nil, tt.transactions)
+		fees, err := c.AccumulatedFees()
+		if err != nil {
+			t.Fatalf("could not accumulate fees: %v", err)
+		}
+		if fees.Cmp(tt.fees) != 0 {
+			t.Errorf("accumulated fees incorrect. want=%v. got=%v", tt.fees, fees)
+		}
+		if c.FeeCapExceeded(tt.fees) {
+			t.Errorf("fees equal to the cap should not exceed it")
+		}
+		if !c.FeeCapExceeded(new(big.Int).Sub(tt.fees, big.NewInt(1))) {
+			t.Errorf("fees above the cap should exceed it")
+		}
+	}
+
+	c := NewCollation(newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1)), nil, []*gethTypes.Transaction{nil})
+	if _, err := c.AccumulatedFees(); err == nil {
+		t.Errorf("accumulating fees of a nil transaction should fail")
+	}
+	if !c.FeeCapExceeded(big.NewInt(0)) {
+		t.Errorf("collations without computable fees should exceed the cap")
+	}
+}
+
+func TestCollation_WithTransactions(t *testing.T) {
+	c := makeStoredCollation(t, 1, 5)
+	header, body, txs := *c.Header(), append([]byte{}, c.Body()...), c.Transactions()
+	headerHash := c.Header().SignedHash()
+
+	updatedTxs := makeRandomTransactions(5)
+	updated, err := c.WithTransactions(updatedTxs)
+	if err != nil {
+		t.Fatalf("could not replace transactions: %v", err)
+	}
+	updatedTxs[0] = nil
+
+	if c.Header().SignedHash() != headerHash || !c.Header().Equal(&header) {
+		t.Errorf("source header should be unchanged")
+	}
+	if !bytes.Equal(c.Body(), body) || !reflect.DeepEqual(c.Transactions(), txs) {
+		t.Errorf("source body and transactions should be unchanged")
+	}
+	if !c.IsChunkRootFresh() {
+		t.Errorf("source chunk root should still match its body")
+	}
+
+	if len(updated.Transactions()) != 5 || updated.Transactions()[0] == nil {
+		t.Errorf("new collation should hold its own copy of the transactions")
+	}
+	if !updated.IsChunkRootFresh() {
+		t.Errorf("new collation chunk root should match its body")
+	}
+	want, err := SerializeTxToBlob(updated.Transactions())
+	if err != nil {
+		t.Fatalf("could not serialize transactions: %v", err)
+	}
+	if !bytes.Equal(updated.Body(), want) {
+		t.Errorf("new collation body not serialized from its transactions")
+	}
+	if updated.Header().ShardID().Cmp(c.Header().ShardID()) != 0 || updated.Header().Period().Cmp(c.Header().Period()) != 0 {
+		t.Errorf("new collation should keep the shardID and period")
+	}
+	if updated.Header().Sig() != nil {
+		t.Errorf("new collation header should be unsigned")
+	}
+	if err := updated.Header().AddProposer(&common.Address{0x01}); err != nil {
+		t.Fatalf("could not add proposer: %v", err)
+	}
+	if len(c.Header().ProposerAddresses()) != 1 {
+		t.Errorf("adding a proposer to the new collation should not change the source")
+	}
+
+	small := NewCollation(c.Header(), nil, nil, WithConfig(ShardConfig{CollationSizeLimit: 64}))
+	if _, err := small.WithTransactions(makeRandomTransactions(5)); !errors.Is(err, ErrCollationTooLarge) {
+		t.Errorf("transactions beyond the size limit should be rejected. want=%v. got=%v", ErrCollationTooLarge, err)
+	}
+}
+
+func TestCollation_Filter(t *testing.T) {
+	c := makeStoredCollation(t, 1, 5)
+	txs := makeRandomTransactions(6)
+	c, err := c.WithTransactions(txs)
+	if err != nil {
+		t.Fatalf("could not replace transactions: %v", err)
+	}
+	even := make(map[common.Hash]bool)
+	for i, tx := range txs {
+		even[tx.Hash()] = i%2 == 0
+	}
+
+	tests := []struct {
+		name string
+		keep func(*gethTypes.Transaction) bool
+		want []*gethTypes.Transaction
+	}{
+		{
+			name: "none",
+			keep: func(*gethTypes.Transaction) bool { return false },
+			want: nil,
+		},
+		{
+			name: "all",
+			keep: func(*gethTypes.Transaction) bool { return true },
+			want: txs,
+		},
+		{
+			name: "half",
+			keep: func(tx *gethTypes.Transaction) bool { return even[tx.Hash()] },
+			want: []*gethTypes.Transaction{txs[0], txs[2], txs[4]},
+		},
+	}
+
+	for _, tt := range tests {
+		filtered, err := c.Filter(tt.keep)
+		if err != nil {
+			t.Fatalf("%s: could not filter transactions: %v", tt.name, err)
+		}
+		if len(filtered.Transactions()) != len(tt.want) {
+			t.Fatalf("%s: number of transactions incorrect. want=%d. got=%d", tt.name, len(tt.want), len(filtered.Transactions()))
+		}
+		for i, tx := range filtered.Transactions() {
+			if tx.Hash() != tt.want[i].Hash() {
+				t.Errorf("%s: transaction %d incorrect. want=%x. got=%x", tt.name, i, tt.want[i].Hash(), tx.Hash())
+			}
+		}
+		body, err := SerializeTxToBlob(tt.want)
+		if err != nil {
+			t.Fatalf("%s: could not serialize transactions: %v", tt.name, err)
+		}
+		if want := chunkRootFromBody(body); filtered.Header().ChunkRoot() == nil || *filtered.Header().ChunkRoot() != want {
+			t.Errorf("%s: chunk root not recalculated. want=%x. got=%v", tt.name, want, filtered.Header().ChunkRoot())
+		}
+	}
+	if len(c.Transactions()) != len(txs) {
+		t.Errorf("source collation should keep its transactions. want=%d. got=%d", len(txs), len(c.Transactions()))
+	}
+}
+
+func TestCollation_Deserialize(t *testing.T) {
+	txs := makeRandomTransactions(3)
+	serialized, err := SerializeTxToBlob(txs)
+	if err != nil {
+		t.Fatalf("could not serialize transactions: %v", err)
+	}
+	compressed, err := NewCollation(newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1)), nil, txs).Compress()
+	if err != nil {
+		t.Fatalf("could not compress transactions: %v", err)
+	}
+
+	tests := []struct {
+		contentType uint8
+		body        []byte
+		numTxs      int
+	}{
+		{contentType: ContentTypeTransactions, body: serialized, numTxs: 3},
+		{contentType: ContentTypeCompressed, body: compressed, numTxs: 3},
+		{contentType: ContentTypeRawData, body: []byte{1, 2, 3}, numTxs: 0},
+	}
+	for _, tt := range tests {
+		header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+		header.WithBodyContentType(tt.contentType)
+		c := NewCollation(header, tt.body, nil)
+		if err := c.Deserialize(); err != nil {
+			t.Fatalf("could not deserialize body of content type %d: %v", tt.contentType, err)
+		}
+		if len(c.Transactions()) != tt.numTxs {
+			t.Errorf("number of transactions of content type %d incorrect. want=%d. got=%d", tt.contentType, tt.numTxs, len(c.Transactions()))
+		}
+		for i := range c.Transactions() {
+			if c.Transactions()[i].Hash() != txs[i].Hash() {
+				t.Errorf("transaction %d of content type %d incorrect", i, tt.contentType)
+			}
+		}
+	}
+
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	header.WithBodyContentType(3)
+	if err := NewCollation(header, serialized, nil).Deserialize(); err == nil {
+		t.Errorf("deserializing a body of unknown content type should fail")
+	}
+	if err := header.Validate(); err == nil {
+		t.Errorf("a header with an unknown body content type should be invalid")
+	}
+}
+
+func TestCollationHeader_BodyContentTypeRLP(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	legacy, err := header.EncodeRLP()
+	if err != nil {
+		t.Fatalf("could not encode header: %v", err)
+	}
+	unsignedHash := header.UnsignedHash()
+
+	header.WithBodyContentType(ContentTypeRawData)
+	encoded, err := header.EncodeRLP()
+	if err != nil {
+		t.Fatalf("could not encode header: %v", err)
+	}
+	if bytes.Equal(encoded, legacy) {
+		t.Errorf("non-transaction content types should be encoded")
+	}
+	if header.UnsignedHash() == unsignedHash {
+		t.Errorf("the unsigned hash should cover the body content type")
+	}
+
+	decoded := &CollationHeader{}
+	if err := rlp.DecodeBytes(encoded, decoded); err != nil {
+		t.Fatalf("could not decode header: %v", err)
+	}
+	if decoded.BodyContentType() != ContentTypeRawData || !decoded.Equal(header) {
+		t.Errorf("decoded header does not match. want=%+v. got=%+v", header.data, decoded.data)
+	}
+
+	// Headers with unknown trailing fields are rejected.
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(encoded, &fields); err != nil {
+		t.Fatalf("could not split header: %v", err)
+	}
+	extended, err := rlp.EncodeToBytes(append(fields, rlp.RawValue{0x01}))
+	if err != nil {
+		t.Fatalf("could not encode header: %v", err)
+	}
+	if err := rlp.DecodeBytes(extended, &CollationHeader{}); err == nil {
+		t.Errorf("decoding a header with unknown trailing fields should fail")
+	}
+}
+
+func TestCollation_DeduplicateTransactions(t *testing.T) {
+	txs := makeRandomTransactions(3)
+	withDuplicates := []*gethTypes.Transaction{txs[0], txs[1], txs[0], txs[2], txs[1], txs[0]}
+
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	c := NewCollation(header, nil, withDuplicates)
+	if removed := c.DeduplicateTransactions(); removed != 3 {
+		t.Errorf("removed duplicates incorrect. want=%d. got=%d", 3, removed)
+	}
+
+	seen := make(map[common.Hash]int)
+	for _, tx := range c.Transactions() {
+		seen[tx.Hash()]++
+	}
+	for i, tx := range txs {
+		if seen[tx.Hash()] != 1 {
+			t.Errorf("transaction %d should appear exactly once. got=%d", i, seen[tx.Hash()])
+		}
+		if c.Transactions()[i] != tx {
+			t.Errorf("transaction %d out of order after deduplication", i)
+		}
+	}
+
+	blobs, err := shardutil.Deserialize(c.Body())
+	if err != nil {
+		t.Fatalf("could not deserialize body: %v", err)
+	}
+	if len(blobs) != len(txs) {
+		t.Errorf("body not reserialized. want=%d blobs. got=%d", len(txs), len(blobs))
+	}
+	if !c.IsChunkRootFresh() {
+		t.Errorf("chunk root should be recalculated after deduplication")
+	}
+
+	if removed := c.DeduplicateTransactions(); removed != 0 {
+		t.Errorf("deduplicating a collation twice should remove nothing. got=%d", removed)
+	}
+}
+
+func TestCollation_ReplaceTx(t *testing.T) {
+	txs := []*gethTypes.Transaction{
+		gethTypes.NewTransaction(0, common.HexToAddress("0x1"), nil, 21000, big.NewInt(10), nil),
+		gethTypes.NewTransaction(1, common.HexToAddress("0x1"), nil, 21000, big.NewInt(10), nil),
+	}
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	c := NewCollation(header, nil, append([]*gethTypes.Transaction(nil), txs...))
+	if err := c.Reserialize(); err != nil {
+		t.Fatalf("could not serialize collation: %v", err)
+	}
+	oldRoot := *c.Header().ChunkRoot()
+
+	bumped := gethTypes.NewTransaction(1, common.HexToAddress("0x1"), nil, 21000, big.NewInt(11), nil)
+	if err := c.ReplaceTx(txs[1].Hash(), bumped); err != nil {
+		t.Fatalf("could not replace transaction: %v", err)
+	}
+	if got := c.Transactions()[1]; got != bumped {
+		t.Errorf("transaction not replaced. want=%s. got=%s", bumped.Hash().Hex(), got.Hash().Hex())
+	}
+	if got := c.Transactions()[0]; got != txs[0] {
+		t.Errorf("other transactions should be kept. want=%s. got=%s", txs[0].Hash().Hex(), got.Hash().Hex())
+	}
+	if _, _, ok := c.TransactionByHash(txs[1].Hash()); ok {
+		t.Errorf("replaced transaction should no longer be found")
+	}
+	if *c.Header().ChunkRoot() == oldRoot {
+		t.Errorf("chunk root should change with the replaced transaction")
+	}
+	if !c.IsChunkRootFresh() {
+		t.Errorf("chunk root should be recalculated after replacing a transaction")
+	}
+	blobs, err := shardutil.Deserialize(c.Body())
+	if err != nil {
+		t.Fatalf("could not deserialize body: %v", err)
+	}
+	if len(blobs) != len(txs) {
+		t.Errorf("body not reserialized. want=%d blobs. got=%d", len(txs), len(blobs))
+	}
+
+	tests := []struct {
+		name    string
+		oldHash common.Hash
+		newTx   *gethTypes.Transaction
+		want    error
+	}{
+		{
+			name:    "unknown transaction",
+			oldHash: common.HexToHash("0xff"),
+			newTx:   gethTypes.NewTransaction(2, common.HexToAddress("0x1"), nil, 21000, big.NewInt(20), nil),
+			want:    ErrTxNotFound,
+		},
+		{
+			name:    "same gas price",
+			oldHash: txs[0].Hash(),
+			newTx:   gethTypes.NewTransaction(0, common.HexToAddress("0x2"), nil, 21000, big.NewInt(10), nil),
+			want:    ErrGasPriceTooLow,
+		},
+		{
+			name:    "lower gas price",
+			oldHash: txs[0].Hash(),
+			newTx:   gethTypes.NewTransaction(0, common.HexToAddress("0x2"), nil, 21000, big.NewInt(9), nil),
+			want:    ErrGasPriceTooLow,
+		},
+	}
+	for _, tt := range tests {
+		root := *c.Header().ChunkRoot()
+		if err := c.ReplaceTx(tt.oldHash, tt.newTx); !errors.Is(err, tt.want) {
+			t.Errorf("%s: unexpected error. want=%v. got=%v", tt.name, tt.want, err)
+		}
+		if *c.Header().ChunkRoot() != root || c.Transactions()[0] != txs[0] {
+			t.Errorf("%s: failed replacement should leave the collation untouched", tt.name)
+		}
+	}
+	if err := c.ReplaceTx(txs[0].Hash(), nil); err == nil {
+		t.Errorf("replacing a transaction by nil should fail")
+	}
+}
+
+func TestCollation_Size(t *testing.T) {
+	tests := []struct {
+		numTxs    int
+		serialize bool
+	}{
+		{numTxs: 1},
+		{numTxs: 10},
+		{numTxs: 100},
+		{numTxs: 100, serialize: true},
+	}
+
+	for _, tt := range tests {
+		header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+		c := NewCollation(header, nil, makeRandomTransactions(tt.numTxs))
+		if tt.serialize {
+			if err := c.Reserialize(); err != nil {
+				t.Fatalf("could not serialize collation: %v", err)
+			}
+		}
+
+		actual, err := c.ActualSize()
+		if err != nil {
+			t.Fatalf("could not compute actual size: %v", err)
+		}
+		if actual > int(params.DefaultCollationSizeLimit()) {
+			t.Errorf("actual size %d exceeds the collation size limit %d", actual, params.DefaultCollationSizeLimit())
+		}
+		if tt.serialize && actual != len(c.Body()) {
+			t.Errorf("actual size incorrect. want=%d. got=%d", len(c.Body()), actual)
+		}
+
+		estimated := c.EstimatedSize()
+		if estimated > 2*actual || actual > 2*estimated {
+			t.Errorf("estimated size not within a factor of 2 of the actual size for %d txs. actual=%d. estimated=%d", tt.numTxs, actual, estimated)
+		}
+	}
+}
+
+func TestCollation_Pad(t *testing.T) {
+	tests := []struct {
+		body      []byte
+		paddedLen int
+	}{
+		{body: []byte{}, paddedLen: 0},
+		{body: []byte{1}, paddedLen: 32},
+		{body: bytes.Repeat([]byte{1}, 31), paddedLen: 32},
+		{body: bytes.Repeat([]byte{1}, 32), paddedLen: 32},
+		{body: bytes.Repeat([]byte{1}, 33), paddedLen: 64},
+	}
+
+	for _, tt := range tests {
+		header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+		c := NewCollation(header, tt.body, nil)
+		c.CalculateChunkRoot()
+		unpaddedRoot := *header.ChunkRoot()
+
+		if err := c.Pad(); err != nil {
+			t.Fatalf("could not pad body: %v", err)
+		}
+		if len(c.Body()) != tt.paddedLen {
+			t.Errorf("padded body length incorrect. want=%d. got=%d", tt.paddedLen, len(c.Body()))
+		}
+		if *header.ChunkRoot() != unpaddedRoot {
+			t.Errorf("padding should not change the chunk root. want=%x. got=%x", unpaddedRoot, *header.ChunkRoot())
+		}
+		if !c.IsChunkRootFresh() {
+			t.Errorf("chunk root should be fresh after padding")
+		}
+		if !bytes.Equal(c.UnpadBody(), tt.body) {
+			t.Errorf("unpadded body incorrect. want=%x. got=%x", tt.body, c.UnpadBody())
+		}
+	}
+}
+
+func TestCollation_PadSizeLimit(t *testing.T) {
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	body := make([]byte, 40)
+	c := NewCollation(header, body, nil, WithConfig(ShardConfig{CollationSizeLimit: 48}))
+	if err := c.Pad(); !errors.Is(err, ErrCollationTooLarge) {
+		t.Errorf("padding beyond the size limit should fail. want=%v. got=%v", ErrCollationTooLarge, err)
+	}
+	if len(c.Body()) != len(body) {
+		t.Errorf("body should be untouched. want=%d. got=%d", len(body), len(c.Body()))
+	}
+}
+
 func Test_CalculatePOC(t *testing.T) {
-	header := NewCollationHeader(big.NewInt(1), nil, big.NewInt(1), nil, [32]byte{})
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
 	body := []byte{0x56, 0xff}
 	transactions := []*gethTypes.Transaction{
 		makeTxWithGasLimit(0),
@@ -164,7 +1631,7 @@ func runSerializeRoundtrip(b *testing.B, numTransactions int) {
 			b.Errorf("SerializeTxToBlob failed: %v", err)
 		}
 
-		_, err = DeserializeBlobToTx(blob)
+		_, err = DeserializeBlobToTx(BytesBodyReader(blob))
 		if err != nil {
 			b.Errorf("DeserializeBlobToTx failed: %v", err)
 		}
@@ -187,7 +1654,7 @@ func runSerializeBenchmark(b *testing.B, numTransactions int) {
 // Benchmarks just the process of converting an RLP encoded set of transactions into serialized data
 func runSerializeNoRLPBenchmark(b *testing.B, numTransactions int) {
 	txs := makeRandomTransactions(numTransactions)
-	blobs, err := convertTxToRawBlob(txs)
+	blobs, err := convertTxToRawBlob(txs, false)
 	if err != nil {
 		b.Errorf("SerializeTxToRawBlock failed: %v", err)
 	}
@@ -213,7 +1680,7 @@ func runDeserializeBenchmark(b *testing.B, numTransactions int) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		_, err := DeserializeBlobToTx(blob)
+		_, err := DeserializeBlobToTx(BytesBodyReader(blob))
 		if err != nil {
 			b.Errorf("DeserializeBlobToTx failed: %v", err)
 		}
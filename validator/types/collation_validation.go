@@ -0,0 +1,196 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"runtime"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrCollationTooLarge is returned when a collation body exceeds the collation size limit.
+	ErrCollationTooLarge = errors.New("collation too large")
+	// ErrInvalidShardID is returned when a shardID is negative or exceeds the shard count.
+	ErrInvalidShardID = errors.New("invalid shardID")
+	// ErrInvalidPeriod is returned when a period is negative, or is not a period
+	// in which the collation can be included.
+	ErrInvalidPeriod = errors.New("invalid period")
+	// ErrInvalidProposerSignature is returned when a proposer signature is
+	// missing, malformed or not signed by the proposer.
+	ErrInvalidProposerSignature = errors.New("invalid proposer signature")
+	// ErrChunkRootMismatch is returned when a header's chunk root does not match
+	// the collation body.
+	ErrChunkRootMismatch = errors.New("chunk root mismatch")
+	// ErrEmptyBody is returned when a collation body is required but empty.
+	ErrEmptyBody = errors.New("empty collation body")
+	// ErrPeriodGap is returned when a range of collations skips a period.
+	ErrPeriodGap = errors.New("gap between collation periods")
+	// ErrPeriodDuplicate is returned when a range of collations holds more than
+	// one collation for a period.
+	ErrPeriodDuplicate = errors.New("duplicate collation period")
+	// ErrShardMismatch is returned when a range of collations mixes shards.
+	ErrShardMismatch = errors.New("collations of different shards")
+	// ErrTxNotFound is returned when a transaction is not part of a collation.
+	ErrTxNotFound = errors.New("transaction not found")
+	// ErrGasPriceTooLow is returned when a replacement transaction does not pay
+	// a higher gas price than the transaction it replaces.
+	ErrGasPriceTooLow = errors.New("gas price too low")
+)
+
+// ValidateShardID checks that the shardID is within [0, shardCount).
+func ValidateShardID(id *big.Int, shardCount int) error {
+	if id == nil || id.Sign() < 0 {
+		return fmt.Errorf("shardID %v must be non-negative: %w", id, ErrInvalidShardID)
+	}
+	if id.Cmp(big.NewInt(int64(shardCount))) >= 0 {
+		return fmt.Errorf("shardID %v exceeds the shard count %d: %w", id, shardCount, ErrInvalidShardID)
+	}
+	return nil
+}
+
+// ValidateHeader checks a collation header without its body, so that peers can
+// discard invalid collations before fetching their bodies, for instance when
+// the header of an announced collation is received. It checks the shardID
+// against the config's shard count, that the period's window is open at
+// currentPeriod, that the chunk root is set, and that the proposer addresses
+// are valid and signed with signatures of the right length.
+func ValidateHeader(h *CollationHeader, shardConfig ShardConfig, currentPeriod *big.Int) error {
+	if h == nil {
+		return errors.New("no collation header provided")
+	}
+	if err := h.validate(int64(shardConfig.shardCount())); err != nil {
+		return err
+	}
+	if currentPeriod.Cmp(h.Period()) < 0 {
+		return fmt.Errorf("collation period %v has not started by period %v: %w", h.Period(), currentPeriod, ErrInvalidPeriod)
+	}
+	if isPeriodExpired(h.Period(), shardConfig.periodWindow(), currentPeriod) {
+		return fmt.Errorf("collation period %v has expired by period %v: %w", h.Period(), currentPeriod, ErrInvalidPeriod)
+	}
+	if h.ChunkRoot() == nil || *h.ChunkRoot() == (common.Hash{}) {
+		return errors.New("collation header has no chunk root")
+	}
+	if !h.isSigned() {
+		return fmt.Errorf("collation header is not signed: %w", ErrInvalidProposerSignature)
+	}
+	return nil
+}
+
+// ValidateChain checks that a range of collations, such as one downloaded while
+// syncing, belongs to a single shard and holds a collation for each consecutive
+// period in ascending order, so that every collation's period is the predecessor
+// of the next one's. The error names the index of the offending collation and
+// wraps ErrShardMismatch, ErrPeriodDuplicate or ErrPeriodGap.
+func ValidateChain(collations []*Collation) error {
+	for i, c := range collations {
+		if c == nil || c.Header() == nil || c.Header().ShardID() == nil || c.Header().Period() == nil {
+			return fmt.Errorf("collation %d has no shardID or period", i)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := collations[i-1].Header()
+		if c.Header().ShardID().Cmp(prev.ShardID()) != 0 {
+			return fmt.Errorf("collation %d of shard %v follows a collation of shard %v: %w", i, c.Header().ShardID(), prev.ShardID(), ErrShardMismatch)
+		}
+		switch want := new(big.Int).Add(prev.Period(), big.NewInt(1)); c.Header().Period().Cmp(want) {
+		case 0:
+		case 1:
+			return fmt.Errorf("collation %d has period %v, want %v: %w", i, c.Header().Period(), want, ErrPeriodGap)
+		default:
+			if c.Header().Period().Cmp(prev.Period()) == 0 {
+				return fmt.Errorf("collation %d repeats period %v: %w", i, c.Header().Period(), ErrPeriodDuplicate)
+			}
+			return fmt.Errorf("collation %d has period %v, want %v: %w", i, c.Header().Period(), want, ErrPeriodGap)
+		}
+	}
+	return nil
+}
+
+// validationOptions holds the settings used by ValidateCollations.
+type validationOptions struct {
+	workers int
+}
+
+// ValidationOption configures how ValidateCollations validates collations.
+type ValidationOption func(o *validationOptions)
+
+// ValidationWorkers sets the number of collations validated in parallel.
+// Non-positive values fall back to the number of CPUs.
+func ValidationWorkers(workers int) ValidationOption {
+	return func(o *validationOptions) {
+		o.workers = workers
+	}
+}
+
+// ValidateCollations validates the header, chunk root, body size and period of
+// each collation using a pool of workers. The returned slice has an error for
+// every invalid collation at the collation's index, and nil for valid ones.
+func ValidateCollations(collations []*Collation, shardCount int, currentPeriod *big.Int, opts ...ValidationOption) []error {
+	options := &validationOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.workers <= 0 {
+		options.workers = runtime.NumCPU()
+	}
+
+	errs := make([]error, len(collations))
+	indices := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < options.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				errs[i] = validateCollation(collations[i], int64(shardCount), currentPeriod)
+			}
+		}()
+	}
+	for i := range collations {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return errs
+}
+
+// validateCollation runs every check of ValidateCollations on a single collation.
+func validateCollation(c *Collation, shardCount int64, currentPeriod *big.Int) error {
+	if err := validateCollationContents(c, shardCount); err != nil {
+		return err
+	}
+	if c.IsPrematurely(currentPeriod) {
+		return fmt.Errorf("collation period %v has not started by period %v: %w", c.Header().Period(), currentPeriod, ErrInvalidPeriod)
+	}
+	if c.IsExpired(currentPeriod) {
+		return fmt.Errorf("collation period %v has expired by period %v: %w", c.Header().Period(), currentPeriod, ErrInvalidPeriod)
+	}
+	return nil
+}
+
+// validateCollationContents checks a collation's header, signature, chunk root
+// and body size, independently of the current period.
+func validateCollationContents(c *Collation, shardCount int64) error {
+	if c == nil || c.Header() == nil {
+		return fmt.Errorf("collation has no header")
+	}
+	if err := c.Header().validate(shardCount); err != nil {
+		return fmt.Errorf("invalid collation header: %w", err)
+	}
+	if !c.Header().isSigned() {
+		return fmt.Errorf("collation header is not signed: %w", ErrInvalidProposerSignature)
+	}
+	if !c.IsChunkRootFresh() {
+		return fmt.Errorf("chunk root does not match the collation body: %w", ErrChunkRootMismatch)
+	}
+	if csl := c.config.collationSizeLimit(); int64(len(c.Body())) > csl {
+		return fmt.Errorf("the collation body size %d exceeded the collation size limit %d: %w", len(c.Body()), csl, ErrCollationTooLarge)
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	sharedDB "github.com/prysmaticlabs/prysm/shared/database"
+)
+
+func TestValidateCollations(t *testing.T) {
+	valid := makeStoredCollation(t, 1, 10)
+
+	staleRoot := makeStoredCollation(t, 1, 10)
+	staleRoot.body = append([]byte{}, staleRoot.body...)
+	staleRoot.body[1] ^= 0xff
+
+	outOfRange := makeStoredCollation(t, 1, 10)
+	outOfRange.header.data.ShardID = big.NewInt(5)
+
+	tooLarge := makeStoredCollation(t, 1, 10)
+	tooLarge.config = ShardConfig{CollationSizeLimit: 32}
+
+	expired := makeStoredCollation(t, 1, 9)
+	premature := makeStoredCollation(t, 1, 11)
+
+	unsigned := makeStoredCollation(t, 1, 10)
+	unsigned.header.data.ProposerSignatures = nil
+
+	collations := []*Collation{valid, staleRoot, outOfRange, tooLarge, expired, premature, unsigned, nil}
+
+	for _, workers := range []int{0, 1, 3, 16} {
+		errs := ValidateCollations(collations, 5, big.NewInt(10), ValidationWorkers(workers))
+		if len(errs) != len(collations) {
+			t.Fatalf("error count incorrect. want=%d. got=%d", len(collations), len(errs))
+		}
+		if errs[0] != nil {
+			t.Errorf("valid collation failed validation with %d workers: %v", workers, errs[0])
+		}
+		for i, err := range errs[1:] {
+			if err == nil {
+				t.Errorf("invalid collation %d passed validation with %d workers", i+1, workers)
+			}
+		}
+	}
+
+	if errs := ValidateCollations(nil, 5, big.NewInt(10)); len(errs) != 0 {
+		t.Errorf("validating no collations should return no errors")
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	staleRoot := makeStoredCollation(t, 1, 10)
+	staleRoot.body = append([]byte{}, staleRoot.body...)
+	staleRoot.body[1] ^= 0xff
+
+	negativeShard := makeStoredCollation(t, 1, 10)
+	negativeShard.header.data.ShardID = big.NewInt(-1)
+	outOfRange := makeStoredCollation(t, 1, 10)
+	outOfRange.header.data.ShardID = big.NewInt(5)
+
+	negativePeriod := makeStoredCollation(t, 1, 10)
+	negativePeriod.header.data.Period = big.NewInt(-1)
+
+	unsigned := makeStoredCollation(t, 1, 10)
+	unsigned.header.data.ProposerSignatures = nil
+	shortSig := makeStoredCollation(t, 1, 10)
+	shortSig.header.data.ProposerSignatures = [][]byte{make([]byte, 10)}
+
+	tooLarge := makeStoredCollation(t, 1, 10)
+	tooLarge.config = ShardConfig{CollationSizeLimit: 32}
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	otherKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	signed := makeStoredCollation(t, 1, 10)
+	proposer := crypto.PubkeyToAddress(key.PublicKey)
+	signed.header.data.ProposerAddresses = []*common.Address{&proposer}
+	signed.header.data.ProposerSignatures = nil
+	if err := signed.header.Sign(key); err != nil {
+		t.Fatalf("could not sign header: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"stale chunk root", validateCollation(staleRoot, 5, big.NewInt(10)), ErrChunkRootMismatch},
+		{"negative shardID", validateCollation(negativeShard, 5, big.NewInt(10)), ErrInvalidShardID},
+		{"shardID out of range", validateCollation(outOfRange, 5, big.NewInt(10)), ErrInvalidShardID},
+		{"negative period", negativePeriod.Header().validate(5), ErrInvalidPeriod},
+		{"expired period", validateCollation(makeStoredCollation(t, 1, 9), 5, big.NewInt(10)), ErrInvalidPeriod},
+		{"premature period", validateCollation(makeStoredCollation(t, 1, 11), 5, big.NewInt(10)), ErrInvalidPeriod},
+		{"unsigned header", validateCollation(unsigned, 5, big.NewInt(10)), ErrInvalidProposerSignature},
+		{"short signature", validateCollation(shortSig, 5, big.NewInt(10)), ErrInvalidProposerSignature},
+		{"setting a short signature", unsigned.Header().SetProposerSignature(make([]byte, 10)), ErrInvalidProposerSignature},
+		{"signature of another key", signed.Header().VerifyProposerSignature(&otherKey.PublicKey), ErrInvalidProposerSignature},
+		{"body too large", validateCollation(tooLarge, 5, big.NewInt(10)), ErrCollationTooLarge},
+		{"serialized body too large", func() error { _, err := tooLarge.Serialize(); return err }(), ErrCollationTooLarge},
+		{"empty body", NewShard(big.NewInt(1), sharedDB.NewKVStore()).SaveBody(nil), ErrEmptyBody},
+	}
+	sentinels := []error{ErrCollationTooLarge, ErrInvalidShardID, ErrInvalidPeriod, ErrInvalidProposerSignature, ErrChunkRootMismatch, ErrEmptyBody}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) {
+			t.Errorf("%s: error does not wrap the sentinel. want=%v. got=%v", tt.name, tt.want, tt.err)
+		}
+		for _, sentinel := range sentinels {
+			if sentinel != tt.want && errors.Is(tt.err, sentinel) {
+				t.Errorf("%s: error should not wrap %v", tt.name, sentinel)
+			}
+		}
+	}
+}
+
+func TestValidateShardID(t *testing.T) {
+	tests := []struct {
+		id      *big.Int
+		wantErr bool
+	}{
+		{id: big.NewInt(0), wantErr: false},
+		{id: big.NewInt(9), wantErr: false},
+		{id: big.NewInt(10), wantErr: true},
+		{id: big.NewInt(-1), wantErr: true},
+		{id: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := ValidateShardID(tt.id, 10)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("shardID %v: unexpected validation result. want error=%v. got=%v", tt.id, tt.wantErr, err)
+		}
+		if tt.wantErr && !errors.Is(err, ErrInvalidShardID) {
+			t.Errorf("shardID %v: error does not wrap the sentinel. want=%v. got=%v", tt.id, ErrInvalidShardID, err)
+		}
+	}
+}
+
+func TestNewCollationHeader_ShardCount(t *testing.T) {
+	cfg := ShardConfig{ShardCount: 4}
+	for id, wantErr := range map[int64]bool{0: false, 3: false, 4: true, -1: true} {
+		_, err := NewCollationHeader(big.NewInt(id), nil, big.NewInt(1), &testProposerAddress, nil, false, cfg)
+		if (err != nil) != wantErr {
+			t.Errorf("shardID %d: unexpected header creation result. want error=%v. got=%v", id, wantErr, err)
+		}
+	}
+	if _, err := NewCollationHeader(big.NewInt(99), nil, big.NewInt(1), &testProposerAddress, nil, false); err != nil {
+		t.Errorf("shardID 99 should be valid with the default shard count: %v", err)
+	}
+	if _, err := NewCollationHeader(big.NewInt(100), nil, big.NewInt(1), &testProposerAddress, nil, false); !errors.Is(err, ErrInvalidShardID) {
+		t.Errorf("shardID 100 should be invalid with the default shard count. want=%v. got=%v", ErrInvalidShardID, err)
+	}
+}
+
+func TestValidateHeader(t *testing.T) {
+	chunkRoot := common.BytesToHash([]byte{1})
+	sig := make([]byte, 65)
+	sig[0] = 1
+	newHeader := func(shardID int64, chunkRoot *common.Hash, period int64, sig []byte) *CollationHeader {
+		return &CollationHeader{data: collationHeaderData{
+			ShardID:            big.NewInt(shardID),
+			ChunkRoot:          chunkRoot,
+			Period:             big.NewInt(period),
+			ProposerAddresses:  []*common.Address{&testProposerAddress},
+			ProposerSignatures: [][]byte{sig},
+		}}
+	}
+	cfg := ShardConfig{ShardCount: 4, PeriodWindow: 2}
+
+	tests := []struct {
+		name    string
+		header  *CollationHeader
+		want    error
+		wantErr bool
+	}{
+		{name: "valid", header: newHeader(3, &chunkRoot, 9, sig)},
+		{name: "last period of the window", header: newHeader(0, &chunkRoot, 8, sig)},
+		{name: "shardID out of range", header: newHeader(4, &chunkRoot, 9, sig), want: ErrInvalidShardID},
+		{name: "negative shardID", header: newHeader(-1, &chunkRoot, 9, sig), want: ErrInvalidShardID},
+		{name: "premature period", header: newHeader(1, &chunkRoot, 10, sig), want: ErrInvalidPeriod},
+		{name: "expired period", header: newHeader(1, &chunkRoot, 7, sig), want: ErrInvalidPeriod},
+		{name: "no chunk root", header: newHeader(1, nil, 9, sig), wantErr: true},
+		{name: "zero chunk root", header: newHeader(1, &common.Hash{}, 9, sig), wantErr: true},
+		{name: "unsigned", header: newHeader(1, &chunkRoot, 9, nil), want: ErrInvalidProposerSignature},
+		{name: "short signature", header: newHeader(1, &chunkRoot, 9, sig[:64]), want: ErrInvalidProposerSignature},
+		{name: "no header", header: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := ValidateHeader(tt.header, cfg, big.NewInt(9))
+		if tt.want == nil && !tt.wantErr {
+			if err != nil {
+				t.Errorf("%s: valid header failed validation: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: invalid header passed validation", tt.name)
+		}
+		if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: error does not wrap the sentinel. want=%v. got=%v", tt.name, tt.want, err)
+		}
+	}
+
+	zeroAddress := newHeader(1, &chunkRoot, 9, sig)
+	zeroAddress.data.ProposerAddresses = []*common.Address{{}}
+	if err := ValidateHeader(zeroAddress, cfg, big.NewInt(9)); err == nil {
+		t.Errorf("header with the zero proposer address passed validation")
+	}
+}
+
+func runValidateCollationsBenchmark(b *testing.B, txCount int, workers int) {
+	collations := make([]*Collation, 100)
+	for i := range collations {
+		header, err := NewCollationHeader(big.NewInt(1), nil, big.NewInt(10), &testProposerAddress, make([]byte, 65), false)
+		if err != nil {
+			b.Fatalf("could not create collation header: %v", err)
+		}
+		txs := makeRandomTransactions(txCount)
+		body, err := SerializeTxToBlob(txs)
+		if err != nil {
+			b.Fatalf("could not serialize transactions: %v", err)
+		}
+		chunkRoot := chunkRootFromBody(body)
+		header.data.ChunkRoot = &chunkRoot
+		collations[i] = NewCollation(header, body, txs)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, err := range ValidateCollations(collations, 100, big.NewInt(10), ValidationWorkers(workers)) {
+			if err != nil {
+				b.Fatalf("collation failed validation: %v", err)
+			}
+		}
+	}
+}
+
+func BenchmarkValidateCollations(b *testing.B) {
+	for _, txCount := range []int{10, 100, 1000} {
+		for _, workers := range []int{1, 4} {
+			b.Run(fmt.Sprintf("txs=%d/workers=%d", txCount, workers), func(b *testing.B) {
+				runValidateCollationsBenchmark(b, txCount, workers)
+			})
+		}
+	}
+}
+
+func TestValidateChain(t *testing.T) {
+	chain := func(specs ...[2]int64) []*Collation {
+		collations := make([]*Collation, len(specs))
+		for i, spec := range specs {
+			collations[i] = NewCollation(newTestCollationHeader(t, big.NewInt(spec[0]), nil, big.NewInt(spec[1])), nil, nil)
+		}
+		return collations
+	}
+
+	tests := []struct {
+		name       string
+		collations []*Collation
+		want       error
+		index      int
+	}{
+		{name: "empty", collations: nil},
+		{name: "single", collations: chain([2]int64{1, 5})},
+		{name: "consecutive", collations: chain([2]int64{1, 5}, [2]int64{1, 6}, [2]int64{1, 7})},
+		{name: "gap", collations: chain([2]int64{1, 5}, [2]int64{1, 6}, [2]int64{1, 8}), want: ErrPeriodGap, index: 2},
+		{name: "descending", collations: chain([2]int64{1, 5}, [2]int64{1, 4}), want: ErrPeriodGap, index: 1},
+		{name: "duplicate", collations: chain([2]int64{1, 5}, [2]int64{1, 5}, [2]int64{1, 6}), want: ErrPeriodDuplicate, index: 1},
+		{name: "shard mismatch", collations: chain([2]int64{1, 5}, [2]int64{1, 6}, [2]int64{2, 7}), want: ErrShardMismatch, index: 2},
+	}
+
+	for _, tt := range tests {
+		err := ValidateChain(tt.collations)
+		if !errors.Is(err, tt.want) || (tt.want == nil) != (err == nil) {
+			t.Errorf("%s: chain error incorrect. want=%v. got=%v", tt.name, tt.want, err)
+			continue
+		}
+		if err != nil && !strings.Contains(err.Error(), fmt.Sprintf("collation %d ", tt.index)) {
+			t.Errorf("%s: chain error should name the offending index. want=%d. got=%v", tt.name, tt.index, err)
+		}
+	}
+
+	if err := ValidateChain([]*Collation{nil}); err == nil {
+		t.Errorf("a chain with a nil collation should fail validation")
+	}
+}
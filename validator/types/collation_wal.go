@@ -0,0 +1,330 @@
+package types
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/prysmaticlabs/prysm/validator/params"
+)
+
+// walRecordKind identifies the operation a WAL record logs.
+type walRecordKind uint8
+
+const (
+	walBegin walRecordKind = iota
+	walData
+	walCommit
+	walRollback
+)
+
+// walHeaderSize is the size of the length and CRC32 prefix of every record.
+const walHeaderSize = 8
+
+// walRecordOverhead bounds the RLP encoding of a record besides its data: the
+// list and data prefixes, the kind and a shardID and period of up to 256 bits.
+const walRecordOverhead = 128
+
+// walMaxDataSize is the most body bytes logged in a single record, so that
+// larger writes are split over several records.
+var walMaxDataSize = int(params.DefaultCollationSizeLimit())
+
+// walMaxRecordSize is the largest record payload replayed. A larger length
+// prefix can only come from a corrupted record, so it is not trusted to
+// allocate the payload before its checksum is verified.
+var walMaxRecordSize = walMaxDataSize + walRecordOverhead
+
+// walRecord is the RLP encoded payload of a WAL record.
+type walRecord struct {
+	Kind    walRecordKind
+	ShardID *big.Int
+	Period  *big.Int
+	Data    []byte
+}
+
+// walKey identifies a collation body write by its shardID and period.
+type walKey struct {
+	shardID string
+	period  string
+}
+
+func newWALKey(shardID *big.Int, period *big.Int) walKey {
+	return walKey{shardID: shardID.String(), period: period.String()}
+}
+
+// CollationWAL is an append-only write-ahead log for collation bodies, so
+// that a node crashing after computing a chunk root but before persisting the
+// body does not lose the body. A body is written through the writer returned by
+// BeginWrite, persisted to the collation store and then marked as done through
+// Commit, or discarded through Rollback. Writes that were neither committed nor
+// rolled back are returned by Recover after a restart.
+//
+// Every record is prefixed with its length and the CRC32 checksum of its RLP
+// encoded payload. A torn record at the end of the log, left behind by a crash
+// in the middle of a write, is truncated when the log is opened.
+type CollationWAL struct {
+	file    *os.File
+	pending map[walKey]bool
+	lock    sync.Mutex
+}
+
+// NewCollationWAL opens or creates the write-ahead log at path, truncating any
+// torn record at its end.
+func NewCollationWAL(path string) (*CollationWAL, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open collation WAL: %v", err)
+	}
+	w := &CollationWAL{file: file, pending: make(map[walKey]bool)}
+
+	entries, size, err := w.replay()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("could not truncate collation WAL: %v", err)
+	}
+	if _, err := file.Seek(size, io.SeekStart); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("could not seek collation WAL: %v", err)
+	}
+	for _, entry := range entries {
+		w.pending[newWALKey(entry.shardID, entry.period)] = true
+	}
+	return w, nil
+}
+
+// Close closes the underlying file.
+func (w *CollationWAL) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.file.Close()
+}
+
+// BeginWrite starts logging the body of the collation for the shardID and
+// period. Every Write to the returned writer is synced to disk before it
+// returns. Only one write per shardID and period can be in progress.
+func (w *CollationWAL) BeginWrite(shardID *big.Int, period *big.Int) (io.Writer, error) {
+	if shardID == nil || period == nil {
+		return nil, errors.New("shardID and period are required")
+	}
+	if shardID.Sign() < 0 || shardID.BitLen() > 256 {
+		return nil, fmt.Errorf("shardID %v out of range: %w", shardID, ErrInvalidShardID)
+	}
+	if period.Sign() < 0 || period.BitLen() > 256 {
+		return nil, fmt.Errorf("period %v out of range: %w", period, ErrInvalidPeriod)
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	key := newWALKey(shardID, period)
+	if w.pending[key] {
+		return nil, fmt.Errorf("write of collation for shardID %v and period %v already in progress", shardID, period)
+	}
+	if err := w.append(walRecord{Kind: walBegin, ShardID: shardID, Period: period}); err != nil {
+		return nil, err
+	}
+	w.pending[key] = true
+	return &walWriter{wal: w, shardID: new(big.Int).Set(shardID), period: new(big.Int).Set(period)}, nil
+}
+
+// Commit marks the write of the collation for the shardID and period as done,
+// once its body was persisted.
+func (w *CollationWAL) Commit(shardID *big.Int, period *big.Int) error {
+	return w.finish(walCommit, shardID, period)
+}
+
+// Rollback discards the write of the collation for the shardID and period.
+func (w *CollationWAL) Rollback(shardID *big.Int, period *big.Int) error {
+	return w.finish(walRollback, shardID, period)
+}
+
+// Recover returns the collations whose writes were neither committed nor rolled
+// back, in the order their writes began. The collations only carry the shardID
+// and period in their headers, with the chunk root calculated from the logged
+// body. Recovered writes stay in progress until they are committed or rolled back.
+func (w *CollationWAL) Recover() ([]*Collation, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	entries, _, err := w.replay()
+	if err != nil {
+		return nil, err
+	}
+	collations := make([]*Collation, 0, len(entries))
+	for _, entry := range entries {
+		var txs []*gethTypes.Transaction
+		if decoded, err := DeserializeBlobToTx(BytesBodyReader(entry.body)); err != nil {
+			log.Warnf("Could not deserialize recovered body of collation for shardID %v and period %v: %v", entry.shardID, entry.period, err)
+		} else {
+			txs = *decoded
+		}
+		header := &CollationHeader{data: collationHeaderData{ShardID: entry.shardID, Period: entry.period}}
+		collation := NewCollation(header, entry.body, txs)
+		collation.CalculateChunkRoot()
+		collations = append(collations, collation)
+	}
+	return collations, nil
+}
+
+// finish logs the end of an in-progress write.
+func (w *CollationWAL) finish(kind walRecordKind, shardID *big.Int, period *big.Int) error {
+	if shardID == nil || period == nil {
+		return errors.New("shardID and period are required")
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	key := newWALKey(shardID, period)
+	if !w.pending[key] {
+		return fmt.Errorf("no write of collation for shardID %v and period %v in progress", shardID, period)
+	}
+	if err := w.append(walRecord{Kind: kind, ShardID: shardID, Period: period}); err != nil {
+		return err
+	}
+	delete(w.pending, key)
+	if len(w.pending) == 0 {
+		// Nothing left to recover, so the log can start over.
+		return w.reset()
+	}
+	return nil
+}
+
+// append writes a record at the end of the log and syncs it to disk. The
+// caller must hold the lock.
+func (w *CollationWAL) append(record walRecord) error {
+	payload, err := rlp.EncodeToBytes(&record)
+	if err != nil {
+		return fmt.Errorf("could not encode WAL record: %v", err)
+	}
+	buf := make([]byte, walHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(payload))
+	copy(buf[walHeaderSize:], payload)
+
+	if _, err := w.file.Write(buf); err != nil {
+		return fmt.Errorf("could not write WAL record: %v", err)
+	}
+	return w.file.Sync()
+}
+
+// reset empties the log. The caller must hold the lock.
+func (w *CollationWAL) reset() error {
+	if err := w.file.Truncate(0); err != nil {
+		return fmt.Errorf("could not truncate collation WAL: %v", err)
+	}
+	_, err := w.file.Seek(0, io.SeekStart)
+	return err
+}
+
+// walEntry is a collation body write replayed from the log.
+type walEntry struct {
+	shardID *big.Int
+	period  *big.Int
+	body    []byte
+}
+
+// replay reads the log from the start and returns the writes left in progress
+// along with the size of the valid prefix of the log. Reading stops at the
+// first torn or corrupted record. The caller must hold the lock, except
+// while the log is being opened.
+func (w *CollationWAL) replay() ([]*walEntry, int64, error) {
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, fmt.Errorf("could not seek collation WAL: %v", err)
+	}
+	defer w.file.Seek(0, io.SeekEnd)
+
+	var entries []*walEntry
+	inProgress := make(map[walKey]*walEntry)
+	var size int64
+	header := make([]byte, walHeaderSize)
+	for {
+		if _, err := io.ReadFull(w.file, header); err != nil {
+			break
+		}
+		length := binary.BigEndian.Uint32(header[0:4])
+		if uint64(length) > uint64(walMaxRecordSize) {
+			log.Warnf("Collation WAL record at offset %d has length %d over the maximum %d, discarding the rest of the log", size, length, walMaxRecordSize)
+			break
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(w.file, payload); err != nil {
+			break
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+			log.Warnf("Collation WAL record at offset %d failed its checksum, discarding the rest of the log", size)
+			break
+		}
+		var record walRecord
+		if err := rlp.DecodeBytes(payload, &record); err != nil {
+			log.Warnf("Could not decode collation WAL record at offset %d, discarding the rest of the log: %v", size, err)
+			break
+		}
+		size += int64(walHeaderSize + len(payload))
+
+		key := newWALKey(record.ShardID, record.Period)
+		switch record.Kind {
+		case walBegin:
+			entry := &walEntry{shardID: record.ShardID, period: record.Period}
+			inProgress[key] = entry
+			entries = append(entries, entry)
+		case walData:
+			if entry, ok := inProgress[key]; ok {
+				entry.body = append(entry.body, record.Data...)
+			}
+		case walCommit, walRollback:
+			delete(inProgress, key)
+		}
+	}
+
+	pending := entries[:0]
+	for _, entry := range entries {
+		if inProgress[newWALKey(entry.shardID, entry.period)] == entry {
+			pending = append(pending, entry)
+		}
+	}
+	return pending, size, nil
+}
+
+// walWriter logs the body of a single collation write.
+type walWriter struct {
+	wal     *CollationWAL
+	shardID *big.Int
+	period  *big.Int
+}
+
+// Write implements io.Writer, logging p as part of the collation body in
+// records of at most walMaxDataSize bytes.
+func (ww *walWriter) Write(p []byte) (int, error) {
+	ww.wal.lock.Lock()
+	defer ww.wal.lock.Unlock()
+
+	if !ww.wal.pending[newWALKey(ww.shardID, ww.period)] {
+		return 0, fmt.Errorf("write of collation for shardID %v and period %v is not in progress", ww.shardID, ww.period)
+	}
+	written := 0
+	for written < len(p) {
+		end := written + walMaxDataSize
+		if end > len(p) {
+			end = len(p)
+		}
+		record := walRecord{Kind: walData, ShardID: ww.shardID, Period: ww.period, Data: common.CopyBytes(p[written:end])}
+		if err := ww.wal.append(record); err != nil {
+			return written, err
+		}
+		written = end
+	}
+	return written, nil
+}
@@ -0,0 +1,265 @@
+package types
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func setupCollationWAL(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "collationwal")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	return filepath.Join(dir, "wal"), func() { os.RemoveAll(dir) }
+}
+
+func openCollationWAL(t *testing.T, path string) *CollationWAL {
+	wal, err := NewCollationWAL(path)
+	if err != nil {
+		t.Fatalf("could not open collation WAL: %v", err)
+	}
+	return wal
+}
+
+// writeCollationBody logs the body of the collation through the WAL in two writes.
+func writeCollationBody(t *testing.T, wal *CollationWAL, c *Collation) {
+	w, err := wal.BeginWrite(c.Header().ShardID(), c.Header().Period())
+	if err != nil {
+		t.Fatalf("could not begin write: %v", err)
+	}
+	half := len(c.Body()) / 2
+	for _, part := range [][]byte{c.Body()[:half], c.Body()[half:]} {
+		if _, err := w.Write(part); err != nil {
+			t.Fatalf("could not write body: %v", err)
+		}
+	}
+}
+
+func TestCollationWAL_Recover(t *testing.T) {
+	path, cleanup := setupCollationWAL(t)
+	defer cleanup()
+
+	wal := openCollationWAL(t, path)
+	committed := makeStoredCollation(t, 1, 1)
+	rolledBack := makeStoredCollation(t, 1, 2)
+	uncommitted := makeStoredCollation(t, 2, 1)
+	for _, c := range []*Collation{committed, uncommitted, rolledBack} {
+		writeCollationBody(t, wal, c)
+	}
+	if err := wal.Commit(committed.Header().ShardID(), committed.Header().Period()); err != nil {
+		t.Fatalf("could not commit write: %v", err)
+	}
+	if err := wal.Rollback(rolledBack.Header().ShardID(), rolledBack.Header().Period()); err != nil {
+		t.Fatalf("could not roll back write: %v", err)
+	}
+	// Simulate a crash by reopening the log without closing it.
+	wal = openCollationWAL(t, path)
+	defer wal.Close()
+
+	recovered, err := wal.Recover()
+	if err != nil {
+		t.Fatalf("could not recover collations: %v", err)
+	}
+	if len(recovered) != 1 {
+		t.Fatalf("number of recovered collations incorrect. want=%d. got=%d", 1, len(recovered))
+	}
+	got := recovered[0]
+	if got.Header().ShardID().Cmp(uncommitted.Header().ShardID()) != 0 || got.Header().Period().Cmp(uncommitted.Header().Period()) != 0 {
+		t.Errorf("recovered collation incorrect. want=shard %v period %v. got=shard %v period %v",
+			uncommitted.Header().ShardID(), uncommitted.Header().Period(), got.Header().ShardID(), got.Header().Period())
+	}
+	if !bytes.Equal(got.Body(), uncommitted.Body()) {
+		t.Errorf("recovered body incorrect. want=%x. got=%x", uncommitted.Body(), got.Body())
+	}
+	if *got.Header().ChunkRoot() != *uncommitted.Header().ChunkRoot() {
+		t.Errorf("recovered chunk root incorrect. want=%x. got=%x", *uncommitted.Header().ChunkRoot(), *got.Header().ChunkRoot())
+	}
+	if len(got.Transactions()) != len(uncommitted.Transactions()) {
+		t.Errorf("recovered transactions incorrect. want=%d. got=%d", len(uncommitted.Transactions()), len(got.Transactions()))
+	}
+
+	// The recovered write stays in progress until it is committed.
+	if _, err := wal.BeginWrite(uncommitted.Header().ShardID(), uncommitted.Header().Period()); err == nil {
+		t.Errorf("beginning a recovered write again should fail")
+	}
+	if err := wal.Commit(uncommitted.Header().ShardID(), uncommitted.Header().Period()); err != nil {
+		t.Fatalf("could not commit recovered write: %v", err)
+	}
+	if recovered, err := wal.Recover(); err != nil || len(recovered) != 0 {
+		t.Errorf("committed writes should not be recovered. got=%d collations, err=%v", len(recovered), err)
+	}
+}
+
+func TestCollationWAL_TornRecord(t *testing.T) {
+	path, cleanup := setupCollationWAL(t)
+	defer cleanup()
+
+	wal := openCollationWAL(t, path)
+	c := makeStoredCollation(t, 1, 1)
+	writeCollationBody(t, wal, c)
+	wal.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("could not stat WAL: %v", err)
+	}
+	// Append half of a record, as left behind by a crash in the middle of a write.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("could not open WAL: %v", err)
+	}
+	f.Write([]byte{0, 0, 0, 100, 1, 2, 3, 4, 5})
+	f.Close()
+
+	wal = openCollationWAL(t, path)
+	defer wal.Close()
+	if truncated, _ := os.Stat(path); truncated.Size() != info.Size() {
+		t.Errorf("torn record should be truncated. want=%d bytes. got=%d", info.Size(), truncated.Size())
+	}
+	recovered, err := wal.Recover()
+	if err != nil {
+		t.Fatalf("could not recover collations: %v", err)
+	}
+	if len(recovered) != 1 || !bytes.Equal(recovered[0].Body(), c.Body()) {
+		t.Errorf("write before the torn record should be recovered")
+	}
+}
+
+func TestCollationWAL_Checksum(t *testing.T) {
+	path, cleanup := setupCollationWAL(t)
+	defer cleanup()
+
+	wal := openCollationWAL(t, path)
+	writeCollationBody(t, wal, makeStoredCollation(t, 1, 1))
+	writeCollationBody(t, wal, makeStoredCollation(t, 1, 2))
+	wal.Close()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read WAL: %v", err)
+	}
+	// Flip a byte of the last record's payload.
+	data[len(data)-1] ^= 0xff
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("could not write WAL: %v", err)
+	}
+
+	wal = openCollationWAL(t, path)
+	defer wal.Close()
+	recovered, err := wal.Recover()
+	if err != nil {
+		t.Fatalf("could not recover collations: %v", err)
+	}
+	if len(recovered) != 2 {
+		t.Fatalf("number of recovered collations incorrect. want=%d. got=%d", 2, len(recovered))
+	}
+	if len(recovered[1].Body()) >= len(recovered[0].Body()) {
+		t.Errorf("the corrupted record should be discarded. got body of %d bytes", len(recovered[1].Body()))
+	}
+}
+
+func TestCollationWAL_CorruptedLength(t *testing.T) {
+	path, cleanup := setupCollationWAL(t)
+	defer cleanup()
+
+	wal := openCollationWAL(t, path)
+	c := makeStoredCollation(t, 1, 1)
+	writeCollationBody(t, wal, c)
+	wal.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("could not stat WAL: %v", err)
+	}
+	// Append a record header claiming a 4 GiB payload.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("could not open WAL: %v", err)
+	}
+	if _, err := f.Write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}); err != nil {
+		t.Fatalf("could not write WAL: %v", err)
+	}
+	f.Close()
+
+	wal = openCollationWAL(t, path)
+	defer wal.Close()
+	recovered, err := wal.Recover()
+	if err != nil {
+		t.Fatalf("could not recover collations: %v", err)
+	}
+	if len(recovered) != 1 || !bytes.Equal(recovered[0].Body(), c.Body()) {
+		t.Errorf("records before the corrupted length should be recovered")
+	}
+	if truncated, _ := os.Stat(path); truncated.Size() != info.Size() {
+		t.Errorf("corrupted record should be truncated. want=%d bytes. got=%d", info.Size(), truncated.Size())
+	}
+}
+
+func TestCollationWAL_LargeWrite(t *testing.T) {
+	path, cleanup := setupCollationWAL(t)
+	defer cleanup()
+
+	wal := openCollationWAL(t, path)
+	w, err := wal.BeginWrite(big.NewInt(1), big.NewInt(1))
+	if err != nil {
+		t.Fatalf("could not begin write: %v", err)
+	}
+	body := bytes.Repeat([]byte{7}, walMaxDataSize+10)
+	if n, err := w.Write(body); err != nil || n != len(body) {
+		t.Fatalf("could not write body. n=%d, err=%v", n, err)
+	}
+	wal.Close()
+
+	wal = openCollationWAL(t, path)
+	defer wal.Close()
+	recovered, err := wal.Recover()
+	if err != nil {
+		t.Fatalf("could not recover collations: %v", err)
+	}
+	if len(recovered) != 1 || !bytes.Equal(recovered[0].Body(), body) {
+		t.Errorf("body larger than a record should be recovered from several records")
+	}
+}
+
+func TestCollationWAL_InvalidTransitions(t *testing.T) {
+	path, cleanup := setupCollationWAL(t)
+	defer cleanup()
+
+	wal := openCollationWAL(t, path)
+	defer wal.Close()
+	shardID, period := big.NewInt(1), big.NewInt(1)
+
+	if err := wal.Commit(shardID, period); err == nil {
+		t.Errorf("committing a write that was not begun should fail")
+	}
+	if err := wal.Rollback(shardID, period); err == nil {
+		t.Errorf("rolling back a write that was not begun should fail")
+	}
+	if _, err := wal.BeginWrite(nil, period); err == nil {
+		t.Errorf("beginning a write without shardID should fail")
+	}
+	if _, err := wal.BeginWrite(big.NewInt(-1), period); err == nil {
+		t.Errorf("beginning a write with a negative shardID should fail")
+	}
+
+	w, err := wal.BeginWrite(shardID, period)
+	if err != nil {
+		t.Fatalf("could not begin write: %v", err)
+	}
+	if _, err := wal.BeginWrite(shardID, period); err == nil {
+		t.Errorf("beginning a write twice should fail")
+	}
+	if err := wal.Commit(shardID, period); err != nil {
+		t.Fatalf("could not commit write: %v", err)
+	}
+	if _, err := w.Write([]byte{1}); err == nil {
+		t.Errorf("writing to a committed write should fail")
+	}
+	if info, _ := os.Stat(path); info.Size() != 0 {
+		t.Errorf("log should be emptied once no write is in progress. got=%d bytes", info.Size())
+	}
+}
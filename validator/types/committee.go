@@ -0,0 +1,183 @@
+package types
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Committee is the set of validators sampled for a shard during an epoch.
+type Committee struct {
+	ShardID    *big.Int
+	Validators []common.Address
+	Epoch      *big.Int
+}
+
+// ComputeCommittee samples the committee of a shard for an epoch by shuffling
+// the validators with a Fisher-Yates shuffle seeded by
+// keccak256(seed || shardID || epoch) and keeping the first CommitteeSize of them,
+// as set in the optional config. The given validators slice is left untouched.
+func ComputeCommittee(validators []common.Address, shardID *big.Int, epoch *big.Int, seed common.Hash, config ...ShardConfig) (*Committee, error) {
+	var cfg ShardConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if len(validators) == 0 {
+		return nil, errors.New("no validators to sample a committee from")
+	}
+	if cfg.committeeSize() <= 0 {
+		return nil, fmt.Errorf("committee size %d must be positive", cfg.committeeSize())
+	}
+	if shardID == nil || shardID.Sign() < 0 {
+		return nil, fmt.Errorf("shardID %v must be non-negative: %w", shardID, ErrInvalidShardID)
+	}
+	if epoch == nil || epoch.Sign() < 0 {
+		return nil, fmt.Errorf("epoch %v must be non-negative", epoch)
+	}
+
+	shuffled := make([]common.Address, len(validators))
+	copy(shuffled, validators)
+
+	rng := &hashRand{randomness: crypto.Keccak256Hash(seed.Bytes(), common.BigToHash(shardID).Bytes(), common.BigToHash(epoch).Bytes())}
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j := rng.intn(i + 1)
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+
+	size := cfg.committeeSize()
+	if size > len(shuffled) {
+		size = len(shuffled)
+	}
+	return &Committee{
+		ShardID:    new(big.Int).Set(shardID),
+		Validators: shuffled[:size],
+		Epoch:      new(big.Int).Set(epoch),
+	}, nil
+}
+
+// SampleCommittee samples committeeSize validators without replacement for the
+// shard in a period, using a PRNG seeded by
+// keccak256(beaconSeed || shardID.Bytes() || period.Bytes()) so that anyone
+// knowing the beacon seed can verify the committee. All validators are
+// returned if committeeSize exceeds their number. The given validators slice is
+// left untouched.
+func SampleCommittee(beaconSeed common.Hash, shardID *big.Int, period *big.Int, validators []common.Address, committeeSize int) ([]common.Address, error) {
+	if len(validators) == 0 {
+		return nil, errors.New("no validators to sample a committee from")
+	}
+	if shardID == nil || shardID.Sign() < 0 {
+		return nil, fmt.Errorf("shardID %v must be non-negative: %w", shardID, ErrInvalidShardID)
+	}
+	if period == nil || period.Sign() < 0 {
+		return nil, fmt.Errorf("period %v must be non-negative: %w", period, ErrInvalidPeriod)
+	}
+	if committeeSize <= 0 {
+		return nil, fmt.Errorf("committee size %d must be positive", committeeSize)
+	}
+
+	sampled := make([]common.Address, len(validators))
+	copy(sampled, validators)
+	if committeeSize >= len(sampled) {
+		return sampled, nil
+	}
+
+	// A partial Fisher-Yates shuffle picks the committee members in order.
+	rng := &hashRand{randomness: crypto.Keccak256Hash(beaconSeed.Bytes(), shardID.Bytes(), period.Bytes())}
+	for i := 0; i < committeeSize; i++ {
+		j := i + rng.intn(len(sampled)-i)
+		sampled[i], sampled[j] = sampled[j], sampled[i]
+	}
+	return sampled[:committeeSize], nil
+}
+
+// CommitteeIndex returns the index of addr in the committee and whether it is
+// a member. It builds a CommitteeLookup for a single check; callers checking
+// many addresses against the same committee should keep a CommitteeLookup.
+func CommitteeIndex(addr common.Address, committee []common.Address) (int, bool) {
+	return NewCommitteeLookup(committee).Index(addr)
+}
+
+// CommitteeLookup indexes the members of a committee for constant time
+// membership checks.
+type CommitteeLookup struct {
+	index map[common.Address]int
+}
+
+// NewCommitteeLookup indexes the committee's members by address. An address
+// listed more than once is indexed at its first position.
+func NewCommitteeLookup(committee []common.Address) *CommitteeLookup {
+	index := make(map[common.Address]int, len(committee))
+	for i := len(committee) - 1; i >= 0; i-- {
+		index[committee[i]] = i
+	}
+	return &CommitteeLookup{index: index}
+}
+
+// Index returns the index of addr in the committee and whether it is a member.
+func (l *CommitteeLookup) Index(addr common.Address) (int, bool) {
+	i, ok := l.index[addr]
+	return i, ok
+}
+
+// CommitteeForProposer returns the first committee the proposer is a member of.
+func CommitteeForProposer(addr common.Address, committees []*Committee) (*Committee, error) {
+	for _, committee := range committees {
+		for _, validator := range committee.Validators {
+			if validator == addr {
+				return committee, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("proposer %s is not in any committee", addr.Hex())
+}
+
+// ElectProposer selects the proposer of a shard for a period from the
+// validators, using keccak256(vrfSeed || shardID || period) modulo the number
+// of validators as the index of the elected proposer.
+func ElectProposer(validators []common.Address, shardID *big.Int, period *big.Int, vrfSeed []byte) (common.Address, error) {
+	if len(validators) == 0 {
+		return common.Address{}, errors.New("no validators to elect a proposer from")
+	}
+	if shardID == nil || shardID.Sign() < 0 {
+		return common.Address{}, fmt.Errorf("shardID %v must be non-negative: %w", shardID, ErrInvalidShardID)
+	}
+	if period == nil || period.Sign() < 0 {
+		return common.Address{}, fmt.Errorf("period %v must be non-negative: %w", period, ErrInvalidPeriod)
+	}
+
+	output := crypto.Keccak256(vrfSeed, common.BigToHash(shardID).Bytes(), common.BigToHash(period).Bytes())
+	index := new(big.Int).Mod(new(big.Int).SetBytes(output), big.NewInt(int64(len(validators))))
+	return validators[index.Int64()], nil
+}
+
+// IsElectedProposer checks whether addr is the proposer elected by ElectProposer.
+func IsElectedProposer(addr common.Address, validators []common.Address, shardID, period *big.Int, vrfSeed []byte) bool {
+	proposer, err := ElectProposer(validators, shardID, period, vrfSeed)
+	if err != nil {
+		return false
+	}
+	return proposer == addr
+}
+
+// hashRand is a deterministic PRNG drawing 8 bytes of randomness at a time from
+// a keccak256 hash chain, rehashing once the current hash runs out.
+type hashRand struct {
+	randomness common.Hash
+	offset     int
+}
+
+// intn returns a number in [0, n). The modulo bias is negligible for n far
+// below 2^64, such as the size of a validator set.
+func (r *hashRand) intn(n int) int {
+	if r.offset+8 > common.HashLength {
+		r.randomness = crypto.Keccak256Hash(r.randomness.Bytes())
+		r.offset = 0
+	}
+	v := binary.BigEndian.Uint64(r.randomness[r.offset:r.offset+8]) % uint64(n)
+	r.offset += 8
+	return int(v)
+}
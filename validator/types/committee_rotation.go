@@ -0,0 +1,104 @@
+package types
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// committeeRotationKey identifies a committee by its shardID and the index of
+// the rotation it serves in.
+type committeeRotationKey struct {
+	shardID  string
+	rotation string
+}
+
+// CommitteeRotationSchedule samples the committees of shards from a validator
+// set, rotating them every RotationPeriod periods. A committee serves from the
+// first period of its rotation up to the next rotation, and the last computed
+// committees are cached as consecutive periods of a rotation share them.
+type CommitteeRotationSchedule struct {
+	validators     []common.Address
+	seed           common.Hash
+	rotationPeriod *big.Int
+	config         ShardConfig
+	cacheSize      int
+	order          *list.List // most recently used committees first.
+	cache          map[committeeRotationKey]*list.Element
+	lock           sync.Mutex
+}
+
+// NewCommitteeRotationSchedule creates a schedule sampling the committees from
+// the validators with the seed, rotating them as often and sampling them as
+// large as set in the config. Up to cacheSize committees are cached.
+func NewCommitteeRotationSchedule(validators []common.Address, seed common.Hash, config ShardConfig, cacheSize int) (*CommitteeRotationSchedule, error) {
+	if len(validators) == 0 {
+		return nil, errors.New("no validators to sample committees from")
+	}
+	if config.committeeRotationPeriod() < 0 {
+		return nil, fmt.Errorf("committee rotation period %d must be positive", config.committeeRotationPeriod())
+	}
+	if config.committeeSize() <= 0 {
+		return nil, fmt.Errorf("committee size %d must be positive", config.committeeSize())
+	}
+	return &CommitteeRotationSchedule{
+		validators:     append([]common.Address(nil), validators...),
+		seed:           seed,
+		rotationPeriod: big.NewInt(config.committeeRotationPeriod()),
+		config:         config,
+		cacheSize:      cacheSize,
+		order:          list.New(),
+		cache:          make(map[committeeRotationKey]*list.Element),
+	}, nil
+}
+
+// RotationPeriod returns the number of periods a committee serves.
+func (s *CommitteeRotationSchedule) RotationPeriod() *big.Int {
+	return new(big.Int).Set(s.rotationPeriod)
+}
+
+// NextRotation returns the first period after currentPeriod in which the
+// committees rotate.
+func (s *CommitteeRotationSchedule) NextRotation(currentPeriod *big.Int) *big.Int {
+	next := new(big.Int).Div(currentPeriod, s.rotationPeriod)
+	next.Add(next, big.NewInt(1))
+	return next.Mul(next, s.rotationPeriod)
+}
+
+// Committee returns the committee of the shard serving in the period.
+func (s *CommitteeRotationSchedule) Committee(shardID *big.Int, period *big.Int) ([]common.Address, error) {
+	if period == nil || period.Sign() < 0 {
+		return nil, fmt.Errorf("period %v must be non-negative: %w", period, ErrInvalidPeriod)
+	}
+	if shardID == nil {
+		return nil, fmt.Errorf("no shardID provided: %w", ErrInvalidShardID)
+	}
+	rotation := new(big.Int).Div(period, s.rotationPeriod)
+	key := committeeRotationKey{shardID: shardID.String(), rotation: rotation.String()}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if elem, ok := s.cache[key]; ok {
+		s.order.MoveToFront(elem)
+		return append([]common.Address(nil), elem.Value.(*Committee).Validators...), nil
+	}
+	committee, err := ComputeCommittee(s.validators, shardID, rotation, s.seed, s.config)
+	if err != nil {
+		return nil, err
+	}
+	if s.cacheSize > 0 {
+		s.cache[key] = s.order.PushFront(committee)
+		if s.order.Len() > s.cacheSize {
+			oldest := s.order.Back()
+			s.order.Remove(oldest)
+			evicted := oldest.Value.(*Committee)
+			delete(s.cache, committeeRotationKey{shardID: evicted.ShardID.String(), rotation: evicted.Epoch.String()})
+		}
+	}
+	return append([]common.Address(nil), committee.Validators...), nil
+}
@@ -0,0 +1,101 @@
+package types
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func newTestRotationSchedule(t *testing.T, cacheSize int) *CommitteeRotationSchedule {
+	schedule, err := NewCommitteeRotationSchedule(makeValidators(300), common.HexToHash("0x01"), ShardConfig{CommitteeRotationPeriod: 4}, cacheSize)
+	if err != nil {
+		t.Fatalf("could not create rotation schedule: %v", err)
+	}
+	return schedule
+}
+
+func TestCommitteeRotationSchedule_CommitteeSize(t *testing.T) {
+	schedule, err := NewCommitteeRotationSchedule(makeValidators(300), common.HexToHash("0x01"), ShardConfig{CommitteeSize: 9}, 2)
+	if err != nil {
+		t.Fatalf("could not create rotation schedule: %v", err)
+	}
+	committee, err := schedule.Committee(big.NewInt(1), big.NewInt(0))
+	if err != nil {
+		t.Fatalf("could not get committee: %v", err)
+	}
+	if len(committee) != 9 {
+		t.Errorf("committee size should follow the config. want=%d. got=%d", 9, len(committee))
+	}
+	if _, err := NewCommitteeRotationSchedule(makeValidators(3), common.Hash{}, ShardConfig{CommitteeSize: -1}, 2); err == nil {
+		t.Errorf("creating a schedule with a negative committee size should fail")
+	}
+}
+
+func TestCommitteeRotationSchedule_Committee(t *testing.T) {
+	schedule := newTestRotationSchedule(t, 2)
+	committee := func(shardID int64, period int64) []common.Address {
+		c, err := schedule.Committee(big.NewInt(shardID), big.NewInt(period))
+		if err != nil {
+			t.Fatalf("could not get committee of shard %d for period %d: %v", shardID, period, err)
+		}
+		return c
+	}
+
+	first := committee(1, 8)
+	for _, period := range []int64{9, 10, 11} {
+		if got := committee(1, period); !reflect.DeepEqual(got, first) {
+			t.Errorf("committee of period %d should match the committee of period %d", period, 8)
+		}
+	}
+	if got := committee(1, 12); reflect.DeepEqual(got, first) {
+		t.Errorf("committee should rotate in period %d", 12)
+	}
+	if got := committee(1, 7); reflect.DeepEqual(got, first) {
+		t.Errorf("committee of period %d should differ from the next rotation", 7)
+	}
+	if got := committee(2, 8); reflect.DeepEqual(got, first) {
+		t.Errorf("committees of different shards should differ")
+	}
+
+	// Evicted committees are computed again to the same result.
+	if got := committee(1, 8); !reflect.DeepEqual(got, first) {
+		t.Errorf("recomputed committee should match the evicted one")
+	}
+	if len(schedule.cache) != 2 || schedule.order.Len() != 2 {
+		t.Errorf("cache size incorrect. want=%d. got=%d", 2, len(schedule.cache))
+	}
+
+	first[0] = common.Address{}
+	if got := committee(1, 8); got[0] == (common.Address{}) {
+		t.Errorf("modifying a returned committee should not change the cached one")
+	}
+
+	if _, err := schedule.Committee(big.NewInt(1), big.NewInt(-1)); err == nil {
+		t.Errorf("getting the committee of a negative period should fail")
+	}
+}
+
+func TestCommitteeRotationSchedule_NextRotation(t *testing.T) {
+	schedule := newTestRotationSchedule(t, 0)
+	if schedule.RotationPeriod().Cmp(big.NewInt(4)) != 0 {
+		t.Errorf("rotation period incorrect. want=%d. got=%v", 4, schedule.RotationPeriod())
+	}
+
+	tests := []struct {
+		current int64
+		want    int64
+	}{
+		{current: 0, want: 4},
+		{current: 3, want: 4},
+		{current: 4, want: 8},
+		{current: 7, want: 8},
+		{current: 101, want: 104},
+	}
+	for _, tt := range tests {
+		if got := schedule.NextRotation(big.NewInt(tt.current)); got.Cmp(big.NewInt(tt.want)) != 0 {
+			t.Errorf("next rotation after period %d incorrect. want=%d. got=%v", tt.current, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package types
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prysmaticlabs/prysm/validator/params"
+)
+
+func makeValidators(n int) []common.Address {
+	validators := make([]common.Address, n)
+	for i := range validators {
+		validators[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+	}
+	return validators
+}
+
+func TestComputeCommittee_Deterministic(t *testing.T) {
+	validators := makeValidators(50)
+	original := append([]common.Address{}, validators...)
+	seed := common.BytesToHash([]byte("seed"))
+
+	a, err := ComputeCommittee(validators, big.NewInt(1), big.NewInt(2), seed)
+	if err != nil {
+		t.Fatalf("could not compute committee: %v", err)
+	}
+	b, err := ComputeCommittee(validators, big.NewInt(1), big.NewInt(2), seed)
+	if err != nil {
+		t.Fatalf("could not compute committee: %v", err)
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("committees computed from the same inputs should be equal")
+	}
+	if !reflect.DeepEqual(validators, original) {
+		t.Errorf("ComputeCommittee should not modify the given validators")
+	}
+	if reflect.DeepEqual(a.Validators, original) {
+		t.Errorf("committee should be shuffled")
+	}
+
+	members := make(map[common.Address]bool)
+	for _, v := range a.Validators {
+		members[v] = true
+	}
+	if len(members) != len(validators) {
+		t.Errorf("committee should be a permutation of the validators. want=%d members. got=%d", len(validators), len(members))
+	}
+
+	tests := []struct {
+		shardID *big.Int
+		epoch   *big.Int
+		seed    common.Hash
+	}{
+		{shardID: big.NewInt(2), epoch: big.NewInt(2), seed: seed},
+		{shardID: big.NewInt(1), epoch: big.NewInt(3), seed: seed},
+		{shardID: big.NewInt(1), epoch: big.NewInt(2), seed: common.BytesToHash([]byte("other"))},
+	}
+	for _, tt := range tests {
+		c, err := ComputeCommittee(validators, tt.shardID, tt.epoch, tt.seed)
+		if err != nil {
+			t.Fatalf("could not compute committee: %v", err)
+		}
+		if reflect.DeepEqual(c.Validators, a.Validators) {
+			t.Errorf("committee for shard %v, epoch %v and seed %x should be shuffled differently", tt.shardID, tt.epoch, tt.seed)
+		}
+	}
+}
+
+func TestComputeCommittee_Size(t *testing.T) {
+	size := params.DefaultConfig().CommitteeSize
+	c, err := ComputeCommittee(makeValidators(size*2), big.NewInt(0), big.NewInt(0), common.Hash{})
+	if err != nil {
+		t.Fatalf("could not compute committee: %v", err)
+	}
+	if len(c.Validators) != size {
+		t.Errorf("committee size incorrect. want=%d. got=%d", size, len(c.Validators))
+	}
+}
+
+func TestComputeCommittee_ConfiguredSize(t *testing.T) {
+	validators := makeValidators(20)
+	c, err := ComputeCommittee(validators, big.NewInt(0), big.NewInt(0), common.Hash{}, ShardConfig{CommitteeSize: 5})
+	if err != nil {
+		t.Fatalf("could not compute committee: %v", err)
+	}
+	if len(c.Validators) != 5 {
+		t.Errorf("committee size incorrect. want=%d. got=%d", 5, len(c.Validators))
+	}
+	if _, err := ComputeCommittee(validators, big.NewInt(0), big.NewInt(0), common.Hash{}, ShardConfig{CommitteeSize: -1}); err == nil {
+		t.Errorf("computing a committee of negative size should fail")
+	}
+}
+
+func TestComputeCommittee_InvalidInputs(t *testing.T) {
+	validators := makeValidators(3)
+	if _, err := ComputeCommittee(nil, big.NewInt(0), big.NewInt(0), common.Hash{}); err == nil {
+		t.Errorf("computing a committee without validators should fail")
+	}
+	if _, err := ComputeCommittee(validators, nil, big.NewInt(0), common.Hash{}); err == nil {
+		t.Errorf("computing a committee without a shardID should fail")
+	}
+	if _, err := ComputeCommittee(validators, big.NewInt(0), big.NewInt(-1), common.Hash{}); err == nil {
+		t.Errorf("computing a committee for a negative epoch should fail")
+	}
+}
+
+func TestCommitteeForProposer(t *testing.T) {
+	validators := makeValidators(4)
+	committees := []*Committee{
+		{ShardID: big.NewInt(0), Validators: validators[:2], Epoch: big.NewInt(1)},
+		{ShardID: big.NewInt(1), Validators: validators[2:], Epoch: big.NewInt(1)},
+	}
+
+	c, err := CommitteeForProposer(validators[3], committees)
+	if err != nil {
+		t.Fatalf("could not find committee for proposer: %v", err)
+	}
+	if c != committees[1] {
+		t.Errorf("wrong committee for proposer. want shard %v. got shard %v", committees[1].ShardID, c.ShardID)
+	}
+	if _, err := CommitteeForProposer(common.HexToAddress("0xff"), committees); err == nil {
+		t.Errorf("looking up a proposer outside of every committee should fail")
+	}
+}
+
+func TestSampleCommittee(t *testing.T) {
+	validators := makeValidators(50)
+	original := make([]common.Address, len(validators))
+	copy(original, validators)
+	seed := common.BytesToHash([]byte("beacon seed"))
+
+	committee, err := SampleCommittee(seed, big.NewInt(3), big.NewInt(10), validators, 10)
+	if err != nil {
+		t.Fatalf("could not sample committee: %v", err)
+	}
+	if len(committee) != 10 {
+		t.Fatalf("committee size incorrect. want=%d. got=%d", 10, len(committee))
+	}
+	lookup := NewCommitteeLookup(validators)
+	seen := make(map[common.Address]bool)
+	for _, member := range committee {
+		if _, ok := lookup.Index(member); !ok {
+			t.Errorf("committee member %s is not a validator", member.Hex())
+		}
+		if seen[member] {
+			t.Errorf("committee member %s sampled more than once", member.Hex())
+		}
+		seen[member] = true
+	}
+	if !reflect.DeepEqual(validators, original) {
+		t.Errorf("sampling should not modify the validators")
+	}
+
+	again, err := SampleCommittee(seed, big.NewInt(3), big.NewInt(10), validators, 10)
+	if err != nil {
+		t.Fatalf("could not sample committee: %v", err)
+	}
+	if !reflect.DeepEqual(committee, again) {
+		t.Errorf("sampling should be deterministic. want=%v. got=%v", committee, again)
+	}
+
+	others := []struct {
+		name    string
+		seed    common.Hash
+		shardID *big.Int
+		period  *big.Int
+	}{
+		{name: "other seed", seed: common.BytesToHash([]byte("other seed")), shardID: big.NewInt(3), period: big.NewInt(10)},
+		{name: "other shard", seed: seed, shardID: big.NewInt(4), period: big.NewInt(10)},
+		{name: "other period", seed: seed, shardID: big.NewInt(3), period: big.NewInt(11)},
+	}
+	for _, tt := range others {
+		other, err := SampleCommittee(tt.seed, tt.shardID, tt.period, validators, 10)
+		if err != nil {
+			t.Fatalf("could not sample committee: %v", err)
+		}
+		if reflect.DeepEqual(committee, other) {
+			t.Errorf("%s should sample a different committee", tt.name)
+		}
+	}
+}
+
+func TestSampleCommittee_AllValidators(t *testing.T) {
+	validators := makeValidators(5)
+	for _, size := range []int{5, 6, 100} {
+		committee, err := SampleCommittee(common.Hash{}, big.NewInt(0), big.NewInt(0), validators, size)
+		if err != nil {
+			t.Fatalf("could not sample committee: %v", err)
+		}
+		if !reflect.DeepEqual(committee, validators) {
+			t.Errorf("committee of size %d should hold every validator. want=%v. got=%v", size, validators, committee)
+		}
+	}
+}
+
+func TestSampleCommittee_InvalidInputs(t *testing.T) {
+	validators := makeValidators(3)
+	tests := []struct {
+		name       string
+		shardID    *big.Int
+		period     *big.Int
+		validators []common.Address
+		size       int
+	}{
+		{name: "no validators", shardID: big.NewInt(0), period: big.NewInt(0), size: 1},
+		{name: "nil shardID", period: big.NewInt(0), validators: validators, size: 1},
+		{name: "negative shardID", shardID: big.NewInt(-1), period: big.NewInt(0), validators: validators, size: 1},
+		{name: "negative period", shardID: big.NewInt(0), period: big.NewInt(-1), validators: validators, size: 1},
+		{name: "zero size", shardID: big.NewInt(0), period: big.NewInt(0), validators: validators, size: 0},
+	}
+	for _, tt := range tests {
+		if _, err := SampleCommittee(common.Hash{}, tt.shardID, tt.period, tt.validators, tt.size); err == nil {
+			t.Errorf("sampling a committee with %s should fail", tt.name)
+		}
+	}
+}
+
+func TestCommitteeIndex(t *testing.T) {
+	committee := makeValidators(4)
+	committee = append(committee, committee[1])
+	for i, addr := range committee[:4] {
+		index, ok := CommitteeIndex(addr, committee)
+		if !ok {
+			t.Errorf("committee member %s not found", addr.Hex())
+		}
+		if index != i {
+			t.Errorf("committee index of %s incorrect. want=%d. got=%d", addr.Hex(), i, index)
+		}
+	}
+	if _, ok := CommitteeIndex(common.HexToAddress("0xff"), committee); ok {
+		t.Errorf("address outside the committee should not be found")
+	}
+	if _, ok := CommitteeIndex(committee[0], nil); ok {
+		t.Errorf("no address should be found in an empty committee")
+	}
+}
+
+func TestElectProposer_Uniform(t *testing.T) {
+	validators := makeValidators(10)
+	seed := []byte("vrf output")
+	periods := 10000
+
+	counts := make(map[common.Address]int)
+	for period := 0; period < periods; period++ {
+		proposer, err := ElectProposer(validators, big.NewInt(1), big.NewInt(int64(period)), seed)
+		if err != nil {
+			t.Fatalf("could not elect proposer: %v", err)
+		}
+		counts[proposer]++
+	}
+
+	expected := periods / len(validators)
+	for _, v := range validators {
+		if counts[v] < expected*9/10 || counts[v] > expected*11/10 {
+			t.Errorf("proposer %s elected %d times, want around %d", v.Hex(), counts[v], expected)
+		}
+	}
+}
+
+func TestElectProposer(t *testing.T) {
+	validators := makeValidators(5)
+	seed := []byte("vrf output")
+
+	proposer, err := ElectProposer(validators, big.NewInt(1), big.NewInt(2), seed)
+	if err != nil {
+		t.Fatalf("could not elect proposer: %v", err)
+	}
+	again, _ := ElectProposer(validators, big.NewInt(1), big.NewInt(2), seed)
+	if proposer != again {
+		t.Errorf("election should be deterministic. got %s and %s", proposer.Hex(), again.Hex())
+	}
+	if !IsElectedProposer(proposer, validators, big.NewInt(1), big.NewInt(2), seed) {
+		t.Errorf("elected proposer %s should be recognized as elected", proposer.Hex())
+	}
+	for _, v := range validators {
+		if v != proposer && IsElectedProposer(v, validators, big.NewInt(1), big.NewInt(2), seed) {
+			t.Errorf("validator %s should not be recognized as elected", v.Hex())
+		}
+	}
+
+	if _, err := ElectProposer(nil, big.NewInt(1), big.NewInt(2), seed); err == nil {
+		t.Errorf("electing a proposer without validators should fail")
+	}
+	if _, err := ElectProposer(validators, big.NewInt(1), nil, seed); err == nil {
+		t.Errorf("electing a proposer without a period should fail")
+	}
+	if IsElectedProposer(validators[0], nil, big.NewInt(1), big.NewInt(2), seed) {
+		t.Errorf("no proposer should be elected without validators")
+	}
+}
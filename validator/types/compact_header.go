@@ -0,0 +1,59 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CompactHeader is a fixed-width representation of a collation header, for
+// indexing large numbers of historical collations. It only keeps the fields
+// identifying a collation, leaving out the signatures and body flags.
+type CompactHeader struct {
+	ShardID         uint16
+	Period          uint64
+	ChunkRoot       [32]byte
+	ProposerAddress [20]byte
+}
+
+// ToCompact converts a header of a single proposer with a chunk root to its
+// compact representation. The shardID must fit in 16 bits and the period in
+// 64 bits.
+func ToCompact(h *CollationHeader) (*CompactHeader, error) {
+	if h == nil {
+		return nil, errors.New("no collation header provided")
+	}
+	if h.ShardID() == nil || h.ShardID().Sign() < 0 || h.ShardID().Cmp(big.NewInt(math.MaxUint16)) > 0 {
+		return nil, fmt.Errorf("shardID %v does not fit a compact header: %w", h.ShardID(), ErrInvalidShardID)
+	}
+	if h.Period() == nil || h.Period().Sign() < 0 || !h.Period().IsUint64() {
+		return nil, fmt.Errorf("period %v does not fit a compact header: %w", h.Period(), ErrInvalidPeriod)
+	}
+	if h.ChunkRoot() == nil {
+		return nil, errors.New("collation header has no chunk root")
+	}
+	if len(h.ProposerAddresses()) != 1 || h.ProposerAddresses()[0] == nil {
+		return nil, fmt.Errorf("compact header needs a single proposer, header has %d", len(h.ProposerAddresses()))
+	}
+	return &CompactHeader{
+		ShardID:         uint16(h.ShardID().Uint64()),
+		Period:          h.Period().Uint64(),
+		ChunkRoot:       *h.ChunkRoot(),
+		ProposerAddress: *h.ProposerAddresses()[0],
+	}, nil
+}
+
+// FromCompact expands a compact header to an unsigned collation header.
+func FromCompact(c *CompactHeader) *CollationHeader {
+	chunkRoot := common.Hash(c.ChunkRoot)
+	proposer := common.Address(c.ProposerAddress)
+	return &CollationHeader{data: collationHeaderData{
+		ShardID:           new(big.Int).SetUint64(uint64(c.ShardID)),
+		ChunkRoot:         &chunkRoot,
+		Period:            new(big.Int).SetUint64(c.Period),
+		ProposerAddresses: []*common.Address{&proposer},
+	}}
+}
@@ -0,0 +1,124 @@
+package types
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"unsafe"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCompactHeader_RoundTrip(t *testing.T) {
+	chunkRoot := common.BytesToHash([]byte("chunk root"))
+	header := newTestCollationHeader(t, big.NewInt(5), &chunkRoot, big.NewInt(1000))
+	header.AddSig(make([]byte, proposerSignatureLength))
+
+	compact, err := ToCompact(header)
+	if err != nil {
+		t.Fatalf("could not compact header: %v", err)
+	}
+	expanded := FromCompact(compact)
+	if expanded.ShardID().Cmp(header.ShardID()) != 0 || expanded.Period().Cmp(header.Period()) != 0 {
+		t.Errorf("shardID and period incorrect. want=%v, %v. got=%v, %v", header.ShardID(), header.Period(), expanded.ShardID(), expanded.Period())
+	}
+	if *expanded.ChunkRoot() != chunkRoot {
+		t.Errorf("chunk root incorrect. want=%x. got=%x", chunkRoot, *expanded.ChunkRoot())
+	}
+	if *expanded.ProposerAddresses()[0] != *header.ProposerAddresses()[0] {
+		t.Errorf("proposer address incorrect. want=%s. got=%s", header.ProposerAddresses()[0].Hex(), expanded.ProposerAddresses()[0].Hex())
+	}
+	if expanded.Sig() != nil {
+		t.Errorf("expanded header should be unsigned")
+	}
+	if expanded.UnsignedHash() != header.UnsignedHash() {
+		t.Errorf("unsigned hash incorrect. want=%x. got=%x", header.UnsignedHash(), expanded.UnsignedHash())
+	}
+}
+
+func TestToCompact_Invalid(t *testing.T) {
+	chunkRoot := common.BytesToHash([]byte("chunk root"))
+	valid := func() *CollationHeader {
+		return newTestCollationHeader(t, big.NewInt(5), &chunkRoot, big.NewInt(1000))
+	}
+	tests := []struct {
+		name   string
+		header func() *CollationHeader
+		want   error
+	}{
+		{
+			name: "shardID too large",
+			header: func() *CollationHeader {
+				h := valid()
+				h.data.ShardID = big.NewInt(math.MaxUint16 + 1)
+				return h
+			},
+			want: ErrInvalidShardID,
+		},
+		{
+			name: "period too large",
+			header: func() *CollationHeader {
+				h := valid()
+				h.data.Period = new(big.Int).Lsh(big.NewInt(1), 64)
+				return h
+			},
+			want: ErrInvalidPeriod,
+		},
+		{
+			name: "no chunk root",
+			header: func() *CollationHeader {
+				h := valid()
+				h.data.ChunkRoot = nil
+				return h
+			},
+		},
+		{
+			name: "several proposers",
+			header: func() *CollationHeader {
+				h := valid()
+				h.data.ProposerAddresses = append(h.data.ProposerAddresses, &common.Address{0x01})
+				return h
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		_, err := ToCompact(tt.header())
+		if err == nil {
+			t.Errorf("%s: compacting the header should fail", tt.name)
+		} else if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: compact error incorrect. want=%v. got=%v", tt.name, tt.want, err)
+		}
+	}
+	if _, err := ToCompact(nil); err == nil {
+		t.Errorf("compacting a nil header should fail")
+	}
+}
+
+func TestCompactHeader_Size(t *testing.T) {
+	if size := unsafe.Sizeof(CompactHeader{}); size > 80 {
+		t.Errorf("compact header too large. want<=%d. got=%d", 80, size)
+	}
+}
+
+func BenchmarkCompactHeader_Memory(b *testing.B) {
+	chunkRoot := common.BytesToHash([]byte("chunk root"))
+	addr := common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567")
+	compact := &CompactHeader{ShardID: 5, Period: 1000, ChunkRoot: chunkRoot, ProposerAddress: addr}
+
+	b.Run("CompactHeader", func(b *testing.B) {
+		b.ReportAllocs()
+		index := make([]CompactHeader, 0, b.N)
+		for i := 0; i < b.N; i++ {
+			index = append(index, *compact)
+		}
+	})
+	b.Run("CollationHeader", func(b *testing.B) {
+		b.ReportAllocs()
+		index := make([]*CollationHeader, 0, b.N)
+		for i := 0; i < b.N; i++ {
+			index = append(index, FromCompact(compact))
+		}
+	})
+}
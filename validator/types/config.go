@@ -0,0 +1,80 @@
+package types
+
+import (
+	"github.com/prysmaticlabs/prysm/validator/params"
+)
+
+// ShardConfig defines the shard parameters a collation is built and validated
+// against. Zero-valued fields fall back to the defaults defined in the params package.
+type ShardConfig struct {
+	CollationSizeLimit      int64  // CollationSizeLimit is the maximum size the serialized blobs in a collation can take.
+	PeriodWindow            int64  // PeriodWindow is the number of periods a collation remains valid for, starting at its own period.
+	GasLimit                uint64 // GasLimit is the maximum amount of gas the transactions in a collation can use.
+	ShardCount              int    // ShardCount is the number of active shards, which bounds the shardIDs of collations.
+	CommitteeRotationPeriod int64  // CommitteeRotationPeriod is the number of periods a shard's committee serves before rotating.
+	CommitteeSize           int    // CommitteeSize is the number of validators sampled into a shard's committee.
+}
+
+// defaultPeriodWindow only allows collations to be included during their own
+// period, like the sharding manager contract does.
+const defaultPeriodWindow = 1
+
+// collationSizeLimit returns the configured collation size limit, defaulting to 2^20 bytes.
+func (cfg ShardConfig) collationSizeLimit() int64 {
+	if cfg.CollationSizeLimit == 0 {
+		return params.DefaultCollationSizeLimit()
+	}
+	return cfg.CollationSizeLimit
+}
+
+// gasLimit returns the configured collation gas limit, defaulting to the params package default.
+func (cfg ShardConfig) gasLimit() uint64 {
+	if cfg.GasLimit == 0 {
+		return params.DefaultCollationGasLimit()
+	}
+	return cfg.GasLimit
+}
+
+// shardCount returns the configured shard count, defaulting to the params package default of 100 shards.
+func (cfg ShardConfig) shardCount() int {
+	if cfg.ShardCount == 0 {
+		return int(params.DefaultShardCount())
+	}
+	return cfg.ShardCount
+}
+
+// periodWindow returns the configured period window, defaulting to a single period.
+func (cfg ShardConfig) periodWindow() int64 {
+	if cfg.PeriodWindow == 0 {
+		return defaultPeriodWindow
+	}
+	return cfg.PeriodWindow
+}
+
+// committeeRotationPeriod returns the configured committee rotation period,
+// defaulting to the params package default.
+func (cfg ShardConfig) committeeRotationPeriod() int64 {
+	if cfg.CommitteeRotationPeriod == 0 {
+		return params.DefaultCommitteeRotationPeriod()
+	}
+	return cfg.CommitteeRotationPeriod
+}
+
+// Option configures optional properties of a collation upon creation.
+type Option func(c *Collation)
+
+// WithConfig sets the shard configuration a collation is serialized against.
+func WithConfig(cfg ShardConfig) Option {
+	return func(c *Collation) {
+		c.config = cfg
+	}
+}
+
+// committeeSize returns the configured committee size, defaulting to the params
+// package default.
+func (cfg ShardConfig) committeeSize() int {
+	if cfg.CommitteeSize == 0 {
+		return params.DefaultCommitteeSize()
+	}
+	return cfg.CommitteeSize
+}
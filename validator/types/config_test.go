@@ -0,0 +1,55 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/validator/params"
+)
+
+func TestShardConfig_DefaultCollationSizeLimit(t *testing.T) {
+	cfg := ShardConfig{}
+	if cfg.collationSizeLimit() != params.DefaultCollationSizeLimit() {
+		t.Errorf("zero value config should default to collation size limit %d, got %d", params.DefaultCollationSizeLimit(), cfg.collationSizeLimit())
+	}
+
+	cfg = ShardConfig{CollationSizeLimit: 64}
+	if cfg.collationSizeLimit() != 64 {
+		t.Errorf("collation size limit incorrect. want=%d. got=%d", 64, cfg.collationSizeLimit())
+	}
+}
+
+func TestShardConfig_DefaultPeriodWindow(t *testing.T) {
+	cfg := ShardConfig{}
+	if cfg.periodWindow() != 1 {
+		t.Errorf("zero value config should default to a period window of 1, got %d", cfg.periodWindow())
+	}
+
+	cfg = ShardConfig{PeriodWindow: 5}
+	if cfg.periodWindow() != 5 {
+		t.Errorf("period window incorrect. want=%d. got=%d", 5, cfg.periodWindow())
+	}
+}
+
+func TestShardConfig_DefaultGasLimit(t *testing.T) {
+	cfg := ShardConfig{}
+	if cfg.gasLimit() != params.DefaultCollationGasLimit() {
+		t.Errorf("zero value config should default to gas limit %d, got %d", params.DefaultCollationGasLimit(), cfg.gasLimit())
+	}
+
+	cfg = ShardConfig{GasLimit: 21000}
+	if cfg.gasLimit() != 21000 {
+		t.Errorf("gas limit incorrect. want=%d. got=%d", 21000, cfg.gasLimit())
+	}
+}
+
+func TestShardConfig_DefaultShardCount(t *testing.T) {
+	cfg := ShardConfig{}
+	if cfg.shardCount() != 100 {
+		t.Errorf("zero value config should default to a shard count of 100, got %d", cfg.shardCount())
+	}
+
+	cfg = ShardConfig{ShardCount: 4}
+	if cfg.shardCount() != 4 {
+		t.Errorf("shard count incorrect. want=%d. got=%d", 4, cfg.shardCount())
+	}
+}
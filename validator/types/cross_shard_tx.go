@@ -0,0 +1,132 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// chunkDataSize is the number of transaction bytes in a body chunk, which
+// starts with a one byte indicator.
+const chunkDataSize = chunkSize - 1
+
+// CrossShardTx packages a transaction included in a collation of its source
+// shard so that it can be executed on its destination shard. The proof is the
+// Merkle proof of the first body chunk of the transaction against the chunk
+// root of the source collation.
+type CrossShardTx struct {
+	SourceShardID *big.Int               // the shard the transaction was included in.
+	DestShardID   *big.Int               // the shard the transaction targets.
+	Tx            *gethTypes.Transaction // the transaction itself.
+	Proof         []common.Hash          // the chunk proof from the source collation.
+}
+
+// NewCrossShardTx creates an envelope for the transaction from the src to the
+// dst shard. Its proof is set through Prove once the transaction is included
+// in a collation of the source shard.
+func NewCrossShardTx(tx *gethTypes.Transaction, src, dst *big.Int) *CrossShardTx {
+	return &CrossShardTx{
+		SourceShardID: src,
+		DestShardID:   dst,
+		Tx:            tx,
+	}
+}
+
+// Prove sets the envelope's proof from the source collation including the
+// transaction.
+func (x *CrossShardTx) Prove(collation *Collation) error {
+	index, err := x.firstChunk(collation)
+	if err != nil {
+		return err
+	}
+	proof, err := collation.ChunkProof(index)
+	if err != nil {
+		return fmt.Errorf("could not prove transaction chunk: %v", err)
+	}
+	x.Proof = proof
+	return nil
+}
+
+// Verify checks that the transaction is included in the collation, which must
+// belong to the source shard, and that the envelope's proof links the first
+// chunk of the transaction to the collation's chunk root.
+func (x *CrossShardTx) Verify(collation *Collation) bool {
+	if collation == nil || collation.Header() == nil || collation.Header().ChunkRoot() == nil {
+		return false
+	}
+	if x.SourceShardID == nil || !bigIntEqual(collation.Header().ShardID(), x.SourceShardID) {
+		return false
+	}
+	index, err := x.firstChunk(collation)
+	if err != nil {
+		return false
+	}
+
+	// The chunk must hold the start of the transaction's encoding, or the proof
+	// would only show that some other part of the body is in the collation.
+	encoded, err := rlp.EncodeToBytes(x.Tx)
+	if err != nil {
+		return false
+	}
+	chunk := ChunksFromBody(collation.Body()).chunk(index)
+	prefix := encoded
+	if len(prefix) > chunkDataSize {
+		prefix = prefix[:chunkDataSize]
+	}
+	if !bytes.Equal(chunk[1:1+len(prefix)], prefix) {
+		return false
+	}
+
+	proof := make([][]byte, len(x.Proof))
+	for i, sibling := range x.Proof {
+		proof[i] = sibling.Bytes()
+	}
+	return VerifyChunkProof(*collation.Header().ChunkRoot(), index, chunk, proof)
+}
+
+// firstChunk returns the index of the body chunk the transaction's blob starts
+// in. Every blob starts a new chunk, so the index is the number of chunks taken
+// by the transactions before it.
+func (x *CrossShardTx) firstChunk(collation *Collation) (int, error) {
+	if x.Tx == nil {
+		return 0, errors.New("cross shard transaction has no transaction")
+	}
+	_, txIndex, ok := collation.TransactionByHash(x.Tx.Hash())
+	if !ok {
+		return 0, fmt.Errorf("transaction %s is not in the collation", x.Tx.Hash().Hex())
+	}
+	index := 0
+	for _, tx := range collation.Transactions()[:txIndex] {
+		encoded, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			return 0, fmt.Errorf("could not encode transaction %s: %v", tx.Hash().Hex(), err)
+		}
+		index += (len(encoded) + chunkDataSize - 1) / chunkDataSize
+	}
+	if index >= ChunksFromBody(collation.Body()).Len() {
+		return 0, fmt.Errorf("transaction %s is not in the collation body", x.Tx.Hash().Hex())
+	}
+	return index, nil
+}
+
+// Encode RLP encodes the cross shard transaction to be sent over the wire.
+func (x *CrossShardTx) Encode() ([]byte, error) {
+	if x.SourceShardID == nil || x.DestShardID == nil || x.Tx == nil {
+		return nil, errors.New("cross shard transaction needs source and destination shardIDs and a transaction")
+	}
+	return rlp.EncodeToBytes(x)
+}
+
+// DecodeCrossShardTx decodes an RLP encoded cross shard transaction.
+func DecodeCrossShardTx(data []byte) (*CrossShardTx, error) {
+	x := &CrossShardTx{}
+	if err := rlp.DecodeBytes(data, x); err != nil {
+		return nil, fmt.Errorf("could not decode cross shard transaction: %v", err)
+	}
+	return x, nil
+}
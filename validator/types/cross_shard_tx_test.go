@@ -0,0 +1,97 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCrossShardTx_ProveVerify(t *testing.T) {
+	c := makeStoredCollation(t, 1, 10)
+	for i, tx := range c.Transactions() {
+		x := NewCrossShardTx(tx, big.NewInt(1), big.NewInt(2))
+		if x.Verify(c) {
+			t.Errorf("transaction %d should not verify without a proof", i)
+		}
+		if err := x.Prove(c); err != nil {
+			t.Fatalf("could not prove transaction %d: %v", i, err)
+		}
+		if !x.Verify(c) {
+			t.Errorf("transaction %d failed verification", i)
+		}
+	}
+}
+
+func TestCrossShardTx_VerifyInvalid(t *testing.T) {
+	c := makeStoredCollation(t, 1, 10)
+	tx := c.Transactions()[1]
+	x := NewCrossShardTx(tx, big.NewInt(1), big.NewInt(2))
+	if err := x.Prove(c); err != nil {
+		t.Fatalf("could not prove transaction: %v", err)
+	}
+
+	wrongSource := NewCrossShardTx(tx, big.NewInt(3), big.NewInt(2))
+	wrongSource.Proof = x.Proof
+	if wrongSource.Verify(c) {
+		t.Errorf("transaction should not verify against a collation of another shard")
+	}
+
+	tampered := NewCrossShardTx(tx, big.NewInt(1), big.NewInt(2))
+	tampered.Proof = append([]common.Hash{}, x.Proof...)
+	tampered.Proof[0][0] ^= 0xff
+	if tampered.Verify(c) {
+		t.Errorf("transaction should not verify with a tampered proof")
+	}
+
+	other := makeStoredCollation(t, 1, 11)
+	if x.Verify(other) {
+		t.Errorf("transaction should not verify against a collation that does not include it")
+	}
+	missing := NewCrossShardTx(makeRandomTransactions(1)[0], big.NewInt(1), big.NewInt(2))
+	if err := missing.Prove(c); err == nil {
+		t.Errorf("proving a transaction outside the collation should fail")
+	}
+
+	stale := makeStoredCollation(t, 1, 10)
+	stale.transactions = c.Transactions()
+	if x.Verify(stale) {
+		t.Errorf("transaction should not verify against a body that does not include it")
+	}
+	if x.Verify(nil) {
+		t.Errorf("transaction should not verify without a collation")
+	}
+}
+
+func TestCrossShardTx_EncodeDecode(t *testing.T) {
+	c := makeStoredCollation(t, 1, 10)
+	x := NewCrossShardTx(c.Transactions()[2], big.NewInt(1), big.NewInt(2))
+	if err := x.Prove(c); err != nil {
+		t.Fatalf("could not prove transaction: %v", err)
+	}
+
+	encoded, err := x.Encode()
+	if err != nil {
+		t.Fatalf("could not encode cross shard transaction: %v", err)
+	}
+	decoded, err := DecodeCrossShardTx(encoded)
+	if err != nil {
+		t.Fatalf("could not decode cross shard transaction: %v", err)
+	}
+	if decoded.SourceShardID.Cmp(x.SourceShardID) != 0 || decoded.DestShardID.Cmp(x.DestShardID) != 0 {
+		t.Errorf("decoded shardIDs incorrect. want=%v,%v. got=%v,%v", x.SourceShardID, x.DestShardID, decoded.SourceShardID, decoded.DestShardID)
+	}
+	if decoded.Tx.Hash() != x.Tx.Hash() {
+		t.Errorf("decoded transaction incorrect. want=%v. got=%v", x.Tx.Hash().Hex(), decoded.Tx.Hash().Hex())
+	}
+	if !decoded.Verify(c) {
+		t.Errorf("decoded cross shard transaction failed verification")
+	}
+
+	if _, err := NewCrossShardTx(nil, big.NewInt(1), big.NewInt(2)).Encode(); err == nil {
+		t.Errorf("encoding without a transaction should fail")
+	}
+	if _, err := DecodeCrossShardTx([]byte{0xc1}); err == nil {
+		t.Errorf("decoding malformed data should fail")
+	}
+}
@@ -0,0 +1,84 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// finalityKey identifies a shard block by its shardID and period.
+type finalityKey struct {
+	shardID string
+	period  string
+}
+
+// FinalityTracker counts the attestations of notaries to shard blocks. A
+// shard block is finalized once at least two thirds of the committee serving
+// the shard in its period attested to it, with the committees taken from a
+// CommitteeRotationSchedule.
+type FinalityTracker struct {
+	schedule     *CommitteeRotationSchedule
+	attestations map[finalityKey]map[common.Address]bool
+	lock         sync.RWMutex
+}
+
+// NewFinalityTracker creates a FinalityTracker checking attestations against
+// the committees of the schedule.
+func NewFinalityTracker(schedule *CommitteeRotationSchedule) *FinalityTracker {
+	return &FinalityTracker{
+		schedule:     schedule,
+		attestations: make(map[finalityKey]map[common.Address]bool),
+	}
+}
+
+// AddAttestation records the notary's attestation to the shard block of the
+// period. The notary must be a member of the shard's committee in the period
+// and can only attest once.
+func (f *FinalityTracker) AddAttestation(shardID *big.Int, period *big.Int, notaryAddr common.Address) error {
+	committee, err := f.schedule.Committee(shardID, period)
+	if err != nil {
+		return fmt.Errorf("could not get committee: %w", err)
+	}
+	if _, ok := NewCommitteeLookup(committee).Index(notaryAddr); !ok {
+		return fmt.Errorf("notary %s is not in the committee of shard %v in period %v", notaryAddr.Hex(), shardID, period)
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	key := finalityKey{shardID: shardID.String(), period: period.String()}
+	attesters, ok := f.attestations[key]
+	if !ok {
+		attesters = make(map[common.Address]bool)
+		f.attestations[key] = attesters
+	}
+	if attesters[notaryAddr] {
+		return fmt.Errorf("notary %s already attested to shard %v in period %v", notaryAddr.Hex(), shardID, period)
+	}
+	attesters[notaryAddr] = true
+	return nil
+}
+
+// AttestationCount returns the number of notaries that attested to the shard
+// block of the period.
+func (f *FinalityTracker) AttestationCount(shardID *big.Int, period *big.Int) int {
+	if shardID == nil || period == nil {
+		return 0
+	}
+
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return len(f.attestations[finalityKey{shardID: shardID.String(), period: period.String()}])
+}
+
+// IsFinalized reports whether at least two thirds of the shard's committee in
+// the period attested to the shard block.
+func (f *FinalityTracker) IsFinalized(shardID *big.Int, period *big.Int) bool {
+	committee, err := f.schedule.Committee(shardID, period)
+	if err != nil || len(committee) == 0 {
+		return false
+	}
+	return 3*f.AttestationCount(shardID, period) >= 2*len(committee)
+}
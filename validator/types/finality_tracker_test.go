@@ -0,0 +1,76 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// newTestFinalityTracker creates a tracker whose committees hold all of n
+// validators.
+func newTestFinalityTracker(t *testing.T, n int) *FinalityTracker {
+	schedule, err := NewCommitteeRotationSchedule(makeValidators(n), common.HexToHash("0x01"), ShardConfig{CommitteeRotationPeriod: 4}, 4)
+	if err != nil {
+		t.Fatalf("could not create rotation schedule: %v", err)
+	}
+	return NewFinalityTracker(schedule)
+}
+
+func TestFinalityTracker_TwoThirdsThreshold(t *testing.T) {
+	tracker := newTestFinalityTracker(t, 9)
+	shardID, period := big.NewInt(1), big.NewInt(5)
+	committee, err := tracker.schedule.Committee(shardID, period)
+	if err != nil {
+		t.Fatalf("could not get committee: %v", err)
+	}
+	if len(committee) != 9 {
+		t.Fatalf("committee size incorrect. want=%d. got=%d", 9, len(committee))
+	}
+
+	// 5 of 9 is short of two thirds, 6 of 9 is exactly two thirds.
+	for i, notary := range committee[:6] {
+		if tracker.IsFinalized(shardID, period) {
+			t.Errorf("shard block should not be finalized with %d of %d attestations", i, len(committee))
+		}
+		if err := tracker.AddAttestation(shardID, period, notary); err != nil {
+			t.Fatalf("could not add attestation: %v", err)
+		}
+		if got := tracker.AttestationCount(shardID, period); got != i+1 {
+			t.Errorf("attestation count incorrect. want=%d. got=%d", i+1, got)
+		}
+	}
+	if !tracker.IsFinalized(shardID, period) {
+		t.Errorf("shard block should be finalized with exactly two thirds of the attestations")
+	}
+
+	// attestations count per shard and period.
+	if tracker.IsFinalized(big.NewInt(2), period) || tracker.IsFinalized(shardID, big.NewInt(4)) {
+		t.Errorf("attestations should not finalize other shards or periods")
+	}
+}
+
+func TestFinalityTracker_AddAttestationInvalid(t *testing.T) {
+	tracker := newTestFinalityTracker(t, 6)
+	shardID, period := big.NewInt(0), big.NewInt(0)
+	notary := makeValidators(1)[0]
+
+	if err := tracker.AddAttestation(shardID, period, notary); err != nil {
+		t.Fatalf("could not add attestation: %v", err)
+	}
+	if err := tracker.AddAttestation(shardID, period, notary); err == nil {
+		t.Errorf("attesting twice should fail")
+	}
+	if err := tracker.AddAttestation(shardID, period, common.HexToAddress("0xdead")); err == nil {
+		t.Errorf("attesting from outside the committee should fail")
+	}
+	if err := tracker.AddAttestation(shardID, big.NewInt(-1), notary); err == nil {
+		t.Errorf("attesting to a negative period should fail")
+	}
+	if got := tracker.AttestationCount(shardID, period); got != 1 {
+		t.Errorf("only valid attestations should count. want=%d. got=%d", 1, got)
+	}
+	if tracker.IsFinalized(nil, period) {
+		t.Errorf("shard block without shardID should not be finalized")
+	}
+}
@@ -0,0 +1,59 @@
+package types
+
+import "bytes"
+
+// SelectCanonicalCollation picks the canonical collation among two valid
+// collations for the same shardID and period. The collation with the
+// lexicographically smaller header hash wins, which is a deterministic
+// tie-breaker every node agrees on. It is a placeholder until a GHOST-based
+// fork-choice rule that weighs the collations building on each candidate is
+// implemented. A nil collation always loses.
+func SelectCanonicalCollation(a, b *Collation) *Collation {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	hashA, hashB := a.Header().SignedHash(), b.Header().SignedHash()
+	if bytes.Compare(hashB[:], hashA[:]) < 0 {
+		return b
+	}
+	return a
+}
+
+// ForkChoice applies SelectCanonicalCollation across the collations, returning
+// nil if there are none.
+func ForkChoice(collations []*Collation) *Collation {
+	var canonical *Collation
+	for _, c := range collations {
+		canonical = SelectCanonicalCollation(canonical, c)
+	}
+	return canonical
+}
+
+// CompareCollations orders collations by shardID, then period, then signed
+// header hash, returning -1, 0 or 1 like bytes.Compare. Collations of the same
+// shard and period are thus ordered the way SelectCanonicalCollation picks among
+// them, and a slice sorted with it lists the canonical candidate of every shard
+// and period first.
+func CompareCollations(a, b *Collation) int {
+	if cmp := a.Header().ShardID().Cmp(b.Header().ShardID()); cmp != 0 {
+		return cmp
+	}
+	if cmp := a.Header().Period().Cmp(b.Header().Period()); cmp != 0 {
+		return cmp
+	}
+	hashA, hashB := a.Header().SignedHash(), b.Header().SignedHash()
+	return bytes.Compare(hashA[:], hashB[:])
+}
+
+// ByPeriod implements sort.Interface, ordering collations by ascending period
+// only. Use sort.Stable to keep the existing order of collations sharing a period.
+type ByPeriod []*Collation
+
+func (p ByPeriod) Len() int      { return len(p) }
+func (p ByPeriod) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p ByPeriod) Less(i, j int) bool {
+	return p[i].Header().Period().Cmp(p[j].Header().Period()) < 0
+}
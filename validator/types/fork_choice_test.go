@@ -0,0 +1,120 @@
+package types
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+)
+
+func TestSelectCanonicalCollation(t *testing.T) {
+	a := makeStoredCollation(t, 1, 10)
+	b := makeStoredCollation(t, 1, 10)
+	hashA, hashB := a.Header().SignedHash(), b.Header().SignedHash()
+	smaller := a
+	if bytes.Compare(hashB[:], hashA[:]) < 0 {
+		smaller = b
+	}
+
+	if got := SelectCanonicalCollation(a, b); got != smaller {
+		t.Errorf("canonical collation incorrect. want=%v. got=%v", smaller.Header().SignedHash().Hex(), got.Header().SignedHash().Hex())
+	}
+	if got := SelectCanonicalCollation(b, a); got != smaller {
+		t.Errorf("canonical collation should not depend on the argument order. want=%v. got=%v", smaller.Header().SignedHash().Hex(), got.Header().SignedHash().Hex())
+	}
+	if got := SelectCanonicalCollation(a, nil); got != a {
+		t.Errorf("a nil collation should never be canonical")
+	}
+	if got := SelectCanonicalCollation(nil, b); got != b {
+		t.Errorf("a nil collation should never be canonical")
+	}
+}
+
+func TestForkChoice(t *testing.T) {
+	if ForkChoice(nil) != nil {
+		t.Errorf("fork choice without collations should return nil")
+	}
+
+	collations := make([]*Collation, 8)
+	smallest := 0
+	for i := range collations {
+		collations[i] = makeStoredCollation(t, 1, 10)
+		hash, min := collations[i].Header().SignedHash(), collations[smallest].Header().SignedHash()
+		if bytes.Compare(hash[:], min[:]) < 0 {
+			smallest = i
+		}
+	}
+	if got := ForkChoice(collations); got != collations[smallest] {
+		t.Errorf("fork choice incorrect. want=%v. got=%v", collations[smallest].Header().SignedHash().Hex(), got.Header().SignedHash().Hex())
+	}
+
+	reversed := make([]*Collation, len(collations))
+	for i, c := range collations {
+		reversed[len(collations)-1-i] = c
+	}
+	if got := ForkChoice(reversed); got != collations[smallest] {
+		t.Errorf("fork choice should not depend on the collation order")
+	}
+}
+
+func TestCompareCollations(t *testing.T) {
+	a := makeStoredCollation(t, 1, 10)
+	b := makeStoredCollation(t, 1, 10)
+	hashA, hashB := a.Header().Hash(), b.Header().Hash()
+
+	tests := []struct {
+		a, b *Collation
+		want int
+	}{
+		{a: makeStoredCollation(t, 1, 10), b: makeStoredCollation(t, 2, 5), want: -1},
+		{a: makeStoredCollation(t, 2, 5), b: makeStoredCollation(t, 1, 10), want: 1},
+		{a: makeStoredCollation(t, 1, 5), b: makeStoredCollation(t, 1, 10), want: -1},
+		{a: makeStoredCollation(t, 1, 10), b: makeStoredCollation(t, 1, 5), want: 1},
+		{a: a, b: b, want: bytes.Compare(hashA[:], hashB[:])},
+		{a: a, b: a, want: 0},
+	}
+	for _, tt := range tests {
+		if got := CompareCollations(tt.a, tt.b); got != tt.want {
+			t.Errorf("comparison incorrect. want=%d. got=%d", tt.want, got)
+		}
+	}
+	canonical, other := a, b
+	if SelectCanonicalCollation(a, b) == b {
+		canonical, other = b, a
+	}
+	if CompareCollations(canonical, other) != -1 {
+		t.Errorf("the canonical collation should compare as the smaller one")
+	}
+}
+
+func TestCompareCollations_Sort(t *testing.T) {
+	collations := []*Collation{
+		makeStoredCollation(t, 2, 3),
+		makeStoredCollation(t, 1, 7),
+		makeStoredCollation(t, 2, 1),
+		makeStoredCollation(t, 1, 3),
+		makeStoredCollation(t, 3, 1),
+		makeStoredCollation(t, 1, 3),
+	}
+
+	sorted := append([]*Collation{}, collations...)
+	sort.Slice(sorted, func(i, j int) bool { return CompareCollations(sorted[i], sorted[j]) < 0 })
+	for i := 1; i < len(sorted); i++ {
+		if CompareCollations(sorted[i-1], sorted[i]) > 0 {
+			t.Errorf("collations %d and %d out of order", i-1, i)
+		}
+	}
+	if sorted[0].Header().ShardID().Int64() != 1 || sorted[len(sorted)-1].Header().ShardID().Int64() != 3 {
+		t.Errorf("collations should be sorted by shardID first")
+	}
+
+	byPeriod := append([]*Collation{}, collations...)
+	sort.Stable(ByPeriod(byPeriod))
+	// Collations sharing a period keep their original relative order.
+	want := []*Collation{collations[2], collations[4], collations[0], collations[3], collations[5], collations[1]}
+	for i := range want {
+		if byPeriod[i] != want[i] {
+			t.Errorf("collation %d out of order. want=shard %v period %v. got=shard %v period %v", i,
+				want[i].Header().ShardID(), want[i].Header().Period(), byPeriod[i].Header().ShardID(), byPeriod[i].Header().Period())
+		}
+	}
+}
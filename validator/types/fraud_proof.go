@@ -0,0 +1,45 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FraudProof challenges a collation over an invalid state transition. The
+// proposer commits to intermediate state roots in the chunks of the collation
+// body, and the proof points at the chunk holding InvalidStateRoot, a state
+// root the challenger claims does not follow from the preceding transactions.
+// Witness holds the sibling hashes proving the chunk against the collation's
+// chunk root, as returned by ChunkProof.
+type FraudProof struct {
+	CollationHash     common.Hash
+	InvalidChunkIndex int
+	Witness           []common.Hash
+	InvalidStateRoot  common.Hash
+}
+
+// ValidateFraudProof checks that the fraud proof is bound to the collation's
+// signed header hash, that the witness proves the chunk at InvalidChunkIndex of
+// the collation body against the header's chunk root, and that this chunk
+// commits to InvalidStateRoot, so that proposers are only challenged over state
+// roots they actually committed to. Re-executing the transactions to settle
+// the challenge is left to the verification game the proof opens.
+func ValidateFraudProof(proof *FraudProof, collation *Collation) bool {
+	if proof == nil || collation == nil || collation.Header() == nil || collation.Header().ChunkRoot() == nil {
+		return false
+	}
+	if proof.CollationHash != collation.Header().SignedHash() {
+		return false
+	}
+	chunk, err := collation.GetChunk(proof.InvalidChunkIndex)
+	if err != nil {
+		return false
+	}
+	if common.BytesToHash(chunk) != proof.InvalidStateRoot {
+		return false
+	}
+	witness := make([][]byte, len(proof.Witness))
+	for i, sibling := range proof.Witness {
+		witness[i] = sibling.Bytes()
+	}
+	return VerifyChunkProof(*collation.Header().ChunkRoot(), proof.InvalidChunkIndex, chunk, witness)
+}
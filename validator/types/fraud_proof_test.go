@@ -0,0 +1,92 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// makeStateRootCollation builds a collation whose body commits to the given
+// intermediate state roots, one per chunk.
+func makeStateRootCollation(t *testing.T, roots []common.Hash) *Collation {
+	body := make([]byte, 0, len(roots)*chunkSize)
+	for _, root := range roots {
+		body = append(body, root.Bytes()...)
+	}
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
+	header.WithBodyContentType(ContentTypeRawData)
+	c := NewCollation(header, body, nil)
+	c.CalculateChunkRoot()
+	header.AddSig(make([]byte, proposerSignatureLength))
+	return c
+}
+
+func makeFraudProof(t *testing.T, c *Collation, index int) *FraudProof {
+	witness, err := c.ChunkProof(index)
+	if err != nil {
+		t.Fatalf("could not generate chunk proof: %v", err)
+	}
+	chunk, err := c.GetChunk(index)
+	if err != nil {
+		t.Fatalf("could not get chunk: %v", err)
+	}
+	return &FraudProof{
+		CollationHash:     c.Header().SignedHash(),
+		InvalidChunkIndex: index,
+		Witness:           witness,
+		InvalidStateRoot:  common.BytesToHash(chunk),
+	}
+}
+
+func TestValidateFraudProof(t *testing.T) {
+	roots := []common.Hash{{0x01}, {0x02}, {0x03}, {0x04}, {0x05}}
+	c := makeStateRootCollation(t, roots)
+
+	for i := range roots {
+		if !ValidateFraudProof(makeFraudProof(t, c, i), c) {
+			t.Errorf("valid fraud proof of chunk %d failed validation", i)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(p *FraudProof)
+	}{
+		{
+			name:   "other collation",
+			tamper: func(p *FraudProof) { p.CollationHash = common.Hash{0xff} },
+		},
+		{
+			name:   "state root not committed to",
+			tamper: func(p *FraudProof) { p.InvalidStateRoot = common.Hash{0xff} },
+		},
+		{
+			name:   "wrong chunk index",
+			tamper: func(p *FraudProof) { p.InvalidChunkIndex = 3 },
+		},
+		{
+			name:   "out of range chunk index",
+			tamper: func(p *FraudProof) { p.InvalidChunkIndex = len(roots) },
+		},
+		{
+			name:   "tampered witness",
+			tamper: func(p *FraudProof) { p.Witness[0] = common.Hash{0xff} },
+		},
+		{
+			name:   "truncated witness",
+			tamper: func(p *FraudProof) { p.Witness = p.Witness[1:] },
+		},
+	}
+	for _, tt := range tests {
+		proof := makeFraudProof(t, c, 2)
+		tt.tamper(proof)
+		if ValidateFraudProof(proof, c) {
+			t.Errorf("%s: invalid fraud proof passed validation", tt.name)
+		}
+	}
+
+	if ValidateFraudProof(nil, c) || ValidateFraudProof(makeFraudProof(t, c, 0), nil) {
+		t.Errorf("validating without proof or collation should fail")
+	}
+}
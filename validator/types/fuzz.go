@@ -0,0 +1,18 @@
+//go:build gofuzz
+// +build gofuzz
+
+package types
+
+// FuzzDeserialize is a go-fuzz entry point feeding arbitrary collation bodies
+// into DeserializeBlobToTx, which must reject malformed input without panicking.
+//
+// Build and run it with:
+//
+//	go-fuzz-build github.com/prysmaticlabs/prysm/validator/types
+//	go-fuzz -bin=types-fuzz.zip -func=FuzzDeserialize -workdir=fuzz
+func FuzzDeserialize(data []byte) int {
+	if _, err := DeserializeBlobToTx(BytesBodyReader(data)); err != nil {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,170 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/prysmaticlabs/prysm/validator/params"
+)
+
+// deserializeCorpus returns the seed inputs for fuzzing DeserializeBlobToTx:
+// valid serialized collations and known edge cases.
+func deserializeCorpus(t *testing.T) [][]byte {
+	valid, err := SerializeTxToBlob(makeRandomTransactions(10))
+	if err != nil {
+		t.Fatalf("could not serialize transactions: %v", err)
+	}
+	single, err := SerializeTxToBlob(makeRandomTransactions(1))
+	if err != nil {
+		t.Fatalf("could not serialize transaction: %v", err)
+	}
+	skipEvm, err := serializeTxToBlob(makeRandomTransactions(3), true, params.DefaultCollationSizeLimit())
+	if err != nil {
+		t.Fatalf("could not serialize transactions: %v", err)
+	}
+
+	maxSize := make([]byte, params.DefaultCollationSizeLimit())
+	rand.New(rand.NewSource(1)).Read(maxSize)
+
+	return [][]byte{
+		valid,
+		single,
+		skipEvm,
+		{},
+		maxSize,
+		valid[:len(valid)-chunkSize], // truncated RLP in the final blob.
+		valid[:len(valid)-1],         // partial chunk.
+		single[:chunkSize],           // non-terminal chunk only.
+		make([]byte, chunkSize),      // a zero length terminal chunk.
+		append([]byte{0x1f}, make([]byte, chunkSize-1)...), // zero bytes instead of RLP.
+	}
+}
+
+func deserializeWithoutPanic(t *testing.T, data []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("DeserializeBlobToTx panicked on input %x: %v", data, r)
+		}
+	}()
+	DeserializeBlobToTx(BytesBodyReader(data))
+}
+
+func TestDeserializeBlobToTx_Corpus(t *testing.T) {
+	for _, data := range deserializeCorpus(t) {
+		deserializeWithoutPanic(t, data)
+	}
+}
+
+func TestDeserializeBlobToTx_Mutations(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, seed := range deserializeCorpus(t) {
+		if len(seed) == 0 || len(seed) > 1<<12 {
+			continue
+		}
+		for i := 0; i < 200; i++ {
+			data := append([]byte{}, seed...)
+			for j := 0; j < 1+r.Intn(4); j++ {
+				data[r.Intn(len(data))] = byte(r.Intn(256))
+			}
+			deserializeWithoutPanic(t, data)
+		}
+	}
+}
+
+// Generate implements quick.Generator. Integers range from nil up to several
+// words, while the chunk root, signatures and aggregate signature may be nil,
+// covering the corner cases of the RLP encoding.
+func (h *CollationHeader) Generate(r *rand.Rand, size int) reflect.Value {
+	randBig := func() *big.Int {
+		if r.Intn(4) == 0 {
+			return nil
+		}
+		b := make([]byte, r.Intn(size+40))
+		r.Read(b)
+		return new(big.Int).SetBytes(b)
+	}
+	randBytes := func(n int) []byte {
+		if r.Intn(4) == 0 {
+			return nil
+		}
+		b := make([]byte, n)
+		r.Read(b)
+		return b
+	}
+
+	data := collationHeaderData{
+		ShardID:                    randBig(),
+		Period:                     randBig(),
+		SkipEvmExecution:           r.Intn(2) == 0,
+		AggregateProposerSignature: randBytes(r.Intn(size + 1)),
+		BodyContentType:            uint8(r.Intn(int(ContentTypeCompressed) + 1)),
+	}
+	if r.Intn(4) != 0 {
+		root := common.BytesToHash(randBytes(common.HashLength))
+		data.ChunkRoot = &root
+	}
+	for i := r.Intn(4); i > 0; i-- {
+		addr := common.BytesToAddress(randBytes(common.AddressLength))
+		data.ProposerAddresses = append(data.ProposerAddresses, &addr)
+		data.ProposerSignatures = append(data.ProposerSignatures, randBytes(proposerSignatureLength))
+	}
+	return reflect.ValueOf(&CollationHeader{data: data})
+}
+
+// rlpBigIntEqual compares integers the way they survive RLP, which encodes a
+// nil integer like zero.
+func rlpBigIntEqual(a *big.Int, b *big.Int) bool {
+	if a == nil {
+		a = new(big.Int)
+	}
+	if b == nil {
+		b = new(big.Int)
+	}
+	return a.Cmp(b) == 0
+}
+
+func TestCollationRoundTrip(t *testing.T) {
+	roundTrip := func(h *CollationHeader) bool {
+		encoded, err := h.EncodeRLP()
+		if err != nil {
+			t.Logf("could not encode header %+v: %v", h.data, err)
+			return false
+		}
+		decoded := &CollationHeader{}
+		if err := rlp.DecodeBytes(encoded, decoded); err != nil {
+			t.Logf("could not decode header %+v: %v", h.data, err)
+			return false
+		}
+
+		want, got := h.data, decoded.data
+		var wantRoot, gotRoot common.Hash
+		if want.ChunkRoot != nil {
+			wantRoot = *want.ChunkRoot
+		}
+		if got.ChunkRoot != nil {
+			gotRoot = *got.ChunkRoot
+		}
+		equal := rlpBigIntEqual(want.ShardID, got.ShardID) &&
+			rlpBigIntEqual(want.Period, got.Period) &&
+			wantRoot == gotRoot &&
+			proposersEqual(&want, &got) &&
+			want.SkipEvmExecution == got.SkipEvmExecution &&
+			want.BodyContentType == got.BodyContentType &&
+			bytes.Equal(want.AggregateProposerSignature, got.AggregateProposerSignature)
+		if !equal {
+			t.Logf("decoded header does not match. want=%+v. got=%+v", want, got)
+		}
+		return equal
+	}
+
+	config := &quick.Config{MaxCount: 1000, Rand: rand.New(rand.NewSource(1))}
+	if err := quick.Check(roundTrip, config); err != nil {
+		t.Error(err)
+	}
+}
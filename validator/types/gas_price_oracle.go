@@ -0,0 +1,98 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+const (
+	// gasPriceOracleSamples is the number of latest collations per shard the
+	// gas price suggestion is based on.
+	gasPriceOracleSamples = 10
+	// gasPriceOraclePercentile is the percentile of the sampled gas prices
+	// suggested, high enough for transactions to be included in the next
+	// collations without overpaying.
+	gasPriceOraclePercentile = 60
+)
+
+// GasPriceOracle suggests gas prices for the transactions of a shard based on
+// the gas prices paid in its latest collations.
+type GasPriceOracle struct {
+	samples map[string]*gasPriceSamples
+	lock    sync.RWMutex
+}
+
+// gasPriceSamples is a ring buffer of the gas prices paid in the latest
+// collations of a shard, holding the prices of one collation per slot.
+type gasPriceSamples struct {
+	prices [gasPriceOracleSamples][]*big.Int
+	next   int
+	count  int
+}
+
+// NewGasPriceOracle creates a GasPriceOracle without samples.
+func NewGasPriceOracle() *GasPriceOracle {
+	return &GasPriceOracle{samples: make(map[string]*gasPriceSamples)}
+}
+
+// Record samples the gas prices of the collation's transactions, replacing the
+// samples of the oldest collation recorded for its shard once the buffer is full.
+// Transactions without gas price are sampled as paying zero.
+func (o *GasPriceOracle) Record(c *Collation) error {
+	if c == nil || c.Header() == nil || c.Header().ShardID() == nil {
+		return errors.New("collation has no shardID")
+	}
+	prices := make([]*big.Int, 0, len(c.Transactions()))
+	for i, tx := range c.Transactions() {
+		if tx == nil {
+			return fmt.Errorf("transaction %d of collation is nil", i)
+		}
+		price := tx.GasPrice()
+		if price == nil {
+			price = new(big.Int)
+		}
+		prices = append(prices, price)
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	key := c.Header().ShardID().String()
+	samples, ok := o.samples[key]
+	if !ok {
+		samples = &gasPriceSamples{}
+		o.samples[key] = samples
+	}
+	samples.prices[samples.next] = prices
+	samples.next = (samples.next + 1) % gasPriceOracleSamples
+	if samples.count < gasPriceOracleSamples {
+		samples.count++
+	}
+	return nil
+}
+
+// Suggest returns the 60th percentile of the gas prices paid in the last 10
+// collations recorded for the shard.
+func (o *GasPriceOracle) Suggest(shardID *big.Int) (*big.Int, error) {
+	if shardID == nil {
+		return nil, errors.New("no shardID provided")
+	}
+
+	o.lock.RLock()
+	var prices []*big.Int
+	if samples, ok := o.samples[shardID.String()]; ok {
+		for _, collationPrices := range samples.prices[:samples.count] {
+			prices = append(prices, collationPrices...)
+		}
+	}
+	o.lock.RUnlock()
+
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("no gas prices recorded for shard %v", shardID)
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Cmp(prices[j]) < 0 })
+	return new(big.Int).Set(prices[(len(prices)-1)*gasPriceOraclePercentile/100]), nil
+}
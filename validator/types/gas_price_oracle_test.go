@@ -0,0 +1,78 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// makeGasPriceCollation builds a collation of the shard with a transaction per gas price.
+func makeGasPriceCollation(t *testing.T, shardID int64, prices ...int64) *Collation {
+	txs := make([]*gethTypes.Transaction, len(prices))
+	for i, price := range prices {
+		txs[i] = gethTypes.NewTransaction(uint64(i), common.HexToAddress("0x0"), nil, 21000, big.NewInt(price), nil)
+	}
+	return NewCollation(newTestCollationHeader(t, big.NewInt(shardID), nil, big.NewInt(1)), nil, txs)
+}
+
+func TestGasPriceOracle_Suggest(t *testing.T) {
+	oracle := NewGasPriceOracle()
+	// Prices 1 to 100 spread over 10 collations.
+	for i := int64(0); i < 10; i++ {
+		prices := make([]int64, 10)
+		for j := range prices {
+			prices[j] = 10*int64(j) + i + 1
+		}
+		if err := oracle.Record(makeGasPriceCollation(t, 1, prices...)); err != nil {
+			t.Fatalf("could not record collation: %v", err)
+		}
+	}
+
+	suggested, err := oracle.Suggest(big.NewInt(1))
+	if err != nil {
+		t.Fatalf("could not suggest gas price: %v", err)
+	}
+	// The 60th percentile of 1 to 100 is 60, within 5%.
+	if suggested.Cmp(big.NewInt(57)) < 0 || suggested.Cmp(big.NewInt(63)) > 0 {
+		t.Errorf("suggested gas price incorrect. want=%d±5%%. got=%v", 60, suggested)
+	}
+
+	if _, err := oracle.Suggest(big.NewInt(2)); err == nil {
+		t.Errorf("suggesting a gas price without samples should fail")
+	}
+}
+
+func TestGasPriceOracle_RingBuffer(t *testing.T) {
+	oracle := NewGasPriceOracle()
+	for i := 0; i < 10; i++ {
+		if err := oracle.Record(makeGasPriceCollation(t, 1, 1000)); err != nil {
+			t.Fatalf("could not record collation: %v", err)
+		}
+	}
+	// Newer collations replace the oldest samples.
+	for i := 0; i < 10; i++ {
+		if err := oracle.Record(makeGasPriceCollation(t, 1, 10, 20)); err != nil {
+			t.Fatalf("could not record collation: %v", err)
+		}
+	}
+	if err := oracle.Record(makeGasPriceCollation(t, 2, 5000)); err != nil {
+		t.Fatalf("could not record collation: %v", err)
+	}
+
+	suggested, err := oracle.Suggest(big.NewInt(1))
+	if err != nil {
+		t.Fatalf("could not suggest gas price: %v", err)
+	}
+	if suggested.Cmp(big.NewInt(20)) != 0 {
+		t.Errorf("suggested gas price incorrect. want=%d. got=%v", 20, suggested)
+	}
+	if suggested, _ := oracle.Suggest(big.NewInt(2)); suggested.Cmp(big.NewInt(5000)) != 0 {
+		t.Errorf("shards should be sampled separately. want=%d. got=%v", 5000, suggested)
+	}
+
+	if err := oracle.Record(nil); err == nil {
+		t.Errorf("recording a nil collation should fail")
+	}
+}
@@ -0,0 +1,170 @@
+package types
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Stage is a step of importing a collation, which can be undone if a later
+// step fails.
+type Stage interface {
+	// Process runs the stage on the collation.
+	Process(c *Collation) error
+	// Rollback undoes the effects of a successful Process on the collation.
+	Rollback(c *Collation) error
+}
+
+// ImportPipeline imports collations through a sequence of stages, so that a
+// collation is either fully imported or not imported at all. Collations are
+// imported one at a time.
+type ImportPipeline struct {
+	stages []Stage
+	lock   sync.Mutex
+}
+
+// NewImportPipeline creates an ImportPipeline running the stages in order.
+func NewImportPipeline(stages ...Stage) *ImportPipeline {
+	return &ImportPipeline{stages: stages}
+}
+
+// Execute runs every stage on the collation in order. If a stage fails, the
+// stages that already processed the collation are rolled back in reverse order
+// and the error of the failed stage is returned. Rollback failures are logged,
+// as the rest of the stages are still rolled back.
+func (p *ImportPipeline) Execute(c *Collation) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for i, stage := range p.stages {
+		if err := stage.Process(c); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				if rollbackErr := p.stages[j].Rollback(c); rollbackErr != nil {
+					log.Errorf("Could not roll back stage %d of collation import: %v", j, rollbackErr)
+				}
+			}
+			return fmt.Errorf("collation import failed at stage %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ValidateStage checks the collation's header, signature, chunk root and body
+// size. It has nothing to roll back.
+type ValidateStage struct {
+	config ShardConfig
+}
+
+// NewValidateStage creates a ValidateStage checking collations against the config.
+func NewValidateStage(config ShardConfig) *ValidateStage {
+	return &ValidateStage{config: config}
+}
+
+// Process validates the collation.
+func (s *ValidateStage) Process(c *Collation) error {
+	return validateCollationContents(c, int64(s.config.shardCount()))
+}
+
+// Rollback is a no-op, as validating has no effects.
+func (s *ValidateStage) Rollback(c *Collation) error {
+	return nil
+}
+
+// StoreHeaderStage saves the collation's header to a shard.
+type StoreHeaderStage struct {
+	shard *Shard
+	// saved is the hash of the header saved by the last Process, or the zero
+	// hash if the header was already stored, in which case Rollback keeps it.
+	saved common.Hash
+	lock  sync.Mutex
+}
+
+// NewStoreHeaderStage creates a StoreHeaderStage saving headers to the shard.
+func NewStoreHeaderStage(shard *Shard) *StoreHeaderStage {
+	return &StoreHeaderStage{shard: shard}
+}
+
+// Process saves the collation's header.
+func (s *StoreHeaderStage) Process(c *Collation) error {
+	if err := s.shard.ValidateShardID(c.Header()); err != nil {
+		return err
+	}
+	hash := c.Header().SignedHash()
+	exists, err := s.shard.shardDB.Has(hash.Bytes())
+	if err != nil {
+		return fmt.Errorf("could not look up header: %v", err)
+	}
+	if err := s.shard.SaveHeader(c.Header()); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.saved = common.Hash{}
+	if !exists {
+		s.saved = hash
+	}
+	return nil
+}
+
+// Rollback deletes the collation's header if the last Process saved it.
+func (s *StoreHeaderStage) Rollback(c *Collation) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	hash := c.Header().SignedHash()
+	if s.saved != hash {
+		return nil
+	}
+	s.saved = common.Hash{}
+	return s.shard.deleteHeader(&hash)
+}
+
+// StoreBodyStage saves the collation's body to a shard and marks it available.
+type StoreBodyStage struct {
+	shard *Shard
+	// saved is the chunk root of the body saved by the last Process, or the
+	// zero hash if the body was already stored, in which case Rollback keeps it.
+	saved common.Hash
+	lock  sync.Mutex
+}
+
+// NewStoreBodyStage creates a StoreBodyStage saving bodies to the shard.
+func NewStoreBodyStage(shard *Shard) *StoreBodyStage {
+	return &StoreBodyStage{shard: shard}
+}
+
+// Process saves the collation's body.
+func (s *StoreBodyStage) Process(c *Collation) error {
+	chunkRoot := chunkRootFromBody(c.Body())
+	exists, err := s.shard.shardDB.Has(chunkRoot.Bytes())
+	if err != nil {
+		return fmt.Errorf("could not look up body: %v", err)
+	}
+	if err := s.shard.SaveBody(c.Body()); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.saved = common.Hash{}
+	if !exists {
+		s.saved = chunkRoot
+	}
+	return nil
+}
+
+// Rollback deletes the collation's body and its availability if the last
+// Process saved it.
+func (s *StoreBodyStage) Rollback(c *Collation) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	chunkRoot := chunkRootFromBody(c.Body())
+	if s.saved != chunkRoot {
+		return nil
+	}
+	s.saved = common.Hash{}
+	return s.shard.deleteBody(&chunkRoot)
+}
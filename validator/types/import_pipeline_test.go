@@ -0,0 +1,133 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	sharedDB "github.com/prysmaticlabs/prysm/shared/database"
+)
+
+// recordingStage records the stages processed and rolled back into a shared log.
+type recordingStage struct {
+	name string
+	log  *[]string
+	err  error
+}
+
+func (s *recordingStage) Process(c *Collation) error {
+	*s.log = append(*s.log, "process "+s.name)
+	return s.err
+}
+
+func (s *recordingStage) Rollback(c *Collation) error {
+	*s.log = append(*s.log, "rollback "+s.name)
+	return nil
+}
+
+func TestImportPipeline_RollbackOrder(t *testing.T) {
+	var calls []string
+	failure := errors.New("stage failed")
+	pipeline := NewImportPipeline(
+		&recordingStage{name: "a", log: &calls},
+		&recordingStage{name: "b", log: &calls},
+		&recordingStage{name: "c", log: &calls, err: failure},
+		&recordingStage{name: "d", log: &calls},
+	)
+
+	if err := pipeline.Execute(makeStoredCollation(t, 1, 1)); !errors.Is(err, failure) {
+		t.Errorf("import error incorrect. want=%v. got=%v", failure, err)
+	}
+	want := []string{"process a", "process b", "process c", "rollback b", "rollback a"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("stage calls incorrect. want=%v. got=%v", want, calls)
+	}
+}
+
+func newImportShard(t *testing.T) (*Shard, *ImportPipeline) {
+	shard := NewShard(big.NewInt(1), sharedDB.NewKVStore())
+	return shard, NewImportPipeline(NewValidateStage(ShardConfig{}), NewStoreHeaderStage(shard), NewStoreBodyStage(shard))
+}
+
+func TestImportPipeline_Execute(t *testing.T) {
+	shard, pipeline := newImportShard(t)
+	c := makeStoredCollation(t, 1, 1)
+	if err := pipeline.Execute(c); err != nil {
+		t.Fatalf("could not import collation: %v", err)
+	}
+
+	hash := c.Header().SignedHash()
+	if header, err := shard.HeaderByHash(&hash); err != nil || header == nil {
+		t.Errorf("imported header should be saved. err=%v", err)
+	}
+	if available, err := shard.CheckAvailability(c.Header()); err != nil || !available {
+		t.Errorf("imported body should be available. available=%v, err=%v", available, err)
+	}
+
+	invalid := makeStoredCollation(t, 1, 2)
+	invalid.header.data.ProposerSignatures = nil
+	if err := pipeline.Execute(invalid); !errors.Is(err, ErrInvalidProposerSignature) {
+		t.Errorf("import error incorrect. want=%v. got=%v", ErrInvalidProposerSignature, err)
+	}
+}
+
+// failingBodyDB fails writes of collation bodies, so that importing fails after
+// the header was saved.
+type failingBodyDB struct {
+	*sharedDB.KVStore
+	body []byte
+}
+
+func (db *failingBodyDB) Put(k []byte, v []byte) error {
+	if bytes.Equal(v, db.body) {
+		return errors.New("disk full")
+	}
+	return db.KVStore.Put(k, v)
+}
+
+func TestImportPipeline_RollbackStores(t *testing.T) {
+	c := makeStoredCollation(t, 1, 1)
+	db := &failingBodyDB{KVStore: sharedDB.NewKVStore(), body: c.Body()}
+	shard := NewShard(big.NewInt(1), db)
+	pipeline := NewImportPipeline(NewValidateStage(ShardConfig{}), NewStoreHeaderStage(shard), NewStoreBodyStage(shard))
+
+	if err := pipeline.Execute(c); err == nil {
+		t.Fatalf("import should fail when the body cannot be saved")
+	}
+	hash := c.Header().SignedHash()
+	if has, _ := db.Has(hash.Bytes()); has {
+		t.Errorf("header should be rolled back after the body failed to save")
+	}
+
+	// A header stored before the import is kept when the import is rolled back.
+	if err := shard.SaveHeader(c.Header()); err != nil {
+		t.Fatalf("could not save header: %v", err)
+	}
+	if err := pipeline.Execute(c); err == nil {
+		t.Fatalf("import should fail when the body cannot be saved")
+	}
+	if has, _ := db.Has(hash.Bytes()); !has {
+		t.Errorf("header stored before the import should not be rolled back")
+	}
+}
+
+func TestStoreBodyStage_Rollback(t *testing.T) {
+	shard := NewShard(big.NewInt(1), sharedDB.NewKVStore())
+	stage := NewStoreBodyStage(shard)
+	c := makeStoredCollation(t, 1, 1)
+
+	if err := stage.Process(c); err != nil {
+		t.Fatalf("could not save body: %v", err)
+	}
+	if err := stage.Rollback(c); err != nil {
+		t.Fatalf("could not roll back body: %v", err)
+	}
+	if body, err := shard.BodyByChunkRoot(c.Header().ChunkRoot()); err == nil && len(body) != 0 {
+		t.Errorf("body should be deleted by the rollback")
+	}
+	if _, err := shard.CheckAvailability(c.Header()); err == nil {
+		t.Errorf("availability should be deleted by the rollback")
+	}
+}
@@ -0,0 +1,99 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrInvalidKeyRotation is returned when a key rotation is incomplete or not
+// signed by the old key.
+var ErrInvalidKeyRotation = errors.New("invalid key rotation")
+
+// KeyRotation announces that a notary assigned to a shard signs with NewKey
+// instead of OldKey from EffectivePeriod on. It is signed by the old key, so
+// that the shard assignment carries over to the new key.
+type KeyRotation struct {
+	OldKey          *ecdsa.PublicKey
+	NewKey          *ecdsa.PublicKey
+	ShardID         *big.Int
+	EffectivePeriod *big.Int
+	Signature       []byte
+}
+
+// Hash returns the keccak256 of the old and new keys, shardID and effective
+// period, which is what the old key signs. Keys are in uncompressed form and
+// numbers are 32 byte big endian.
+func (r *KeyRotation) Hash() common.Hash {
+	return crypto.Keccak256Hash(
+		crypto.FromECDSAPub(r.OldKey),
+		crypto.FromECDSAPub(r.NewKey),
+		common.LeftPadBytes(r.ShardID.Bytes(), 32),
+		common.LeftPadBytes(r.EffectivePeriod.Bytes(), 32),
+	)
+}
+
+// validate checks that the rotation has every field its hash covers.
+func (r *KeyRotation) validate() error {
+	if r.OldKey == nil || r.NewKey == nil {
+		return fmt.Errorf("rotation is missing a key: %w", ErrInvalidKeyRotation)
+	}
+	if r.ShardID == nil || r.ShardID.Sign() < 0 {
+		return fmt.Errorf("rotation has invalid shardID %v: %w", r.ShardID, ErrInvalidKeyRotation)
+	}
+	if r.EffectivePeriod == nil || r.EffectivePeriod.Sign() < 0 {
+		return fmt.Errorf("rotation has invalid effective period %v: %w", r.EffectivePeriod, ErrInvalidKeyRotation)
+	}
+	return nil
+}
+
+// SignRotation signs the rotation with the old key, which must be the private
+// key of the rotation's OldKey.
+func SignRotation(oldKey *ecdsa.PrivateKey, rotation *KeyRotation) error {
+	if err := rotation.validate(); err != nil {
+		return err
+	}
+	if crypto.PubkeyToAddress(oldKey.PublicKey) != crypto.PubkeyToAddress(*rotation.OldKey) {
+		return fmt.Errorf("signing key does not match the old key of the rotation: %w", ErrInvalidKeyRotation)
+	}
+	sig, err := crypto.Sign(rotation.Hash().Bytes(), oldKey)
+	if err != nil {
+		return fmt.Errorf("could not sign key rotation: %v", err)
+	}
+	rotation.Signature = sig
+	return nil
+}
+
+// VerifyRotation checks that the rotation is complete and signed by its old
+// key.
+func VerifyRotation(rotation *KeyRotation) bool {
+	if rotation == nil || rotation.validate() != nil || len(rotation.Signature) != 65 {
+		return false
+	}
+	signer, err := crypto.SigToPub(rotation.Hash().Bytes(), rotation.Signature)
+	if err != nil {
+		return false
+	}
+	return crypto.PubkeyToAddress(*signer) == crypto.PubkeyToAddress(*rotation.OldKey)
+}
+
+// ApplyRotation verifies the rotation and moves the old key's assignment to
+// the shard over to the new key from the effective period on.
+func ApplyRotation(tracker *ValidatorTracker, rotation *KeyRotation) error {
+	if !VerifyRotation(rotation) {
+		return fmt.Errorf("key rotation is not signed by the old key: %w", ErrInvalidKeyRotation)
+	}
+	oldNotary := crypto.PubkeyToAddress(*rotation.OldKey)
+	newNotary := crypto.PubkeyToAddress(*rotation.NewKey)
+	if oldNotary == newNotary {
+		return fmt.Errorf("rotation does not change the key of notary %s: %w", oldNotary.Hex(), ErrInvalidKeyRotation)
+	}
+	if err := tracker.rotate(oldNotary, newNotary, rotation.ShardID, rotation.EffectivePeriod); err != nil {
+		return fmt.Errorf("could not rotate key: %v", err)
+	}
+	return nil
+}
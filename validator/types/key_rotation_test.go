@@ -0,0 +1,144 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newTestRotation(t *testing.T, shardID, period int64) (*KeyRotation, *KeyRotation) {
+	keys := generateKeys(t, 2)
+	rotation := &KeyRotation{
+		OldKey:          &keys[0].PublicKey,
+		NewKey:          &keys[1].PublicKey,
+		ShardID:         big.NewInt(shardID),
+		EffectivePeriod: big.NewInt(period),
+	}
+	if err := SignRotation(keys[0], rotation); err != nil {
+		t.Fatalf("could not sign rotation: %v", err)
+	}
+	// the reverse rotation is signed by the key it rotates away from.
+	reverse := &KeyRotation{
+		OldKey:          &keys[1].PublicKey,
+		NewKey:          &keys[0].PublicKey,
+		ShardID:         big.NewInt(shardID),
+		EffectivePeriod: big.NewInt(period + 1),
+	}
+	if err := SignRotation(keys[1], reverse); err != nil {
+		t.Fatalf("could not sign rotation: %v", err)
+	}
+	return rotation, reverse
+}
+
+func TestSignRotation(t *testing.T) {
+	rotation, _ := newTestRotation(t, 1, 10)
+	if !VerifyRotation(rotation) {
+		t.Fatalf("signed rotation should verify")
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(r *KeyRotation)
+	}{
+		{name: "shardID", tamper: func(r *KeyRotation) { r.ShardID = big.NewInt(2) }},
+		{name: "effective period", tamper: func(r *KeyRotation) { r.EffectivePeriod = big.NewInt(11) }},
+		{name: "new key", tamper: func(r *KeyRotation) { r.NewKey = &generateKeys(t, 1)[0].PublicKey }},
+		{name: "old key", tamper: func(r *KeyRotation) { r.OldKey = &generateKeys(t, 1)[0].PublicKey }},
+		{name: "signature", tamper: func(r *KeyRotation) { r.Signature = r.Signature[:64] }},
+		{name: "missing key", tamper: func(r *KeyRotation) { r.NewKey = nil }},
+	}
+	for _, tt := range tests {
+		r := *rotation
+		tt.tamper(&r)
+		if VerifyRotation(&r) {
+			t.Errorf("rotation with tampered %s should not verify", tt.name)
+		}
+	}
+	if VerifyRotation(nil) {
+		t.Errorf("nil rotation should not verify")
+	}
+
+	otherKey := generateKeys(t, 1)[0]
+	if err := SignRotation(otherKey, &KeyRotation{OldKey: rotation.OldKey, NewKey: rotation.NewKey, ShardID: big.NewInt(1), EffectivePeriod: big.NewInt(10)}); !errors.Is(err, ErrInvalidKeyRotation) {
+		t.Errorf("signing with a key other than the old key should fail. want=%v. got=%v", ErrInvalidKeyRotation, err)
+	}
+}
+
+func TestApplyRotation(t *testing.T) {
+	rotation, reverse := newTestRotation(t, 1, 10)
+	oldNotary := crypto.PubkeyToAddress(*rotation.OldKey)
+	newNotary := crypto.PubkeyToAddress(*rotation.NewKey)
+
+	tracker := NewValidatorTracker()
+	if err := tracker.Assign(oldNotary, big.NewInt(1), big.NewInt(5)); err != nil {
+		t.Fatalf("could not assign notary: %v", err)
+	}
+	if err := ApplyRotation(tracker, rotation); err != nil {
+		t.Fatalf("could not apply rotation: %v", err)
+	}
+
+	tests := []struct {
+		notary string
+		period int64
+		ok     bool
+	}{
+		{notary: "old", period: 9, ok: true},
+		{notary: "old", period: 10},
+		{notary: "new", period: 9},
+		{notary: "new", period: 10, ok: true},
+	}
+	for _, tt := range tests {
+		addr := oldNotary
+		if tt.notary == "new" {
+			addr = newNotary
+		}
+		shardID, ok := tracker.ShardAt(addr, big.NewInt(tt.period))
+		if ok != tt.ok {
+			t.Errorf("assignment of the %s key in period %d incorrect. want=%v. got=%v", tt.notary, tt.period, tt.ok, ok)
+		}
+		if ok && shardID.Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("rotation should keep the shard assignment. want=%d. got=%v", 1, shardID)
+		}
+	}
+
+	if err := ApplyRotation(tracker, rotation); err == nil {
+		t.Errorf("applying a rotation twice should fail")
+	}
+	// keys are never reused, so rotating back to the old key fails.
+	if err := ApplyRotation(tracker, reverse); err == nil {
+		t.Errorf("rotating to a key that was assigned before should fail")
+	}
+}
+
+func TestApplyRotation_Invalid(t *testing.T) {
+	rotation, _ := newTestRotation(t, 1, 10)
+	oldNotary := crypto.PubkeyToAddress(*rotation.OldKey)
+
+	tracker := NewValidatorTracker()
+	if err := ApplyRotation(tracker, rotation); err == nil {
+		t.Errorf("rotating the key of an unassigned notary should fail")
+	}
+	if err := tracker.Assign(oldNotary, big.NewInt(2), big.NewInt(5)); err != nil {
+		t.Fatalf("could not assign notary: %v", err)
+	}
+	if err := ApplyRotation(tracker, rotation); err == nil {
+		t.Errorf("rotating the key of a notary assigned to another shard should fail")
+	}
+
+	unsigned := *rotation
+	unsigned.Signature = nil
+	if err := ApplyRotation(tracker, &unsigned); !errors.Is(err, ErrInvalidKeyRotation) {
+		t.Errorf("applying an unsigned rotation should fail. want=%v. got=%v", ErrInvalidKeyRotation, err)
+	}
+
+	early, _ := newTestRotation(t, 1, 5)
+	tracker = NewValidatorTracker()
+	if err := tracker.Assign(crypto.PubkeyToAddress(*early.OldKey), big.NewInt(1), big.NewInt(5)); err != nil {
+		t.Fatalf("could not assign notary: %v", err)
+	}
+	if err := ApplyRotation(tracker, early); err == nil {
+		t.Errorf("rotating in the assignment period should fail")
+	}
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	serializeDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name: "collation_serialize_duration_seconds",
+		Help: "Time spent serializing collation transactions into a body.",
+	})
+	serializeBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "collation_serialize_bytes",
+		Help: "Size in bytes of the last serialized collation body.",
+	})
+	deserializeDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name: "collation_deserialize_duration_seconds",
+		Help: "Time spent deserializing a collation body into transactions.",
+	})
+	chunkRootDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name: "collation_chunk_root_compute_duration_seconds",
+		Help: "Time spent computing the chunk root of a collation body.",
+	})
+)
+
+// RegisterMetrics registers the collation serialization metrics with the given
+// registerer. Metrics are recorded whether or not they are registered.
+func RegisterMetrics(reg prometheus.Registerer) error {
+	collectors := []prometheus.Collector{
+		serializeDuration,
+		serializeBytes,
+		deserializeDuration,
+		chunkRootDuration,
+	}
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// observeDuration records the time elapsed since start in the given histogram.
+func observeDuration(h prometheus.Histogram, start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gatherMetric returns the sample count of the named histogram, or the value of
+// the named gauge, from the registry.
+func gatherMetric(t *testing.T, reg *prometheus.Registry, name string) float64 {
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("could not gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		metric := family.GetMetric()[0]
+		if h := metric.GetHistogram(); h != nil {
+			return float64(h.GetSampleCount())
+		}
+		return metric.GetGauge().GetValue()
+	}
+	t.Fatalf("metric %s was not gathered", name)
+	return 0
+}
+
+func TestRegisterMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if err := RegisterMetrics(reg); err != nil {
+		t.Fatalf("could not register metrics: %v", err)
+	}
+	if err := RegisterMetrics(reg); err == nil {
+		t.Errorf("registering the metrics twice should fail")
+	}
+}
+
+func TestMetrics_Recorded(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if err := RegisterMetrics(reg); err != nil {
+		t.Fatalf("could not register metrics: %v", err)
+	}
+	c := makeStoredCollation(t, 1, 1)
+
+	serializeCount := gatherMetric(t, reg, "collation_serialize_duration_seconds")
+	body, err := c.Serialize()
+	if err != nil {
+		t.Fatalf("could not serialize collation: %v", err)
+	}
+	if got := gatherMetric(t, reg, "collation_serialize_duration_seconds"); got != serializeCount+1 {
+		t.Errorf("serialize duration was not observed. want=%v. got=%v", serializeCount+1, got)
+	}
+	if got := gatherMetric(t, reg, "collation_serialize_bytes"); got != float64(len(body)) {
+		t.Errorf("serialized bytes incorrect. want=%v. got=%v", len(body), got)
+	}
+
+	deserializeCount := gatherMetric(t, reg, "collation_deserialize_duration_seconds")
+	if _, err := DeserializeBlobToTx(BytesBodyReader(body)); err != nil {
+		t.Fatalf("could not deserialize collation body: %v", err)
+	}
+	if got := gatherMetric(t, reg, "collation_deserialize_duration_seconds"); got != deserializeCount+1 {
+		t.Errorf("deserialize duration was not observed. want=%v. got=%v", deserializeCount+1, got)
+	}
+
+	chunkRootCount := gatherMetric(t, reg, "collation_chunk_root_compute_duration_seconds")
+	c.CalculateChunkRoot()
+	if got := gatherMetric(t, reg, "collation_chunk_root_compute_duration_seconds"); got != chunkRootCount+1 {
+		t.Errorf("chunk root duration was not observed. want=%v. got=%v", chunkRootCount+1, got)
+	}
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceTracker records the shardID and period pairs each proposer submitted
+// collations for, rejecting duplicates to prevent replayed submissions.
+// Periods more than retention periods older than the latest period recorded
+// for a proposer and shard are evicted, and recording them is rejected.
+type NonceTracker struct {
+	retention *big.Int
+	entries   map[nonceKey]*proposerPeriods
+	lock      sync.RWMutex
+}
+
+// nonceKey identifies a proposer on a shard.
+type nonceKey struct {
+	proposer common.Address
+	shardID  string
+}
+
+// proposerPeriods holds the periods recorded for a proposer on a shard.
+type proposerPeriods struct {
+	last    *big.Int
+	periods map[string]*big.Int
+}
+
+// NewNonceTracker creates a NonceTracker keeping the last retention periods of
+// every proposer and shard.
+func NewNonceTracker(retention int64) *NonceTracker {
+	return &NonceTracker{
+		retention: big.NewInt(retention),
+		entries:   make(map[nonceKey]*proposerPeriods),
+	}
+}
+
+// Record marks the period as used by the proposer on the shard. It returns an
+// error if the tuple was recorded before or if the period is too old to be
+// tracked anymore.
+func (n *NonceTracker) Record(addr common.Address, shardID *big.Int, period *big.Int) error {
+	if shardID == nil || period == nil {
+		return errors.New("shardID and period are required")
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	key := nonceKey{proposer: addr, shardID: shardID.String()}
+	entry, ok := n.entries[key]
+	if !ok {
+		entry = &proposerPeriods{periods: make(map[string]*big.Int)}
+		n.entries[key] = entry
+	}
+	if entry.last != nil && period.Cmp(n.oldestRetained(entry.last)) < 0 {
+		return fmt.Errorf("period %v is older than the %v retained periods of proposer %s on shard %v", period, n.retention, addr.Hex(), shardID)
+	}
+	if _, ok := entry.periods[period.String()]; ok {
+		return fmt.Errorf("proposer %s already submitted a collation for shard %v in period %v", addr.Hex(), shardID, period)
+	}
+	entry.periods[period.String()] = new(big.Int).Set(period)
+
+	if entry.last == nil || period.Cmp(entry.last) > 0 {
+		entry.last = new(big.Int).Set(period)
+		oldest := n.oldestRetained(entry.last)
+		for k, p := range entry.periods {
+			if p.Cmp(oldest) < 0 {
+				delete(entry.periods, k)
+			}
+		}
+	}
+	return nil
+}
+
+// LastPeriod returns the latest period recorded for the proposer on the shard.
+func (n *NonceTracker) LastPeriod(addr common.Address, shardID *big.Int) (*big.Int, bool) {
+	if shardID == nil {
+		return nil, false
+	}
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	entry, ok := n.entries[nonceKey{proposer: addr, shardID: shardID.String()}]
+	if !ok || entry.last == nil {
+		return nil, false
+	}
+	return new(big.Int).Set(entry.last), true
+}
+
+// oldestRetained returns the oldest period still tracked given the latest one.
+func (n *NonceTracker) oldestRetained(last *big.Int) *big.Int {
+	return new(big.Int).Sub(last, n.retention)
+}
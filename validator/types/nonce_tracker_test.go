@@ -0,0 +1,92 @@
+package types
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestNonceTracker_Record(t *testing.T) {
+	tracker := NewNonceTracker(10)
+	proposer := common.HexToAddress("0x01")
+	other := common.HexToAddress("0x02")
+
+	if _, ok := tracker.LastPeriod(proposer, big.NewInt(1)); ok {
+		t.Errorf("no period should be recorded for a new proposer")
+	}
+	if err := tracker.Record(proposer, big.NewInt(1), big.NewInt(5)); err != nil {
+		t.Fatalf("could not record period: %v", err)
+	}
+	if err := tracker.Record(proposer, big.NewInt(1), big.NewInt(5)); err == nil {
+		t.Errorf("recording the same shardID and period twice should fail")
+	}
+	if err := tracker.Record(proposer, big.NewInt(2), big.NewInt(5)); err != nil {
+		t.Errorf("the same period should be recordable on another shard: %v", err)
+	}
+	if err := tracker.Record(other, big.NewInt(1), big.NewInt(5)); err != nil {
+		t.Errorf("the same period should be recordable by another proposer: %v", err)
+	}
+	if err := tracker.Record(proposer, big.NewInt(1), big.NewInt(3)); err != nil {
+		t.Errorf("an earlier period within the retention should be recordable: %v", err)
+	}
+	if err := tracker.Record(proposer, nil, big.NewInt(3)); err == nil {
+		t.Errorf("recording without a shardID should fail")
+	}
+
+	last, ok := tracker.LastPeriod(proposer, big.NewInt(1))
+	if !ok || last.Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("last period incorrect. want=%d. got=%v", 5, last)
+	}
+}
+
+func TestNonceTracker_Eviction(t *testing.T) {
+	tracker := NewNonceTracker(10)
+	proposer := common.HexToAddress("0x01")
+	shardID := big.NewInt(1)
+
+	for _, period := range []int64{1, 5, 20} {
+		if err := tracker.Record(proposer, shardID, big.NewInt(period)); err != nil {
+			t.Fatalf("could not record period %d: %v", period, err)
+		}
+	}
+
+	entry := tracker.entries[nonceKey{proposer: proposer, shardID: shardID.String()}]
+	if len(entry.periods) != 1 {
+		t.Errorf("periods older than the retention should be evicted. want=%d entries. got=%d", 1, len(entry.periods))
+	}
+	if err := tracker.Record(proposer, shardID, big.NewInt(5)); err == nil {
+		t.Errorf("recording an evicted period should fail")
+	}
+	if err := tracker.Record(proposer, shardID, big.NewInt(10)); err != nil {
+		t.Errorf("recording a period within the retention should succeed: %v", err)
+	}
+}
+
+func TestNonceTracker_Concurrent(t *testing.T) {
+	tracker := NewNonceTracker(100)
+	proposer := common.HexToAddress("0x01")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- tracker.Record(proposer, big.NewInt(1), big.NewInt(7))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("exactly one concurrent record of the same tuple should succeed. got=%d", succeeded)
+	}
+}
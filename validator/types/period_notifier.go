@@ -0,0 +1,55 @@
+package types
+
+import (
+	"math/big"
+	"sync"
+)
+
+// periodSubscriptionBuffer is the number of period notifications buffered for
+// a subscriber before further notifications to it are dropped.
+const periodSubscriptionBuffer = 16
+
+// PeriodNotifier broadcasts the start of new periods to node components such
+// as proposers and attesters. Subscribers that fall behind miss notifications
+// instead of blocking the notifier.
+type PeriodNotifier struct {
+	subs map[<-chan *big.Int]chan *big.Int
+	lock sync.RWMutex
+}
+
+// NewPeriodNotifier creates a PeriodNotifier without subscribers.
+func NewPeriodNotifier() *PeriodNotifier {
+	return &PeriodNotifier{subs: make(map[<-chan *big.Int]chan *big.Int)}
+}
+
+// Subscribe returns a channel receiving every period notified from now on.
+func (n *PeriodNotifier) Subscribe() <-chan *big.Int {
+	ch := make(chan *big.Int, periodSubscriptionBuffer)
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.subs[ch] = ch
+	return ch
+}
+
+// Unsubscribe stops notifying the channel and closes it.
+func (n *PeriodNotifier) Unsubscribe(ch <-chan *big.Int) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if sub, ok := n.subs[ch]; ok {
+		delete(n.subs, ch)
+		close(sub)
+	}
+}
+
+// NotifyPeriod sends the period to every subscriber, each receiving its own copy.
+func (n *PeriodNotifier) NotifyPeriod(period *big.Int) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	for _, sub := range n.subs {
+		select {
+		case sub <- new(big.Int).Set(period):
+		default:
+			log.Warnf("Dropped notification of period %v for a subscriber that fell behind", period)
+		}
+	}
+}
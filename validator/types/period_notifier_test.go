@@ -0,0 +1,59 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPeriodNotifier_NotifyPeriod(t *testing.T) {
+	n := NewPeriodNotifier()
+	subs := []<-chan *big.Int{n.Subscribe(), n.Subscribe(), n.Subscribe()}
+
+	n.NotifyPeriod(big.NewInt(7))
+	for i, sub := range subs {
+		select {
+		case period := <-sub:
+			if period.Cmp(big.NewInt(7)) != 0 {
+				t.Errorf("subscriber %d received the wrong period. want=%v. got=%v", i, 7, period)
+			}
+		default:
+			t.Errorf("subscriber %d did not receive the period", i)
+		}
+	}
+
+	// Every subscriber receives its own copy of the period.
+	n.NotifyPeriod(big.NewInt(8))
+	first, second := <-subs[0], <-subs[1]
+	first.SetInt64(100)
+	if second.Cmp(big.NewInt(8)) != 0 {
+		t.Errorf("subscribers should not share the notified period. got=%v", second)
+	}
+}
+
+func TestPeriodNotifier_Unsubscribe(t *testing.T) {
+	n := NewPeriodNotifier()
+	kept, removed := n.Subscribe(), n.Subscribe()
+	n.Unsubscribe(removed)
+	n.Unsubscribe(removed)
+
+	n.NotifyPeriod(big.NewInt(1))
+	if period, ok := <-removed; ok {
+		t.Errorf("unsubscribed channel should not receive periods. got=%v", period)
+	}
+	select {
+	case <-kept:
+	default:
+		t.Errorf("remaining subscriber did not receive the period")
+	}
+}
+
+func TestPeriodNotifier_SlowSubscriber(t *testing.T) {
+	n := NewPeriodNotifier()
+	sub := n.Subscribe()
+	for i := 0; i < periodSubscriptionBuffer+5; i++ {
+		n.NotifyPeriod(big.NewInt(int64(i)))
+	}
+	if len(sub) != periodSubscriptionBuffer {
+		t.Errorf("buffered notifications incorrect. want=%d. got=%d", periodSubscriptionBuffer, len(sub))
+	}
+}
@@ -0,0 +1,125 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrProposerNotFound is returned by a ProposerRegistry when a proposer is not
+// registered.
+var ErrProposerNotFound = errors.New("proposer not found")
+
+// ProposerInfo describes a registered proposer.
+type ProposerInfo struct {
+	Address          common.Address
+	TotalStake       *big.Int   // the stake of all the proposer's registrations.
+	RegisteredPeriod *big.Int   // the period of the proposer's first registration.
+	ActiveShards     []*big.Int // the shards the proposer is registered for, in registration order.
+}
+
+// ProposerRegistry records the shards proposers register for. It keeps the
+// registrations in memory until they are read from the SMC.
+type ProposerRegistry struct {
+	proposers map[common.Address]*registeredProposer
+	lock      sync.RWMutex
+}
+
+// registeredProposer holds a proposer's info and the period it registered
+// for each of its shards in.
+type registeredProposer struct {
+	info         *ProposerInfo
+	shardPeriods map[string]*big.Int
+}
+
+// NewProposerRegistry creates an empty ProposerRegistry.
+func NewProposerRegistry() *ProposerRegistry {
+	return &ProposerRegistry{proposers: make(map[common.Address]*registeredProposer)}
+}
+
+// Register registers the proposer for the shard from the period on, adding
+// the stake to its total stake. A proposer can only register once per shard.
+func (r *ProposerRegistry) Register(addr common.Address, shardID *big.Int, period *big.Int, stake *big.Int) error {
+	if shardID == nil || shardID.Sign() < 0 {
+		return fmt.Errorf("invalid shardID %v: %w", shardID, ErrInvalidShardID)
+	}
+	if period == nil || period.Sign() < 0 {
+		return fmt.Errorf("invalid period %v: %w", period, ErrInvalidPeriod)
+	}
+	if stake == nil || stake.Sign() <= 0 {
+		return fmt.Errorf("stake %v must be positive", stake)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	p, ok := r.proposers[addr]
+	if !ok {
+		p = &registeredProposer{
+			info: &ProposerInfo{
+				Address:          addr,
+				TotalStake:       new(big.Int),
+				RegisteredPeriod: new(big.Int).Set(period),
+			},
+			shardPeriods: make(map[string]*big.Int),
+		}
+		r.proposers[addr] = p
+	}
+	if _, ok := p.shardPeriods[shardID.String()]; ok {
+		return fmt.Errorf("proposer %s is already registered for shard %v", addr.Hex(), shardID)
+	}
+	p.shardPeriods[shardID.String()] = new(big.Int).Set(period)
+	p.info.ActiveShards = append(p.info.ActiveShards, new(big.Int).Set(shardID))
+	p.info.TotalStake.Add(p.info.TotalStake, stake)
+	if period.Cmp(p.info.RegisteredPeriod) < 0 {
+		p.info.RegisteredPeriod.Set(period)
+	}
+	return nil
+}
+
+// Lookup returns a copy of the info of the registered proposer.
+func (r *ProposerRegistry) Lookup(addr common.Address) (*ProposerInfo, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	p, ok := r.proposers[addr]
+	if !ok {
+		return nil, fmt.Errorf("proposer %s: %w", addr.Hex(), ErrProposerNotFound)
+	}
+	info := &ProposerInfo{
+		Address:          p.info.Address,
+		TotalStake:       new(big.Int).Set(p.info.TotalStake),
+		RegisteredPeriod: new(big.Int).Set(p.info.RegisteredPeriod),
+		ActiveShards:     make([]*big.Int, len(p.info.ActiveShards)),
+	}
+	for i, shardID := range p.info.ActiveShards {
+		info.ActiveShards[i] = new(big.Int).Set(shardID)
+	}
+	return info, nil
+}
+
+// ProposersForShard returns the proposers registered for the shard in or
+// before the period, sorted by address.
+func (r *ProposerRegistry) ProposersForShard(shardID *big.Int, period *big.Int) ([]common.Address, error) {
+	if shardID == nil || period == nil {
+		return nil, errors.New("shardID and period are required")
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var proposers []common.Address
+	for addr, p := range r.proposers {
+		registered, ok := p.shardPeriods[shardID.String()]
+		if ok && registered.Cmp(period) <= 0 {
+			proposers = append(proposers, addr)
+		}
+	}
+	sort.Slice(proposers, func(i, j int) bool { return bytes.Compare(proposers[i][:], proposers[j][:]) < 0 })
+	return proposers, nil
+}
@@ -0,0 +1,119 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestProposerRegistry_RegisterLookup(t *testing.T) {
+	registry := NewProposerRegistry()
+	addr := common.HexToAddress("0xbeef")
+
+	if err := registry.Register(addr, big.NewInt(1), big.NewInt(10), big.NewInt(100)); err != nil {
+		t.Fatalf("could not register proposer: %v", err)
+	}
+	if err := registry.Register(addr, big.NewInt(3), big.NewInt(8), big.NewInt(50)); err != nil {
+		t.Fatalf("could not register proposer for a second shard: %v", err)
+	}
+	if err := registry.Register(addr, big.NewInt(1), big.NewInt(12), big.NewInt(10)); err == nil {
+		t.Errorf("registering twice for the same shard should fail")
+	}
+
+	info, err := registry.Lookup(addr)
+	if err != nil {
+		t.Fatalf("could not look up proposer: %v", err)
+	}
+	want := &ProposerInfo{
+		Address:          addr,
+		TotalStake:       big.NewInt(150),
+		RegisteredPeriod: big.NewInt(8),
+		ActiveShards:     []*big.Int{big.NewInt(1), big.NewInt(3)},
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("proposer info incorrect. want=%+v. got=%+v", want, info)
+	}
+
+	// the returned info is a copy.
+	info.TotalStake.SetInt64(0)
+	info.ActiveShards[0].SetInt64(7)
+	if again, _ := registry.Lookup(addr); !reflect.DeepEqual(again, want) {
+		t.Errorf("modifying looked up info should not change the registry. got=%+v", again)
+	}
+
+	if _, err := registry.Lookup(common.HexToAddress("0xdead")); !errors.Is(err, ErrProposerNotFound) {
+		t.Errorf("looking up an unregistered proposer should fail. want=%v. got=%v", ErrProposerNotFound, err)
+	}
+}
+
+func TestProposerRegistry_RegisterInvalid(t *testing.T) {
+	registry := NewProposerRegistry()
+	addr := common.HexToAddress("0xbeef")
+	tests := []struct {
+		name    string
+		shardID *big.Int
+		period  *big.Int
+		stake   *big.Int
+	}{
+		{name: "nil shardID", period: big.NewInt(1), stake: big.NewInt(1)},
+		{name: "negative shardID", shardID: big.NewInt(-1), period: big.NewInt(1), stake: big.NewInt(1)},
+		{name: "nil period", shardID: big.NewInt(1), stake: big.NewInt(1)},
+		{name: "nil stake", shardID: big.NewInt(1), period: big.NewInt(1)},
+		{name: "zero stake", shardID: big.NewInt(1), period: big.NewInt(1), stake: big.NewInt(0)},
+	}
+	for _, tt := range tests {
+		if err := registry.Register(addr, tt.shardID, tt.period, tt.stake); err == nil {
+			t.Errorf("registering with %s should fail", tt.name)
+		}
+	}
+	if _, err := registry.Lookup(addr); err == nil {
+		t.Errorf("failed registrations should not register the proposer")
+	}
+}
+
+func TestProposerRegistry_ProposersForShard(t *testing.T) {
+	registry := NewProposerRegistry()
+	validators := makeValidators(4)
+	registrations := []struct {
+		addr    common.Address
+		shardID int64
+		period  int64
+	}{
+		{addr: validators[2], shardID: 1, period: 5},
+		{addr: validators[0], shardID: 1, period: 10},
+		{addr: validators[1], shardID: 2, period: 1},
+		{addr: validators[3], shardID: 1, period: 1},
+	}
+	for _, reg := range registrations {
+		if err := registry.Register(reg.addr, big.NewInt(reg.shardID), big.NewInt(reg.period), big.NewInt(1)); err != nil {
+			t.Fatalf("could not register proposer: %v", err)
+		}
+	}
+
+	tests := []struct {
+		shardID int64
+		period  int64
+		want    []common.Address
+	}{
+		{shardID: 1, period: 0},
+		{shardID: 1, period: 5, want: []common.Address{validators[2], validators[3]}},
+		{shardID: 1, period: 10, want: []common.Address{validators[0], validators[2], validators[3]}},
+		{shardID: 2, period: 10, want: []common.Address{validators[1]}},
+		{shardID: 3, period: 10},
+	}
+	for _, tt := range tests {
+		got, err := registry.ProposersForShard(big.NewInt(tt.shardID), big.NewInt(tt.period))
+		if err != nil {
+			t.Fatalf("could not get proposers: %v", err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("proposers of shard %d in period %d incorrect. want=%v. got=%v", tt.shardID, tt.period, tt.want, got)
+		}
+	}
+	if _, err := registry.ProposersForShard(nil, big.NewInt(1)); err == nil {
+		t.Errorf("getting the proposers without a shardID should fail")
+	}
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// randaoChunkVariance is the percentage by which the length of RANDAO chunks
+// may deviate from the chunk size.
+const randaoChunkVariance = 10
+
+// randaoChunkLengths draws the lengths of RANDAO chunks of the given nominal
+// size from the RANDAO reveal. Lengths are within randaoChunkVariance percent
+// of the size.
+type randaoChunkLengths struct {
+	rng      *hashRand
+	min, max int
+}
+
+func newRANDAOChunkLengths(randaoReveal common.Hash, size int) *randaoChunkLengths {
+	delta := size * randaoChunkVariance / 100
+	return &randaoChunkLengths{
+		rng: &hashRand{randomness: crypto.Keccak256Hash(randaoReveal.Bytes())},
+		min: size - delta,
+		max: size + delta,
+	}
+}
+
+func (l *randaoChunkLengths) next() int {
+	return l.min + l.rng.intn(l.max-l.min+1)
+}
+
+// RANDAOChunking splits the body into chunks whose lengths are drawn from the
+// RANDAO reveal, within 10% of chunkSize, so that proposers cannot predict the
+// chunk boundaries before the reveal. The last chunk holds the rest of the
+// body and may be shorter. Chunks share the body's backing array.
+func RANDAOChunking(body []byte, randaoReveal common.Hash) [][]byte {
+	lengths := newRANDAOChunkLengths(randaoReveal, chunkSize)
+	var chunks [][]byte
+	for len(body) > 0 {
+		n := lengths.next()
+		if n > len(body) {
+			n = len(body)
+		}
+		chunks = append(chunks, body[:n:n])
+		body = body[n:]
+	}
+	return chunks
+}
+
+// ValidateRANDAOChunking checks that the chunks are the RANDAO chunking of
+// their concatenation for the reveal and the nominal chunk size.
+func ValidateRANDAOChunking(chunks [][]byte, randaoReveal common.Hash, chunkSize int) bool {
+	if chunkSize <= 0 {
+		return false
+	}
+	lengths := newRANDAOChunkLengths(randaoReveal, chunkSize)
+	for i, chunk := range chunks {
+		n := lengths.next()
+		if len(chunk) == n {
+			continue
+		}
+		// only the last chunk may be cut short by the end of the body.
+		if i != len(chunks)-1 || len(chunk) == 0 || len(chunk) > n {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package types
+
+import (
+	"bytes"
+	"crypto/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRANDAOChunking(t *testing.T) {
+	body := make([]byte, 1000)
+	rand.Read(body)
+	reveal := common.BytesToHash([]byte("randao reveal"))
+
+	chunks := RANDAOChunking(body, reveal)
+	if !bytes.Equal(bytes.Join(chunks, nil), body) {
+		t.Fatalf("chunks should concatenate to the body")
+	}
+	lengths := make(map[int]bool)
+	for i, chunk := range chunks[:len(chunks)-1] {
+		if len(chunk) < 29 || len(chunk) > 35 {
+			t.Errorf("chunk %d length %d is not within 10%% of %d", i, len(chunk), chunkSize)
+		}
+		lengths[len(chunk)] = true
+	}
+	if len(lengths) < 2 {
+		t.Errorf("chunk lengths should vary. got=%v", lengths)
+	}
+	if !ValidateRANDAOChunking(chunks, reveal, chunkSize) {
+		t.Errorf("chunking should validate against its reveal")
+	}
+
+	if again := RANDAOChunking(body, reveal); !reflect.DeepEqual(again, chunks) {
+		t.Errorf("chunking should be deterministic")
+	}
+	other := RANDAOChunking(body, common.BytesToHash([]byte("other reveal")))
+	if ValidateRANDAOChunking(other, reveal, chunkSize) {
+		t.Errorf("chunking of another reveal should not validate")
+	}
+	if chunks := RANDAOChunking(nil, reveal); len(chunks) != 0 {
+		t.Errorf("empty body should have no chunks. got=%d", len(chunks))
+	}
+}
+
+func TestValidateRANDAOChunking(t *testing.T) {
+	body := make([]byte, 500)
+	rand.Read(body)
+	reveal := common.BytesToHash([]byte("randao reveal"))
+	chunks := RANDAOChunking(body, reveal)
+	last := len(chunks) - 1
+
+	tests := []struct {
+		name   string
+		chunks func() [][]byte
+		size   int
+		valid  bool
+	}{
+		{name: "unchanged", chunks: func() [][]byte { return chunks }, size: chunkSize, valid: true},
+		{name: "no chunks", chunks: func() [][]byte { return nil }, size: chunkSize, valid: true},
+		{name: "truncated last chunk", chunks: func() [][]byte {
+			c := append([][]byte{}, chunks...)
+			c[last] = c[last][:1]
+			return c
+		}, size: chunkSize, valid: true},
+		{name: "dropped last chunk", chunks: func() [][]byte { return chunks[:last] }, size: chunkSize, valid: true},
+		{name: "empty last chunk", chunks: func() [][]byte { return append(append([][]byte{}, chunks...), []byte{}) }, size: chunkSize},
+		{name: "moved boundary", chunks: func() [][]byte {
+			c := append([][]byte{}, chunks...)
+			c[0], c[1] = c[0][:len(c[0])-1], append([]byte{c[0][len(c[0])-1]}, c[1]...)
+			return c
+		}, size: chunkSize},
+		{name: "other chunk size", chunks: func() [][]byte { return chunks }, size: 64},
+		{name: "zero chunk size", chunks: func() [][]byte { return chunks }, size: 0},
+	}
+	for _, tt := range tests {
+		if got := ValidateRANDAOChunking(tt.chunks(), reveal, tt.size); got != tt.valid {
+			t.Errorf("validation of %s incorrect. want=%v. got=%v", tt.name, tt.valid, got)
+		}
+	}
+}
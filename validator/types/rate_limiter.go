@@ -0,0 +1,62 @@
+package types
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RateLimiter allows every proposer a single collation per shard and period,
+// rejecting further ones as spam. Unlike the NonceTracker, which keeps a
+// window of periods per proposer, entries are kept until purged through Reset.
+type RateLimiter struct {
+	seen map[rateLimitKey]*big.Int
+	lock sync.Mutex
+}
+
+// rateLimitKey identifies a proposer's collation on a shard for a period.
+type rateLimitKey struct {
+	proposer common.Address
+	shardID  string
+	period   string
+}
+
+// NewRateLimiter creates an empty RateLimiter.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{seen: make(map[rateLimitKey]*big.Int)}
+}
+
+// Allow records the proposer's collation for the shard and period, returning
+// false if the proposer was already seen for them.
+func (r *RateLimiter) Allow(proposer common.Address, shardID *big.Int, period *big.Int) bool {
+	if shardID == nil || period == nil {
+		return false
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := rateLimitKey{proposer: proposer, shardID: shardID.String(), period: period.String()}
+	if _, ok := r.seen[key]; ok {
+		return false
+	}
+	r.seen[key] = new(big.Int).Set(period)
+	return true
+}
+
+// Reset purges the entries of periods earlier than olderThan.
+func (r *RateLimiter) Reset(olderThan *big.Int) {
+	if olderThan == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for key, period := range r.seen {
+		if period.Cmp(olderThan) < 0 {
+			delete(r.seen, key)
+		}
+	}
+}
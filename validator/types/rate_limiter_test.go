@@ -0,0 +1,50 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	limiter := NewRateLimiter()
+	proposer, other := common.Address{0x01}, common.Address{0x02}
+
+	tests := []struct {
+		proposer common.Address
+		shardID  int64
+		period   int64
+		want     bool
+	}{
+		{proposer: proposer, shardID: 1, period: 5, want: true},
+		{proposer: proposer, shardID: 1, period: 5, want: false},
+		{proposer: other, shardID: 1, period: 5, want: true},
+		{proposer: proposer, shardID: 2, period: 5, want: true},
+		{proposer: proposer, shardID: 1, period: 6, want: true},
+		{proposer: proposer, shardID: 1, period: 6, want: false},
+	}
+	for i, tt := range tests {
+		if got := limiter.Allow(tt.proposer, big.NewInt(tt.shardID), big.NewInt(tt.period)); got != tt.want {
+			t.Errorf("attempt %d incorrectly allowed. want=%v. got=%v", i, tt.want, got)
+		}
+	}
+	if limiter.Allow(proposer, nil, big.NewInt(5)) {
+		t.Errorf("a collation without shardID should not be allowed")
+	}
+}
+
+func TestRateLimiter_Reset(t *testing.T) {
+	limiter := NewRateLimiter()
+	proposer := common.Address{0x01}
+	limiter.Allow(proposer, big.NewInt(1), big.NewInt(5))
+	limiter.Allow(proposer, big.NewInt(1), big.NewInt(6))
+
+	limiter.Reset(big.NewInt(6))
+	if !limiter.Allow(proposer, big.NewInt(1), big.NewInt(5)) {
+		t.Errorf("proposer should be allowed again after its period was reset")
+	}
+	if limiter.Allow(proposer, big.NewInt(1), big.NewInt(6)) {
+		t.Errorf("periods not older than the reset period should be kept")
+	}
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// CollationReceipt summarizes the outcome of a finalized collation so that it
+// can be referenced from other shards.
+type CollationReceipt struct {
+	CollationHash common.Hash // the hash of the finalized collation.
+	ShardID       *big.Int    // the shard the collation belongs to.
+	Period        *big.Int    // the period the collation was finalized in.
+	GasUsed       uint64      // the total gas used by the collation's transactions.
+	Success       bool        // whether the collation executed successfully.
+}
+
+// ReceiptHash takes the keccak256 of the receipt's RLP encoding.
+func (r *CollationReceipt) ReceiptHash() common.Hash {
+	encoded, err := rlp.EncodeToBytes(r)
+	if err != nil {
+		log.Errorf("Failed to RLP encode collation receipt: %v", err)
+	}
+	return crypto.Keccak256Hash(encoded)
+}
+
+// CollationReceipts is a list of receipts that can be used to derive a
+// receipt root.
+type CollationReceipts []*CollationReceipt
+
+// Len returns the number of receipts in the list.
+func (rs CollationReceipts) Len() int { return len(rs) }
+
+// GetRlp returns the RLP encoding of one receipt from the list.
+func (rs CollationReceipts) GetRlp(i int) []byte {
+	bytes, err := rlp.EncodeToBytes(rs[i])
+	if err != nil {
+		log.Errorf("Unable to RLP encode to bytes: %v", err)
+	}
+	return bytes
+}
+
+// CalculateReceiptRoot computes the root of the trie built from the RLP
+// encoded receipts, in the same way a block's receipt root is derived.
+func CalculateReceiptRoot(receipts []*CollationReceipt) common.Hash {
+	return gethTypes.DeriveSha(CollationReceipts(receipts))
+}
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+func makeTestReceipts(n int) []*CollationReceipt {
+	receipts := make([]*CollationReceipt, n)
+	for i := range receipts {
+		receipts[i] = &CollationReceipt{
+			CollationHash: common.BytesToHash([]byte{byte(i + 1)}),
+			ShardID:       big.NewInt(int64(i)),
+			Period:        big.NewInt(10),
+			GasUsed:       uint64(21000 * (i + 1)),
+			Success:       i%2 == 0,
+		}
+	}
+	return receipts
+}
+
+func TestCollationReceipt_ReceiptHash(t *testing.T) {
+	receipts := makeTestReceipts(2)
+	if receipts[0].ReceiptHash() != receipts[0].ReceiptHash() {
+		t.Errorf("receipt hash should be deterministic")
+	}
+	if receipts[0].ReceiptHash() == receipts[1].ReceiptHash() {
+		t.Errorf("different receipts should have different hashes")
+	}
+
+	changed := *receipts[0]
+	changed.Success = !changed.Success
+	if changed.ReceiptHash() == receipts[0].ReceiptHash() {
+		t.Errorf("receipt hash should commit to the success flag")
+	}
+}
+
+func TestCalculateReceiptRoot(t *testing.T) {
+	if root := CalculateReceiptRoot(nil); root != gethTypes.EmptyRootHash {
+		t.Errorf("empty receipt root incorrect. want=%x. got=%x", gethTypes.EmptyRootHash, root)
+	}
+
+	receipts := makeTestReceipts(5)
+	root := CalculateReceiptRoot(receipts)
+	if root != CalculateReceiptRoot(makeTestReceipts(5)) {
+		t.Errorf("receipt root should be deterministic")
+	}
+
+	receipts[0], receipts[1] = receipts[1], receipts[0]
+	if CalculateReceiptRoot(receipts) == root {
+		t.Errorf("receipt root should commit to the order of receipts")
+	}
+	if CalculateReceiptRoot(receipts[:4]) == root {
+		t.Errorf("receipt root should commit to every receipt")
+	}
+}
@@ -2,15 +2,12 @@
 package types
 
 import (
-	"bytes"
-	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
-	gethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
-	"github.com/ethereum/go-ethereum/rlp"
 	logger "github.com/sirupsen/logrus"
 	leveldberrors "github.com/syndtr/goleveldb/leveldb/errors"
 )
@@ -24,6 +21,11 @@ var log = logger.WithField("prefix", "shard")
 type Shard struct {
 	shardDB ethdb.Database
 	shardID *big.Int
+	// headPeriod and headCollationHash track the latest collation processed
+	// for the shard by a ShardManager.
+	headPeriod        *big.Int
+	headCollationHash common.Hash
+	lock              sync.RWMutex
 }
 
 // NewShard creates an instance of a Shard struct given a shardID.
@@ -39,6 +41,37 @@ func (s *Shard) ShardID() *big.Int {
 	return s.shardID
 }
 
+// HeadPeriod is the period of the shard's latest processed collation, or nil
+// if no collation was processed yet.
+func (s *Shard) HeadPeriod() *big.Int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.headPeriod == nil {
+		return nil
+	}
+	return new(big.Int).Set(s.headPeriod)
+}
+
+// HeadCollationHash is the header hash of the shard's latest processed collation.
+func (s *Shard) HeadCollationHash() common.Hash {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.headCollationHash
+}
+
+// advanceHead sets the given header as the shard's head if its period is later
+// than the current head's.
+func (s *Shard) advanceHead(header *CollationHeader) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.headPeriod != nil && header.Period().Cmp(s.headPeriod) <= 0 {
+		return fmt.Errorf("collation period %v is not later than the head period %v of shard %v", header.Period(), s.headPeriod, s.shardID)
+	}
+	s.headPeriod = new(big.Int).Set(header.Period())
+	s.headCollationHash = header.SignedHash()
+	return nil
+}
+
 // ValidateShardID checks if header belongs to shard.
 func (s *Shard) ValidateShardID(h *CollationHeader) error {
 	if s.ShardID().Cmp(h.ShardID()) != 0 {
@@ -62,9 +95,7 @@ func (s *Shard) HeaderByHash(hash *common.Hash) (*CollationHeader, error) {
 	}
 
 	var header CollationHeader
-
-	stream := rlp.NewStream(bytes.NewReader(encoded), uint64(len(encoded)))
-	if err := header.DecodeRLP(stream); err != nil {
+	if err := header.Decode(encoded); err != nil {
 		return nil, fmt.Errorf("could not decode RLP header: %v", err)
 	}
 
@@ -87,11 +118,16 @@ func (s *Shard) CollationByHeaderHash(headerHash *common.Hash) (*Collation, erro
 		return nil, fmt.Errorf("cannot fetch body by chunk root: %v", err)
 	}
 
-	txs, err := DeserializeBlobToTx(body)
+	collation := NewCollation(header, body, nil)
+	if err := collation.VerifyBodyIntegrity(); err != nil {
+		return nil, err
+	}
+	txs, err := DeserializeBlobToTx(BytesBodyReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("cannot deserialize body: %v", err)
 	}
-	return NewCollation(header, body, *txs), nil
+	collation.transactions = *txs
+	return collation, nil
 }
 
 // ChunkRootfromHeaderHash gets the chunk root of a collation body from the hash of its header.
@@ -119,13 +155,11 @@ func (s *Shard) CanonicalHeaderHash(shardID *big.Int, period *big.Int) (*common.
 
 	// RLP decodes the header, computes its hash.
 	var header CollationHeader
-
-	stream := rlp.NewStream(bytes.NewReader(encoded), uint64(len(encoded)))
-	if err := header.DecodeRLP(stream); err != nil {
+	if err := header.Decode(encoded); err != nil {
 		return nil, fmt.Errorf("could not decode RLP header: %v", err)
 	}
 
-	collationHash := header.Hash()
+	collationHash := header.SignedHash()
 	return &collationHash, nil
 }
 
@@ -184,22 +218,21 @@ func (s *Shard) SaveHeader(header *CollationHeader) error {
 		return fmt.Errorf("header needs to have a chunk root set before saving")
 	}
 
-	encoded, err := header.EncodeRLP()
+	encoded, err := header.Encode()
 	if err != nil {
 		return fmt.Errorf("cannot encode header: %v", err)
 	}
 
 	// uses the hash of the header as the key.
-	return s.shardDB.Put(header.Hash().Bytes(), encoded)
+	return s.shardDB.Put(header.SignedHash().Bytes(), encoded)
 }
 
 // SaveBody adds the collation body to the shardDB and sets availability.
 func (s *Shard) SaveBody(body []byte) error {
 	if len(body) == 0 {
-		return errors.New("body is empty")
+		return fmt.Errorf("cannot save body: %w", ErrEmptyBody)
 	}
-	chunks := Chunks(body)                   // wrapper allowing us to merklizing the chunks.
-	chunkRoot := gethTypes.DeriveSha(chunks) // merklize the serialized blobs.
+	chunkRoot := chunkRootFromBody(body) // merklize the serialized blobs.
 	if err := s.SetAvailability(&chunkRoot, true); err != nil {
 		return err
 	}
@@ -217,6 +250,20 @@ func (s *Shard) SaveCollation(collation *Collation) error {
 	return s.SaveBody(collation.Body())
 }
 
+// deleteHeader removes the header with the given signed hash from the shardDB.
+func (s *Shard) deleteHeader(hash *common.Hash) error {
+	return s.shardDB.Delete(hash.Bytes())
+}
+
+// deleteBody removes the body with the given chunk root and its availability
+// from the shardDB.
+func (s *Shard) deleteBody(chunkRoot *common.Hash) error {
+	if err := s.shardDB.Delete(dataAvailabilityLookupKey(chunkRoot).Bytes()); err != nil {
+		return err
+	}
+	return s.shardDB.Delete(chunkRoot.Bytes())
+}
+
 // SetCanonical sets the collation header as canonical in the shardDB. This is called
 // after the period is over and over 2/3 attesters voted on the header.
 func (s *Shard) SetCanonical(header *CollationHeader) error {
@@ -225,7 +272,7 @@ func (s *Shard) SetCanonical(header *CollationHeader) error {
 	}
 	// the header needs to have been stored in the DB previously, so we
 	// fetch it from the shardDB.
-	hash := header.Hash()
+	hash := header.SignedHash()
 	dbHeader, err := s.HeaderByHash(&hash)
 	if err != nil {
 		return err
@@ -238,7 +285,7 @@ func (s *Shard) SetCanonical(header *CollationHeader) error {
 	}
 
 	key := canonicalCollationLookupKey(dbHeader.ShardID(), dbHeader.Period())
-	encoded, err := dbHeader.EncodeRLP()
+	encoded, err := dbHeader.Encode()
 	if err != nil {
 		return fmt.Errorf("cannot encode header: %v", err)
 	}
@@ -0,0 +1,143 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/prysmaticlabs/prysm/validator/params"
+	"github.com/prysmaticlabs/prysm/validator/utils"
+)
+
+// ShardManager keeps track of the shards a node is active in and processes
+// the collations it receives for them, e.g. from the P2P layer.
+type ShardManager struct {
+	shardDB   ethdb.Database
+	config    ShardConfig
+	shards    map[string]*Shard
+	callbacks []func(shard *Shard, c *Collation)
+	periods   *PeriodNotifier
+	period    *big.Int // the latest period notified to the period subscribers.
+	lock      sync.RWMutex
+}
+
+// NewShardManager creates a ShardManager storing the collations of every
+// registered shard in the given shardDB. Shards and collations are validated
+// against the shard count of cfg.
+func NewShardManager(shardDB ethdb.Database, cfg ShardConfig) *ShardManager {
+	return &ShardManager{
+		shardDB: shardDB,
+		config:  cfg,
+		shards:  make(map[string]*Shard),
+		periods: NewPeriodNotifier(),
+	}
+}
+
+// RegisterShard starts tracking the shard with the given ID.
+func (m *ShardManager) RegisterShard(id *big.Int) error {
+	if err := ValidateShardID(id, m.config.shardCount()); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.shards[id.String()]; ok {
+		return fmt.Errorf("shard %v is already registered", id)
+	}
+	m.shards[id.String()] = NewShard(new(big.Int).Set(id), m.shardDB)
+	return nil
+}
+
+// GetShard returns the registered shard with the given ID.
+func (m *ShardManager) GetShard(id *big.Int) (*Shard, error) {
+	if id == nil {
+		return nil, errors.New("no shardID provided")
+	}
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	shard, ok := m.shards[id.String()]
+	if !ok {
+		return nil, fmt.Errorf("shard %v is not registered", id)
+	}
+	return shard, nil
+}
+
+// ActiveShards returns the IDs of the registered shards in ascending order.
+func (m *ShardManager) ActiveShards() []*big.Int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	ids := make([]*big.Int, 0, len(m.shards))
+	for _, shard := range m.shards {
+		ids = append(ids, new(big.Int).Set(shard.ShardID()))
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Cmp(ids[j]) < 0 })
+	return ids
+}
+
+// OnCollation registers a callback fired for every collation successfully
+// processed by the manager.
+func (m *ShardManager) OnCollation(callback func(shard *Shard, c *Collation)) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.callbacks = append(m.callbacks, callback)
+}
+
+// PeriodNotifier returns the notifier broadcasting the periods started by the
+// blocks passed to ProcessBlock.
+func (m *ShardManager) PeriodNotifier() *PeriodNotifier {
+	return m.periods
+}
+
+// ProcessBlock is called by the mainchain block-processing loop for every new
+// block. When the block starts a period later than the last one seen, the
+// period subscribers are notified.
+func (m *ShardManager) ProcessBlock(blockNumber *big.Int) error {
+	period := utils.BlockToPeriod(blockNumber, big.NewInt(params.DefaultConfig().PeriodLength))
+	if period == nil {
+		return fmt.Errorf("invalid block number %v", blockNumber)
+	}
+
+	// Notifying never blocks, so the lock keeps notifications in period order.
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.period != nil && period.Cmp(m.period) <= 0 {
+		return nil
+	}
+	m.period = period
+	m.periods.NotifyPeriod(period)
+	return nil
+}
+
+// ProcessCollation validates a collation for one of the registered shards,
+// saves it to the shardDB, makes it the shard's head and fires the registered
+// callbacks. Collations that are not later than the shard's head are rejected.
+func (m *ShardManager) ProcessCollation(c *Collation) error {
+	if err := validateCollationContents(c, int64(m.config.shardCount())); err != nil {
+		return fmt.Errorf("invalid collation: %w", err)
+	}
+	shard, err := m.GetShard(c.Header().ShardID())
+	if err != nil {
+		return err
+	}
+	if head := shard.HeadPeriod(); head != nil && c.Header().Period().Cmp(head) <= 0 {
+		return fmt.Errorf("collation period %v is not later than the head period %v of shard %v", c.Header().Period(), head, shard.ShardID())
+	}
+	if err := shard.SaveCollation(c); err != nil {
+		return fmt.Errorf("could not save collation: %v", err)
+	}
+	if err := shard.advanceHead(c.Header()); err != nil {
+		return err
+	}
+
+	m.lock.RLock()
+	callbacks := make([]func(*Shard, *Collation), len(m.callbacks))
+	copy(callbacks, m.callbacks)
+	m.lock.RUnlock()
+	for _, callback := range callbacks {
+		callback(shard, c)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"sync"
+	"testing"
+
+	sharedDB "github.com/prysmaticlabs/prysm/shared/database"
+)
+
+func TestShardManager_RegisterShard(t *testing.T) {
+	manager := NewShardManager(sharedDB.NewKVStore(), ShardConfig{})
+	for _, id := range []int64{5, 1, 3} {
+		if err := manager.RegisterShard(big.NewInt(id)); err != nil {
+			t.Fatalf("could not register shard %d: %v", id, err)
+		}
+	}
+	if err := manager.RegisterShard(big.NewInt(3)); err == nil {
+		t.Errorf("registering a shard twice should fail")
+	}
+	if err := manager.RegisterShard(big.NewInt(-1)); err == nil {
+		t.Errorf("registering a negative shardID should fail")
+	}
+	if err := manager.RegisterShard(big.NewInt(100)); err == nil {
+		t.Errorf("registering a shardID beyond the shard count should fail")
+	}
+	if err := manager.RegisterShard(nil); err == nil {
+		t.Errorf("registering a nil shardID should fail")
+	}
+
+	want := []*big.Int{big.NewInt(1), big.NewInt(3), big.NewInt(5)}
+	if got := manager.ActiveShards(); !reflect.DeepEqual(got, want) {
+		t.Errorf("active shards incorrect. want=%v. got=%v", want, got)
+	}
+
+	shard, err := manager.GetShard(big.NewInt(3))
+	if err != nil {
+		t.Fatalf("could not get registered shard: %v", err)
+	}
+	if shard.ShardID().Cmp(big.NewInt(3)) != 0 {
+		t.Errorf("shardID incorrect. want=%d. got=%v", 3, shard.ShardID())
+	}
+	if _, err := manager.GetShard(big.NewInt(2)); err == nil {
+		t.Errorf("getting an unregistered shard should fail")
+	}
+}
+
+func TestShardManager_ConfiguredShardCount(t *testing.T) {
+	manager := NewShardManager(sharedDB.NewKVStore(), ShardConfig{ShardCount: 200})
+	if err := manager.RegisterShard(big.NewInt(150)); err != nil {
+		t.Fatalf("could not register shard within the configured shard count: %v", err)
+	}
+	if err := manager.RegisterShard(big.NewInt(200)); err == nil {
+		t.Errorf("registering a shardID beyond the configured shard count should fail")
+	}
+
+	small := NewShardManager(sharedDB.NewKVStore(), ShardConfig{ShardCount: 2})
+	if err := small.RegisterShard(big.NewInt(2)); !errors.Is(err, ErrInvalidShardID) {
+		t.Errorf("registering a shardID beyond the configured shard count should fail. want=%v. got=%v", ErrInvalidShardID, err)
+	}
+	if err := small.ProcessCollation(makeStoredCollation(t, 5, 1)); !errors.Is(err, ErrInvalidShardID) {
+		t.Errorf("processing a collation beyond the configured shard count should fail. want=%v. got=%v", ErrInvalidShardID, err)
+	}
+}
+
+func TestShardManager_ProcessCollation(t *testing.T) {
+	manager := NewShardManager(sharedDB.NewKVStore(), ShardConfig{})
+	if err := manager.RegisterShard(big.NewInt(1)); err != nil {
+		t.Fatalf("could not register shard: %v", err)
+	}
+
+	var processed []*Collation
+	manager.OnCollation(func(shard *Shard, c *Collation) {
+		if shard.ShardID().Cmp(c.Header().ShardID()) != 0 {
+			t.Errorf("callback fired with shard %v for a collation of shard %v", shard.ShardID(), c.Header().ShardID())
+		}
+		processed = append(processed, c)
+	})
+
+	c := makeStoredCollation(t, 1, 10)
+	if err := manager.ProcessCollation(c); err != nil {
+		t.Fatalf("could not process collation: %v", err)
+	}
+	shard, _ := manager.GetShard(big.NewInt(1))
+	if shard.HeadPeriod().Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("head period incorrect. want=%d. got=%v", 10, shard.HeadPeriod())
+	}
+	if shard.HeadCollationHash() != c.Header().SignedHash() {
+		t.Errorf("head collation hash incorrect. want=%x. got=%x", c.Header().SignedHash(), shard.HeadCollationHash())
+	}
+	hash := c.Header().SignedHash()
+	if saved, err := shard.CollationByHeaderHash(&hash); err != nil || saved == nil {
+		t.Errorf("processed collation should be saved in the shardDB: %v", err)
+	}
+	if len(processed) != 1 || processed[0] != c {
+		t.Errorf("callback should fire once for the processed collation. got %d calls", len(processed))
+	}
+
+	if err := manager.ProcessCollation(makeStoredCollation(t, 1, 10)); err == nil {
+		t.Errorf("processing a collation for the head period should fail")
+	}
+	if err := manager.ProcessCollation(makeStoredCollation(t, 2, 11)); err == nil {
+		t.Errorf("processing a collation for an unregistered shard should fail")
+	}
+	stale := makeStoredCollation(t, 1, 11)
+	stale.body = append([]byte{}, stale.body...)
+	stale.body[1] ^= 0xff
+	if err := manager.ProcessCollation(stale); err == nil {
+		t.Errorf("processing a collation with a stale chunk root should fail")
+	}
+	if len(processed) != 1 {
+		t.Errorf("callbacks should not fire for rejected collations. got %d calls", len(processed))
+	}
+	if shard.HeadPeriod().Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("rejected collations should not update the head. got=%v", shard.HeadPeriod())
+	}
+}
+
+func TestShardManager_ProcessCollationConcurrent(t *testing.T) {
+	manager := NewShardManager(sharedDB.NewKVStore(), ShardConfig{})
+	if err := manager.RegisterShard(big.NewInt(1)); err != nil {
+		t.Fatalf("could not register shard: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for period := int64(1); period <= 20; period++ {
+		c := makeStoredCollation(t, 1, period)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			manager.ProcessCollation(c)
+		}()
+	}
+	wg.Wait()
+
+	shard, _ := manager.GetShard(big.NewInt(1))
+	if shard.HeadPeriod() == nil {
+		t.Fatalf("shard should have a head after processing collations")
+	}
+}
+
+func TestShardManager_ProcessBlock(t *testing.T) {
+	m := NewShardManager(sharedDB.NewKVStore(), ShardConfig{})
+	sub := m.PeriodNotifier().Subscribe()
+
+	var got []int64
+	for block := int64(0); block < 12; block++ {
+		if err := m.ProcessBlock(big.NewInt(block)); err != nil {
+			t.Fatalf("could not process block %d: %v", block, err)
+		}
+	}
+	// A reorg to an earlier block does not notify a period again.
+	if err := m.ProcessBlock(big.NewInt(4)); err != nil {
+		t.Fatalf("could not process block: %v", err)
+	}
+	for len(sub) > 0 {
+		got = append(got, (<-sub).Int64())
+	}
+
+	want := []int64{0, 1, 2}
+	if len(got) != len(want) {
+		t.Fatalf("notified periods incorrect. want=%v. got=%v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("notified periods incorrect. want=%v. got=%v", want, got)
+		}
+	}
+
+	if err := m.ProcessBlock(big.NewInt(-1)); err == nil {
+		t.Errorf("processing a negative block number should fail")
+	}
+}
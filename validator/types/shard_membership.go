@@ -0,0 +1,105 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SMCState holds the shard assignments of the validators registered in the
+// Sharding Manager Contract, merklized so that membership of a validator in a
+// shard can be proven to light clients. Leaves are keccak256(shardID ||
+// address) with the shardID as a 32 byte big-endian value, sorted by address,
+// and the tree is built like a ValidatorSetTree.
+type SMCState struct {
+	shards map[common.Address]*big.Int
+	layers [][]common.Hash
+	index  map[common.Address]int
+}
+
+// NewSMCState merklizes the shard each validator is assigned to.
+func NewSMCState(assignments map[common.Address]*big.Int) (*SMCState, error) {
+	addrs := make([]common.Address, 0, len(assignments))
+	for addr, shardID := range assignments {
+		if shardID == nil || shardID.Sign() < 0 {
+			return nil, fmt.Errorf("validator %s has invalid shardID %v: %w", addr.Hex(), shardID, ErrInvalidShardID)
+		}
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+
+	s := &SMCState{
+		shards: make(map[common.Address]*big.Int, len(addrs)),
+		index:  make(map[common.Address]int, len(addrs)),
+	}
+	leaves := make([]common.Hash, len(addrs))
+	for i, addr := range addrs {
+		s.shards[addr] = new(big.Int).Set(assignments[addr])
+		s.index[addr] = i
+		leaves[i] = membershipLeaf(assignments[addr], addr)
+	}
+	s.layers = buildSortedPairLayers(leaves)
+	return s, nil
+}
+
+// Root returns the state root of the shard assignments.
+func (s *SMCState) Root() common.Hash {
+	return sortedPairRoot(s.layers)
+}
+
+// ShardOf returns the shard the validator is assigned to, and false if it is
+// not registered.
+func (s *SMCState) ShardOf(validator common.Address) (*big.Int, bool) {
+	shardID, ok := s.shards[validator]
+	if !ok {
+		return nil, false
+	}
+	return new(big.Int).Set(shardID), true
+}
+
+// membershipLeaf is the leaf of the validator's assignment to the shard.
+func membershipLeaf(shardID *big.Int, validator common.Address) common.Hash {
+	return crypto.Keccak256Hash(common.BigToHash(shardID).Bytes(), validator.Bytes())
+}
+
+// ShardMembershipProof proves to light clients that a validator is assigned
+// to a shard in the SMC state with the given root.
+type ShardMembershipProof struct {
+	ShardID          *big.Int
+	ValidatorAddress common.Address
+	SMCStateRoot     common.Hash
+	Proof            []common.Hash
+}
+
+// GenerateMembershipProof proves the validator's shard assignment in the SMC
+// state.
+func GenerateMembershipProof(validator common.Address, smcState *SMCState) (*ShardMembershipProof, error) {
+	if smcState == nil {
+		return nil, errors.New("no SMC state to prove membership in")
+	}
+	index, ok := smcState.index[validator]
+	if !ok {
+		return nil, fmt.Errorf("validator %s is not registered in the SMC", validator.Hex())
+	}
+	return &ShardMembershipProof{
+		ShardID:          new(big.Int).Set(smcState.shards[validator]),
+		ValidatorAddress: validator,
+		SMCStateRoot:     smcState.Root(),
+		Proof:            sortedPairProof(smcState.layers, index),
+	}, nil
+}
+
+// VerifyMembership checks that the proof links the validator's assignment to
+// the shard to the SMC state root. Light clients must check the root against
+// the SMC state they trust themselves.
+func VerifyMembership(proof *ShardMembershipProof) bool {
+	if proof == nil || proof.ShardID == nil || proof.ShardID.Sign() < 0 {
+		return false
+	}
+	return verifySortedPairProof(proof.SMCStateRoot, membershipLeaf(proof.ShardID, proof.ValidatorAddress), proof.Proof)
+}
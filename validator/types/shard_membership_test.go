@@ -0,0 +1,75 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func makeSMCState(t *testing.T, n int) (*SMCState, map[common.Address]*big.Int) {
+	assignments := make(map[common.Address]*big.Int)
+	for i, addr := range makeValidators(n) {
+		assignments[addr] = big.NewInt(int64(i % 4))
+	}
+	state, err := NewSMCState(assignments)
+	if err != nil {
+		t.Fatalf("could not create SMC state: %v", err)
+	}
+	return state, assignments
+}
+
+func TestGenerateMembershipProof(t *testing.T) {
+	for _, n := range []int{1, 2, 7, 50} {
+		state, assignments := makeSMCState(t, n)
+		for addr, shardID := range assignments {
+			proof, err := GenerateMembershipProof(addr, state)
+			if err != nil {
+				t.Fatalf("could not prove membership of %s: %v", addr.Hex(), err)
+			}
+			if proof.ShardID.Cmp(shardID) != 0 || proof.SMCStateRoot != state.Root() {
+				t.Errorf("proof of %s has wrong shardID or root. want shardID=%v. got=%v", addr.Hex(), shardID, proof.ShardID)
+			}
+			if !VerifyMembership(proof) {
+				t.Errorf("membership of %s in a state of %d validators should verify", addr.Hex(), n)
+			}
+		}
+	}
+}
+
+func TestVerifyMembership_Invalid(t *testing.T) {
+	state, _ := makeSMCState(t, 10)
+	validator := makeValidators(1)[0]
+	other, _ := makeSMCState(t, 11)
+
+	tests := []struct {
+		name   string
+		tamper func(p *ShardMembershipProof)
+	}{
+		{name: "shardID", tamper: func(p *ShardMembershipProof) { p.ShardID = big.NewInt(1) }},
+		{name: "validator", tamper: func(p *ShardMembershipProof) { p.ValidatorAddress = common.HexToAddress("0xff") }},
+		{name: "root", tamper: func(p *ShardMembershipProof) { p.SMCStateRoot = other.Root() }},
+		{name: "proof", tamper: func(p *ShardMembershipProof) { p.Proof = p.Proof[1:] }},
+		{name: "nil shardID", tamper: func(p *ShardMembershipProof) { p.ShardID = nil }},
+	}
+	for _, tt := range tests {
+		proof, err := GenerateMembershipProof(validator, state)
+		if err != nil {
+			t.Fatalf("could not prove membership: %v", err)
+		}
+		tt.tamper(proof)
+		if VerifyMembership(proof) {
+			t.Errorf("proof with tampered %s should not verify", tt.name)
+		}
+	}
+	if VerifyMembership(nil) {
+		t.Errorf("nil proof should not verify")
+	}
+
+	if _, err := GenerateMembershipProof(common.HexToAddress("0xff"), state); err == nil {
+		t.Errorf("proving membership of an unregistered validator should fail")
+	}
+	if _, err := NewSMCState(map[common.Address]*big.Int{validator: big.NewInt(-1)}); err == nil {
+		t.Errorf("creating an SMC state with a negative shardID should fail")
+	}
+}
@@ -0,0 +1,103 @@
+package types
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// ShardNonceManager tracks the next transaction nonce of accounts in each
+// shard, as every shard has its own nonce space. Nonces are persisted in a
+// shard state database so proposers keep assigning correct nonces across
+// restarts.
+type ShardNonceManager struct {
+	db   ethdb.Database
+	lock sync.Mutex
+}
+
+// NewShardNonceManager creates a ShardNonceManager persisting nonces in db.
+func NewShardNonceManager(db ethdb.Database) *ShardNonceManager {
+	return &ShardNonceManager{db: db}
+}
+
+// GetNonce returns the next nonce of the account in the shard, which is zero
+// until a nonce is committed.
+func (m *ShardNonceManager) GetNonce(shardID *big.Int, addr common.Address) (uint64, error) {
+	if shardID == nil {
+		return 0, errors.New("no shardID provided")
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.nonce(shardID, addr)
+}
+
+// CommitNonce marks nonce as used by the account in the shard, incrementing
+// its next nonce. Only the account's next nonce can be committed.
+func (m *ShardNonceManager) CommitNonce(shardID *big.Int, addr common.Address, nonce uint64) error {
+	if shardID == nil {
+		return errors.New("no shardID provided")
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	next, err := m.nonce(shardID, addr)
+	if err != nil {
+		return err
+	}
+	if nonce != next {
+		return fmt.Errorf("nonce %d of %s on shard %v committed out of order, next nonce is %d", nonce, addr.Hex(), shardID, next)
+	}
+	if nonce == math.MaxUint64 {
+		return fmt.Errorf("nonce of %s on shard %v overflows uint64", addr.Hex(), shardID)
+	}
+	encoded := make([]byte, 8)
+	binary.BigEndian.PutUint64(encoded, nonce+1)
+	return m.db.Put(shardNonceLookupKey(shardID, addr).Bytes(), encoded)
+}
+
+// ResetNonce resets the next nonce of the account in the shard to zero.
+func (m *ShardNonceManager) ResetNonce(shardID *big.Int, addr common.Address) error {
+	if shardID == nil {
+		return errors.New("no shardID provided")
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.db.Delete(shardNonceLookupKey(shardID, addr).Bytes())
+}
+
+// nonce reads the next nonce of the account in the shard. The caller must
+// hold the lock.
+func (m *ShardNonceManager) nonce(shardID *big.Int, addr common.Address) (uint64, error) {
+	key := shardNonceLookupKey(shardID, addr).Bytes()
+	has, err := m.db.Has(key)
+	if err != nil {
+		return 0, fmt.Errorf("could not look up nonce: %v", err)
+	}
+	if !has {
+		return 0, nil
+	}
+	encoded, err := m.db.Get(key)
+	if err != nil {
+		return 0, fmt.Errorf("could not get nonce: %v", err)
+	}
+	if len(encoded) != 8 {
+		return 0, fmt.Errorf("stored nonce of %s on shard %v has %d bytes, expected 8", addr.Hex(), shardID, len(encoded))
+	}
+	return binary.BigEndian.Uint64(encoded), nil
+}
+
+// shardNonceLookupKey formats the lookup key of an account's nonce in a shard.
+// Unlike the other lookup keys, it is hashed rather than truncated, as the
+// address would otherwise be cut off.
+func shardNonceLookupKey(shardID *big.Int, addr common.Address) common.Hash {
+	key := fmt.Sprintf("shard-nonce-lookup:shardID=%s,address=%s", shardID, addr.Hex())
+	return crypto.Keccak256Hash([]byte(key))
+}
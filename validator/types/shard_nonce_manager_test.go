@@ -0,0 +1,85 @@
+package types
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	sharedDB "github.com/prysmaticlabs/prysm/shared/database"
+)
+
+func TestShardNonceManager_CommitNonce(t *testing.T) {
+	db := sharedDB.NewKVStore()
+	manager := NewShardNonceManager(db)
+	addr := common.HexToAddress("0x01")
+	other := common.HexToAddress("0x02")
+
+	for want := uint64(0); want < 3; want++ {
+		nonce, err := manager.GetNonce(big.NewInt(1), addr)
+		if err != nil {
+			t.Fatalf("could not get nonce: %v", err)
+		}
+		if nonce != want {
+			t.Errorf("nonce incorrect. want=%d. got=%d", want, nonce)
+		}
+		if err := manager.CommitNonce(big.NewInt(1), addr, nonce); err != nil {
+			t.Fatalf("could not commit nonce %d: %v", nonce, err)
+		}
+	}
+
+	// Shards and accounts have separate nonce spaces.
+	if nonce, _ := manager.GetNonce(big.NewInt(2), addr); nonce != 0 {
+		t.Errorf("nonce on another shard incorrect. want=%d. got=%d", 0, nonce)
+	}
+	if nonce, _ := manager.GetNonce(big.NewInt(1), other); nonce != 0 {
+		t.Errorf("nonce of another account incorrect. want=%d. got=%d", 0, nonce)
+	}
+
+	// Nonces are read back from the store.
+	if nonce, _ := NewShardNonceManager(db).GetNonce(big.NewInt(1), addr); nonce != 3 {
+		t.Errorf("persisted nonce incorrect. want=%d. got=%d", 3, nonce)
+	}
+
+	for _, nonce := range []uint64{2, 4} {
+		if err := manager.CommitNonce(big.NewInt(1), addr, nonce); err == nil {
+			t.Errorf("committing nonce %d out of order should fail", nonce)
+		}
+	}
+	if err := manager.CommitNonce(nil, addr, 0); err == nil {
+		t.Errorf("committing a nonce without shardID should fail")
+	}
+}
+
+func TestShardNonceManager_ResetNonce(t *testing.T) {
+	manager := NewShardNonceManager(sharedDB.NewKVStore())
+	addr := common.HexToAddress("0x01")
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		if err := manager.CommitNonce(big.NewInt(1), addr, nonce); err != nil {
+			t.Fatalf("could not commit nonce %d: %v", nonce, err)
+		}
+	}
+
+	if err := manager.ResetNonce(big.NewInt(1), addr); err != nil {
+		t.Fatalf("could not reset nonce: %v", err)
+	}
+	if nonce, err := manager.GetNonce(big.NewInt(1), addr); err != nil || nonce != 0 {
+		t.Errorf("reset nonce incorrect. want=%d. got=%d, err=%v", 0, nonce, err)
+	}
+	if err := manager.CommitNonce(big.NewInt(1), addr, 0); err != nil {
+		t.Errorf("could not commit nonce after reset: %v", err)
+	}
+}
+
+func TestShardNonceManager_Overflow(t *testing.T) {
+	db := sharedDB.NewKVStore()
+	manager := NewShardNonceManager(db)
+	addr := common.HexToAddress("0x01")
+	encoded := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	if err := db.Put(shardNonceLookupKey(big.NewInt(1), addr).Bytes(), encoded); err != nil {
+		t.Fatalf("could not store nonce: %v", err)
+	}
+	if err := manager.CommitNonce(big.NewInt(1), addr, math.MaxUint64); err == nil {
+		t.Errorf("committing the maximum nonce should fail")
+	}
+}
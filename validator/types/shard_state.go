@@ -0,0 +1,81 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ShardState links the finalized collations of a shard by folding every
+// collation and the receipts of its transactions into the shard's state root.
+type ShardState struct {
+	ShardID           *big.Int    // the shard the state belongs to.
+	StateRoot         common.Hash // the state root after the head collation.
+	HeadCollationHash common.Hash // the signed header hash of the head collation.
+	Period            *big.Int    // the period of the head collation.
+}
+
+// NewShardState creates the genesis state of a shard, which has no head
+// collation and an empty state root.
+func NewShardState(shardID *big.Int) *ShardState {
+	return &ShardState{
+		ShardID: new(big.Int).Set(shardID),
+		Period:  new(big.Int),
+	}
+}
+
+// Advance returns the state of the shard after the collation is finalized,
+// leaving the current state untouched. The new state root is the keccak256 of
+// the current state root, the collation's header hash and the root of the
+// transaction receipts, which must be given in the order of the transactions.
+func (s *ShardState) Advance(c *Collation, txResults []*gethTypes.Receipt) (*ShardState, error) {
+	if c == nil || c.Header() == nil {
+		return nil, fmt.Errorf("collation has no header")
+	}
+	header := c.Header()
+	if header.ShardID() == nil || header.ShardID().Cmp(s.ShardID) != 0 {
+		return nil, fmt.Errorf("collation shardID %v does not match shard %v: %w", header.ShardID(), s.ShardID, ErrInvalidShardID)
+	}
+	if header.Period() == nil {
+		return nil, fmt.Errorf("collation has no period: %w", ErrInvalidPeriod)
+	}
+	if s.HeadCollationHash != (common.Hash{}) && header.Period().Cmp(s.Period) <= 0 {
+		return nil, fmt.Errorf("collation period %v is not later than the head period %v: %w", header.Period(), s.Period, ErrInvalidPeriod)
+	}
+	if len(txResults) != len(c.Transactions()) {
+		return nil, fmt.Errorf("got %d receipts for %d transactions", len(txResults), len(c.Transactions()))
+	}
+	for i, receipt := range txResults {
+		if receipt == nil {
+			return nil, fmt.Errorf("receipt %d is nil", i)
+		}
+	}
+
+	headHash := header.SignedHash()
+	receiptRoot := gethTypes.DeriveSha(gethTypes.Receipts(txResults))
+	return &ShardState{
+		ShardID:           new(big.Int).Set(s.ShardID),
+		StateRoot:         crypto.Keccak256Hash(s.StateRoot.Bytes(), headHash.Bytes(), receiptRoot.Bytes()),
+		HeadCollationHash: headHash,
+		Period:            new(big.Int).Set(header.Period()),
+	}, nil
+}
+
+// MarshalBinary RLP encodes the shard state for persistent storage.
+func (s *ShardState) MarshalBinary() ([]byte, error) {
+	return rlp.EncodeToBytes(s)
+}
+
+// UnmarshalBinary decodes a shard state encoded by MarshalBinary.
+func (s *ShardState) UnmarshalBinary(data []byte) error {
+	var dec ShardState
+	if err := rlp.DecodeBytes(data, &dec); err != nil {
+		return fmt.Errorf("could not decode shard state: %v", err)
+	}
+	*s = dec
+	return nil
+}
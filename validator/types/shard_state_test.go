@@ -0,0 +1,132 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// makeReceipts creates a successful receipt for every transaction of the collation.
+func makeReceipts(c *Collation) []*gethTypes.Receipt {
+	receipts := make([]*gethTypes.Receipt, len(c.Transactions()))
+	var cumulativeGas uint64
+	for i, tx := range c.Transactions() {
+		cumulativeGas += tx.Gas()
+		receipts[i] = gethTypes.NewReceipt(nil, false, cumulativeGas)
+		receipts[i].TxHash = tx.Hash()
+		receipts[i].GasUsed = tx.Gas()
+	}
+	return receipts
+}
+
+func TestShardState_Advance(t *testing.T) {
+	genesis := NewShardState(big.NewInt(1))
+	first := makeStoredCollation(t, 1, 1)
+
+	state, err := genesis.Advance(first, makeReceipts(first))
+	if err != nil {
+		t.Fatalf("could not advance state: %v", err)
+	}
+	if state.HeadCollationHash != first.Header().SignedHash() {
+		t.Errorf("head collation hash incorrect. want=%v. got=%v", first.Header().SignedHash().Hex(), state.HeadCollationHash.Hex())
+	}
+	if state.Period.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("period incorrect. want=%v. got=%v", 1, state.Period)
+	}
+	if state.StateRoot == (common.Hash{}) || state.StateRoot == genesis.StateRoot {
+		t.Errorf("state root should change after advancing. got=%v", state.StateRoot.Hex())
+	}
+	if genesis.HeadCollationHash != (common.Hash{}) || genesis.Period.Sign() != 0 {
+		t.Errorf("advancing should not modify the current state: %+v", genesis)
+	}
+
+	again, err := genesis.Advance(first, makeReceipts(first))
+	if err != nil {
+		t.Fatalf("could not advance state: %v", err)
+	}
+	if again.StateRoot != state.StateRoot {
+		t.Errorf("state root should be deterministic. want=%v. got=%v", state.StateRoot.Hex(), again.StateRoot.Hex())
+	}
+
+	failed := makeReceipts(first)
+	failed[0].Status = gethTypes.ReceiptStatusFailed
+	other, err := genesis.Advance(first, failed)
+	if err != nil {
+		t.Fatalf("could not advance state: %v", err)
+	}
+	if other.StateRoot == state.StateRoot {
+		t.Errorf("state root should depend on the receipts")
+	}
+
+	second := makeStoredCollation(t, 1, 2)
+	next, err := state.Advance(second, makeReceipts(second))
+	if err != nil {
+		t.Fatalf("could not advance state: %v", err)
+	}
+	if next.StateRoot == state.StateRoot || next.Period.Cmp(big.NewInt(2)) != 0 {
+		t.Errorf("state should advance to the second collation: %+v", next)
+	}
+}
+
+func TestShardState_AdvanceInvalid(t *testing.T) {
+	genesis := NewShardState(big.NewInt(1))
+	first := makeStoredCollation(t, 1, 5)
+	state, err := genesis.Advance(first, makeReceipts(first))
+	if err != nil {
+		t.Fatalf("could not advance state: %v", err)
+	}
+
+	otherShard := makeStoredCollation(t, 2, 6)
+	if _, err := state.Advance(otherShard, makeReceipts(otherShard)); !errors.Is(err, ErrInvalidShardID) {
+		t.Errorf("advancing with another shard's collation should fail. want=%v. got=%v", ErrInvalidShardID, err)
+	}
+	for _, period := range []int64{4, 5} {
+		stale := makeStoredCollation(t, 1, period)
+		if _, err := state.Advance(stale, makeReceipts(stale)); !errors.Is(err, ErrInvalidPeriod) {
+			t.Errorf("advancing with period %d should fail. want=%v. got=%v", period, ErrInvalidPeriod, err)
+		}
+	}
+
+	next := makeStoredCollation(t, 1, 6)
+	if _, err := state.Advance(next, makeReceipts(next)[1:]); err == nil {
+		t.Errorf("advancing with a missing receipt should fail")
+	}
+	receipts := makeReceipts(next)
+	receipts[1] = nil
+	if _, err := state.Advance(next, receipts); err == nil {
+		t.Errorf("advancing with a nil receipt should fail")
+	}
+	if _, err := state.Advance(nil, nil); err == nil {
+		t.Errorf("advancing without a collation should fail")
+	}
+}
+
+func TestShardState_MarshalBinary(t *testing.T) {
+	c := makeStoredCollation(t, 3, 7)
+	state, err := NewShardState(big.NewInt(3)).Advance(c, makeReceipts(c))
+	if err != nil {
+		t.Fatalf("could not advance state: %v", err)
+	}
+
+	for _, want := range []*ShardState{NewShardState(big.NewInt(3)), state} {
+		encoded, err := want.MarshalBinary()
+		if err != nil {
+			t.Fatalf("could not marshal state: %v", err)
+		}
+		got := &ShardState{}
+		if err := got.UnmarshalBinary(encoded); err != nil {
+			t.Fatalf("could not unmarshal state: %v", err)
+		}
+		if got.ShardID.Cmp(want.ShardID) != 0 || got.Period.Cmp(want.Period) != 0 ||
+			got.StateRoot != want.StateRoot || got.HeadCollationHash != want.HeadCollationHash {
+			t.Errorf("unmarshaled state does not match. want=%+v. got=%+v", want, got)
+		}
+	}
+
+	if err := (&ShardState{}).UnmarshalBinary([]byte{0xc1}); err == nil {
+		t.Errorf("unmarshaling malformed data should fail")
+	}
+}
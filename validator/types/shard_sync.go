@@ -0,0 +1,132 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// syncBatchSize is the number of headers requested at once while syncing.
+const syncBatchSize = 64
+
+// syncBodyPeriods is the number of periods, counted back from the synced head,
+// whose collation bodies are fetched after syncing the headers. Older bodies are
+// not needed to follow the shard and are only fetched on demand.
+const syncBodyPeriods = 16
+
+// ShardClient fetches the collations of a shard from a peer.
+type ShardClient interface {
+	// GetHeaders returns up to count headers of the shard's collations, starting
+	// at period from and in ascending period order. Periods without a collation
+	// are skipped, so fewer than count headers are only returned at the head.
+	GetHeaders(shardID *big.Int, from, count *big.Int) ([]*CollationHeader, error)
+	// GetBody returns the body of the collation with the given signed header hash.
+	GetBody(hash common.Hash) ([]byte, error)
+}
+
+// SyncFromCheckpoint catches a registered shard up with the client, starting at
+// checkpointPeriod. The headers up to the client's head are fetched in batches,
+// verified and saved, after which the bodies of the collations in the last
+// syncBodyPeriods periods are fetched and saved as well. The shard's head is
+// advanced to the last synced header. Headers that were saved stay saved if
+// the sync fails or ctx is cancelled, so a later sync can resume from the
+// shard's head period.
+func (m *ShardManager) SyncFromCheckpoint(ctx context.Context, client ShardClient, shardID *big.Int, checkpointPeriod *big.Int) error {
+	shard, err := m.GetShard(shardID)
+	if err != nil {
+		return err
+	}
+	if checkpointPeriod == nil || checkpointPeriod.Sign() < 0 {
+		return fmt.Errorf("invalid checkpoint period %v: %w", checkpointPeriod, ErrInvalidPeriod)
+	}
+
+	var headers []*CollationHeader
+	from := new(big.Int).Set(checkpointPeriod)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, err := client.GetHeaders(shardID, from, big.NewInt(syncBatchSize))
+		if err != nil {
+			return fmt.Errorf("could not fetch headers from period %v: %v", from, err)
+		}
+		if len(batch) > syncBatchSize {
+			return fmt.Errorf("received %d headers, requested at most %d", len(batch), syncBatchSize)
+		}
+		for _, h := range batch {
+			if err := verifySyncedHeader(h, shardID, from, m.config); err != nil {
+				return fmt.Errorf("invalid header: %w", err)
+			}
+			if err := shard.SaveHeader(h); err != nil {
+				return fmt.Errorf("could not save header: %v", err)
+			}
+			from.Add(h.Period(), big.NewInt(1))
+		}
+		headers = append(headers, batch...)
+		if len(batch) < syncBatchSize {
+			break
+		}
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+
+	head := headers[len(headers)-1]
+	oldest := new(big.Int).Sub(head.Period(), big.NewInt(syncBodyPeriods-1))
+	for _, h := range headers {
+		if h.Period().Cmp(oldest) < 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := syncBody(client, shard, h); err != nil {
+			return err
+		}
+	}
+
+	if current := shard.HeadPeriod(); current == nil || head.Period().Cmp(current) > 0 {
+		return shard.advanceHead(head)
+	}
+	return nil
+}
+
+// verifySyncedHeader checks that a header received while syncing is valid
+// against cfg, signed by all its proposers, belongs to the shard and is not
+// earlier than the period requested from.
+func verifySyncedHeader(h *CollationHeader, shardID *big.Int, from *big.Int, cfg ShardConfig) error {
+	if h == nil {
+		return fmt.Errorf("header is nil")
+	}
+	if err := h.validate(int64(cfg.shardCount())); err != nil {
+		return err
+	}
+	if h.ShardID().Cmp(shardID) != 0 {
+		return fmt.Errorf("header of shard %v received while syncing shard %v: %w", h.ShardID(), shardID, ErrInvalidShardID)
+	}
+	if h.Period().Cmp(from) < 0 {
+		return fmt.Errorf("header period %v is earlier than the requested period %v: %w", h.Period(), from, ErrInvalidPeriod)
+	}
+	if h.ChunkRoot() == nil {
+		return fmt.Errorf("header of period %v has no chunk root", h.Period())
+	}
+	return h.VerifyAllProposerSignatures()
+}
+
+// syncBody fetches the body of the header's collation and saves it after
+// checking it against the header's chunk root.
+func syncBody(client ShardClient, shard *Shard, h *CollationHeader) error {
+	body, err := client.GetBody(h.SignedHash())
+	if err != nil {
+		return fmt.Errorf("could not fetch body of collation %s: %v", h.SignedHash().Hex(), err)
+	}
+	if chunkRoot := chunkRootFromBody(body); chunkRoot != *h.ChunkRoot() {
+		return fmt.Errorf("body of collation %s has chunk root %s, header has %s: %w", h.SignedHash().Hex(), chunkRoot.Hex(), h.ChunkRoot().Hex(), ErrChunkRootMismatch)
+	}
+	if err := shard.SaveBody(body); err != nil {
+		return fmt.Errorf("could not save body of collation %s: %w", h.SignedHash().Hex(), err)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	sharedDB "github.com/prysmaticlabs/prysm/shared/database"
+)
+
+// mockShardClient serves the collations of a single shard from memory.
+type mockShardClient struct {
+	collations    map[int64]*Collation
+	headerBatches int
+}
+
+// newMockShardClient creates signed collations of shard 1 for the given periods.
+func newMockShardClient(t *testing.T, periods []int64) *mockShardClient {
+	key := generateKeys(t, 1)[0]
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	client := &mockShardClient{collations: make(map[int64]*Collation)}
+	for _, period := range periods {
+		header, err := NewCollationHeader(big.NewInt(1), nil, big.NewInt(period), &addr, nil, false)
+		if err != nil {
+			t.Fatalf("could not create collation header: %v", err)
+		}
+		c := NewCollation(header, nil, makeRandomTransactions(1))
+		if err := c.Reserialize(); err != nil {
+			t.Fatalf("could not serialize collation: %v", err)
+		}
+		if err := header.Sign(key); err != nil {
+			t.Fatalf("could not sign header: %v", err)
+		}
+		client.collations[period] = c
+	}
+	return client
+}
+
+func (c *mockShardClient) GetHeaders(shardID *big.Int, from, count *big.Int) ([]*CollationHeader, error) {
+	c.headerBatches++
+	var periods []int64
+	for period := range c.collations {
+		if period >= from.Int64() {
+			periods = append(periods, period)
+		}
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i] < periods[j] })
+	if int64(len(periods)) > count.Int64() {
+		periods = periods[:count.Int64()]
+	}
+	headers := make([]*CollationHeader, len(periods))
+	for i, period := range periods {
+		headers[i] = c.collations[period].Header()
+	}
+	return headers, nil
+}
+
+func (c *mockShardClient) GetBody(hash common.Hash) ([]byte, error) {
+	for _, collation := range c.collations {
+		if collation.Header().SignedHash() == hash {
+			return collation.Body(), nil
+		}
+	}
+	return nil, fmt.Errorf("no collation with header hash %s", hash.Hex())
+}
+
+func newSyncShardManager(t *testing.T) *ShardManager {
+	manager := NewShardManager(sharedDB.NewKVStore(), ShardConfig{})
+	if err := manager.RegisterShard(big.NewInt(1)); err != nil {
+		t.Fatalf("could not register shard: %v", err)
+	}
+	return manager
+}
+
+func TestShardManager_SyncFromCheckpoint(t *testing.T) {
+	// Periods 10 to 159 have a collation, except for the multiples of 7.
+	var periods []int64
+	for period := int64(10); period < 160; period++ {
+		if period%7 != 0 {
+			periods = append(periods, period)
+		}
+	}
+	client := newMockShardClient(t, periods)
+	manager := newSyncShardManager(t)
+
+	if err := manager.SyncFromCheckpoint(context.Background(), client, big.NewInt(1), big.NewInt(20)); err != nil {
+		t.Fatalf("could not sync shard: %v", err)
+	}
+	// The 119 headers from period 20 onward are fetched in a full batch and a partial one.
+	if client.headerBatches != 2 {
+		t.Errorf("number of header batches incorrect. want=%d. got=%d", 2, client.headerBatches)
+	}
+
+	shard, _ := manager.GetShard(big.NewInt(1))
+	for _, period := range periods {
+		header := client.collations[period].Header()
+		hash := header.SignedHash()
+		_, err := shard.HeaderByHash(&hash)
+		if synced := period >= 20; synced != (err == nil) {
+			t.Errorf("header of period %d saved=%v, want %v", period, err == nil, synced)
+		}
+		available, err := shard.CheckAvailability(header)
+		if err != nil {
+			available = false
+		}
+		if wantBody := period >= 144; available != wantBody {
+			t.Errorf("body of period %d available=%v, want %v", period, available, wantBody)
+		}
+	}
+	if head := shard.HeadPeriod(); head == nil || head.Int64() != 159 {
+		t.Errorf("head period incorrect. want=%d. got=%v", 159, head)
+	}
+}
+
+func TestShardManager_SyncFromCheckpointInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(client *mockShardClient)
+		want   error
+	}{
+		{
+			name: "invalid signature",
+			tamper: func(client *mockShardClient) {
+				client.collations[12].Header().AddSig(make([]byte, 65))
+			},
+			want: ErrInvalidProposerSignature,
+		},
+		{
+			name: "mismatching body",
+			tamper: func(client *mockShardClient) {
+				client.collations[12].body = []byte{1, 2, 3}
+			},
+			want: ErrChunkRootMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		client := newMockShardClient(t, []int64{10, 11, 12})
+		tt.tamper(client)
+		err := newSyncShardManager(t).SyncFromCheckpoint(context.Background(), client, big.NewInt(1), big.NewInt(0))
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: sync error incorrect. want=%v. got=%v", tt.name, tt.want, err)
+		}
+	}
+
+	client := newMockShardClient(t, []int64{10})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := newSyncShardManager(t).SyncFromCheckpoint(ctx, client, big.NewInt(1), big.NewInt(0)); err != context.Canceled {
+		t.Errorf("sync error incorrect. want=%v. got=%v", context.Canceled, err)
+	}
+	if err := newSyncShardManager(t).SyncFromCheckpoint(context.Background(), client, big.NewInt(2), big.NewInt(0)); err == nil {
+		t.Errorf("syncing an unregistered shard should fail")
+	}
+}
+
+func TestVerifySyncedHeader_ConfiguredShardCount(t *testing.T) {
+	key := generateKeys(t, 1)[0]
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	cfg := ShardConfig{ShardCount: 200}
+	chunkRoot := common.BytesToHash([]byte{1})
+	header, err := NewCollationHeader(big.NewInt(150), &chunkRoot, big.NewInt(10), &addr, nil, false, cfg)
+	if err != nil {
+		t.Fatalf("could not create collation header: %v", err)
+	}
+	if err := header.Sign(key); err != nil {
+		t.Fatalf("could not sign header: %v", err)
+	}
+
+	if err := verifySyncedHeader(header, big.NewInt(150), big.NewInt(0), cfg); err != nil {
+		t.Errorf("header within the configured shard count should be valid: %v", err)
+	}
+	if err := verifySyncedHeader(header, big.NewInt(150), big.NewInt(0), ShardConfig{}); !errors.Is(err, ErrInvalidShardID) {
+		t.Errorf("header beyond the default shard count should be invalid. want=%v. got=%v", ErrInvalidShardID, err)
+	}
+}
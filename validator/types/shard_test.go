@@ -7,7 +7,6 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
-	gethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rlp"
 	sharedDB "github.com/prysmaticlabs/prysm/shared/database"
@@ -52,8 +51,7 @@ func (c *Collation) Hash() common.Hash {
 
 func TestShard_ValidateShardID(t *testing.T) {
 	emptyHash := common.BytesToHash([]byte{})
-	emptyAddr := common.BytesToAddress([]byte{})
-	header := NewCollationHeader(big.NewInt(1), &emptyHash, big.NewInt(1), &emptyAddr, [32]byte{})
+	header := newTestCollationHeader(t, big.NewInt(1), &emptyHash, big.NewInt(1))
 	shardDB := sharedDB.NewKVStore()
 	shard := NewShard(big.NewInt(3), shardDB)
 
@@ -61,8 +59,8 @@ func TestShard_ValidateShardID(t *testing.T) {
 		t.Errorf("ShardID validation incorrect. Function should throw error when ShardID's do not match. want=%d. got=%d", header.ShardID().Int64(), shard.ShardID().Int64())
 	}
 
-	header2 := NewCollationHeader(big.NewInt(100), &emptyHash, big.NewInt(1), &emptyAddr, [32]byte{})
-	shard2 := NewShard(big.NewInt(100), shardDB)
+	header2 := newTestCollationHeader(t, big.NewInt(99), &emptyHash, big.NewInt(1))
+	shard2 := NewShard(big.NewInt(99), shardDB)
 
 	if err := shard2.ValidateShardID(header2); err != nil {
 		t.Errorf("ShardID validation incorrect. Function should not throw error when ShardID's match. want=%d. got=%d", header2.ShardID().Int64(), shard2.ShardID().Int64())
@@ -71,8 +69,7 @@ func TestShard_ValidateShardID(t *testing.T) {
 
 func TestShard_HeaderByHash(t *testing.T) {
 	emptyHash := common.BytesToHash([]byte{})
-	emptyAddr := common.BytesToAddress([]byte{})
-	header := NewCollationHeader(big.NewInt(1), &emptyHash, big.NewInt(1), &emptyAddr, [32]byte{})
+	header := newTestCollationHeader(t, big.NewInt(1), &emptyHash, big.NewInt(1))
 
 	// creates a mockDB that always returns nil values from .Get and errors in every other method.
 	mockDB := &mockShardDB{kv: make(map[common.Hash][]byte)}
@@ -107,10 +104,9 @@ func TestShard_HeaderByHash(t *testing.T) {
 }
 
 func TestShard_CollationByHeaderHash(t *testing.T) {
-	emptyAddr := common.BytesToAddress([]byte{})
 
 	// Empty chunk root.
-	header := NewCollationHeader(big.NewInt(1), nil, big.NewInt(1), &emptyAddr, [32]byte{})
+	header := newTestCollationHeader(t, big.NewInt(1), nil, big.NewInt(1))
 
 	collation := &Collation{
 		header: header,
@@ -167,9 +163,7 @@ func TestShard_CollationByHeaderHash(t *testing.T) {
 func TestShard_ChunkRootfromHeaderHash(t *testing.T) {
 	shardID := big.NewInt(1)
 	period := big.NewInt(1)
-	proposerAddress := common.BytesToAddress([]byte{})
-	proposerSignature := [32]byte{}
-	header := NewCollationHeader(shardID, nil, period, &proposerAddress, proposerSignature)
+	header := newTestCollationHeader(t, shardID, nil, period)
 
 	collation := NewCollation(header, []byte{1, 2, 3}, nil)
 	collation.CalculateChunkRoot()
@@ -202,9 +196,7 @@ func TestShard_ChunkRootfromHeaderHash(t *testing.T) {
 func TestShard_CanonicalHeaderHash(t *testing.T) {
 	shardID := big.NewInt(1)
 	period := big.NewInt(1)
-	proposerAddress := common.BytesToAddress([]byte{})
-	proposerSignature := [32]byte{}
-	header := NewCollationHeader(shardID, nil, period, &proposerAddress, proposerSignature)
+	header := newTestCollationHeader(t, shardID, nil, period)
 
 	collation := NewCollation(header, []byte{1, 2, 3}, nil)
 
@@ -245,10 +237,8 @@ func TestShard_CanonicalHeaderHash(t *testing.T) {
 func TestShard_CanonicalCollation(t *testing.T) {
 	shardID := big.NewInt(1)
 	period := big.NewInt(1)
-	proposerAddress := common.BytesToAddress([]byte{})
-	proposerSignature := [32]byte{}
 	emptyHash := common.BytesToHash([]byte{})
-	header := NewCollationHeader(shardID, &emptyHash, period, &proposerAddress, proposerSignature)
+	header := newTestCollationHeader(t, shardID, &emptyHash, period)
 
 	shardDB := sharedDB.NewKVStore()
 	shard := NewShard(shardID, shardDB)
@@ -288,7 +278,7 @@ func TestShard_CanonicalCollation(t *testing.T) {
 
 func TestShard_SetCanonical(t *testing.T) {
 	chunkRoot := common.BytesToHash([]byte{})
-	header := NewCollationHeader(big.NewInt(1), &chunkRoot, big.NewInt(1), nil, [32]byte{})
+	header := newTestCollationHeader(t, big.NewInt(1), &chunkRoot, big.NewInt(1))
 
 	shardDB := sharedDB.NewKVStore()
 	shard := NewShard(big.NewInt(1), shardDB)
@@ -320,8 +310,7 @@ func TestShard_BodyByChunkRoot(t *testing.T) {
 		t.Fatalf("cannot save body: %v", err)
 	}
 
-	chunks := Chunks(body)                   // wrapper allowing us to merklizing the chunks.
-	chunkRoot := gethTypes.DeriveSha(chunks) // merklize the serialized blobs.
+	chunkRoot := chunkRootFromBody(body) // merklize the serialized blobs.
 
 	dbBody, err := shard.BodyByChunkRoot(&chunkRoot)
 	if err != nil {
@@ -351,10 +340,8 @@ func TestShard_BodyByChunkRoot(t *testing.T) {
 func TestShard_CheckAvailability(t *testing.T) {
 	shardID := big.NewInt(1)
 	period := big.NewInt(1)
-	proposerAddress := common.BytesToAddress([]byte{})
-	proposerSignature := [32]byte{}
 	emptyHash := common.BytesToHash([]byte{})
-	header := NewCollationHeader(shardID, &emptyHash, period, &proposerAddress, proposerSignature)
+	header := newTestCollationHeader(t, shardID, &emptyHash, period)
 
 	shardDB := sharedDB.NewKVStore()
 	shard := NewShard(shardID, shardDB)
@@ -387,7 +374,7 @@ func TestShard_CheckAvailability(t *testing.T) {
 
 func TestShard_SetAvailability(t *testing.T) {
 	chunkRoot := common.BytesToHash([]byte{})
-	header := NewCollationHeader(big.NewInt(1), &chunkRoot, big.NewInt(1), nil, [32]byte{})
+	header := newTestCollationHeader(t, big.NewInt(1), &chunkRoot, big.NewInt(1))
 
 	// creates a mockDB that always returns nil values from .Get and errors in every other method.
 	mockDB := &mockShardDB{kv: make(map[common.Hash][]byte)}
@@ -422,10 +409,8 @@ func TestShard_SetAvailability(t *testing.T) {
 func TestShard_SaveCollation(t *testing.T) {
 	headerShardID := big.NewInt(1)
 	period := big.NewInt(1)
-	proposerAddress := common.BytesToAddress([]byte{})
-	proposerSignature := [32]byte{}
 	emptyHash := common.BytesToHash([]byte{})
-	header := NewCollationHeader(headerShardID, &emptyHash, period, &proposerAddress, proposerSignature)
+	header := newTestCollationHeader(t, headerShardID, &emptyHash, period)
 
 	shardDB := sharedDB.NewKVStore()
 	shard := NewShard(big.NewInt(2), shardDB)
@@ -449,7 +434,7 @@ func TestShard_SaveHeader(t *testing.T) {
 	emptyHash := common.BytesToHash([]byte{})
 	errorShard := NewShard(big.NewInt(1), mockDB)
 
-	header := NewCollationHeader(big.NewInt(1), &emptyHash, big.NewInt(1), nil, [32]byte{})
+	header := newTestCollationHeader(t, big.NewInt(1), &emptyHash, big.NewInt(1))
 	if err := errorShard.SaveHeader(header); err == nil {
 		t.Errorf("should not be able to save header if a faulty shardDB is used")
 	}
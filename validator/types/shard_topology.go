@@ -0,0 +1,118 @@
+package types
+
+import (
+	"math/big"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// ShardTopology keeps track of which peers serve which shards, so that
+// collation messages can be routed to peers interested in their shard. Peers
+// are indexed by shard and shards by peer, making lookups in both directions
+// cheap.
+type ShardTopology struct {
+	shardPeers map[string]map[string]bool
+	peerShards map[string]map[string]*big.Int
+	lock       sync.RWMutex
+}
+
+// NewShardTopology creates an empty ShardTopology.
+func NewShardTopology() *ShardTopology {
+	return &ShardTopology{
+		shardPeers: make(map[string]map[string]bool),
+		peerShards: make(map[string]map[string]*big.Int),
+	}
+}
+
+// AddPeer records the peer as serving the given shards, in addition to the
+// shards it was already known to serve.
+func (t *ShardTopology) AddPeer(peerID string, shardIDs []*big.Int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	shards, ok := t.peerShards[peerID]
+	if !ok {
+		shards = make(map[string]*big.Int, len(shardIDs))
+		t.peerShards[peerID] = shards
+	}
+	for _, shardID := range shardIDs {
+		if shardID == nil {
+			continue
+		}
+		key := shardID.String()
+		shards[key] = new(big.Int).Set(shardID)
+		if t.shardPeers[key] == nil {
+			t.shardPeers[key] = make(map[string]bool)
+		}
+		t.shardPeers[key][peerID] = true
+	}
+}
+
+// RemovePeer forgets the peer and all the shards it served.
+func (t *ShardTopology) RemovePeer(peerID string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for key := range t.peerShards[peerID] {
+		delete(t.shardPeers[key], peerID)
+		if len(t.shardPeers[key]) == 0 {
+			delete(t.shardPeers, key)
+		}
+	}
+	delete(t.peerShards, peerID)
+}
+
+// PeersForShard returns the peers serving the shard, sorted by peerID.
+func (t *ShardTopology) PeersForShard(shardID *big.Int) []string {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.peersForShard(shardID, nil)
+}
+
+// ShardsForPeer returns the shards served by the peer in ascending order.
+func (t *ShardTopology) ShardsForPeer(peerID string) []*big.Int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	shards := make([]*big.Int, 0, len(t.peerShards[peerID]))
+	for _, shardID := range t.peerShards[peerID] {
+		shards = append(shards, new(big.Int).Set(shardID))
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i].Cmp(shards[j]) < 0 })
+	return shards
+}
+
+// SelectBestPeer picks a random peer serving the shard which is not in the
+// exclude list, spreading requests across peers. It returns false if no such
+// peer is known.
+func (t *ShardTopology) SelectBestPeer(shardID *big.Int, exclude []string) (string, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	excluded := make(map[string]bool, len(exclude))
+	for _, peerID := range exclude {
+		excluded[peerID] = true
+	}
+	peers := t.peersForShard(shardID, excluded)
+	if len(peers) == 0 {
+		return "", false
+	}
+	return peers[rand.Intn(len(peers))], true
+}
+
+// peersForShard returns the sorted peers serving the shard, leaving out the
+// excluded ones. The caller must hold the lock.
+func (t *ShardTopology) peersForShard(shardID *big.Int, excluded map[string]bool) []string {
+	if shardID == nil {
+		return nil
+	}
+	peers := make([]string, 0, len(t.shardPeers[shardID.String()]))
+	for peerID := range t.shardPeers[shardID.String()] {
+		if !excluded[peerID] {
+			peers = append(peers, peerID)
+		}
+	}
+	sort.Strings(peers)
+	return peers
+}
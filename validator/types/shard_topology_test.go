@@ -0,0 +1,90 @@
+package types
+
+import (
+	"math/big"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestShardTopology_AddRemovePeer(t *testing.T) {
+	topology := NewShardTopology()
+	topology.AddPeer("peerA", []*big.Int{big.NewInt(1), big.NewInt(2)})
+	topology.AddPeer("peerB", []*big.Int{big.NewInt(2)})
+	topology.AddPeer("peerB", []*big.Int{big.NewInt(3), nil})
+
+	if peers := topology.PeersForShard(big.NewInt(2)); !reflect.DeepEqual(peers, []string{"peerA", "peerB"}) {
+		t.Errorf("peers for shard incorrect. want=%v. got=%v", []string{"peerA", "peerB"}, peers)
+	}
+	if shards := topology.ShardsForPeer("peerB"); !reflect.DeepEqual(shards, []*big.Int{big.NewInt(2), big.NewInt(3)}) {
+		t.Errorf("shards for peer incorrect. want=%v. got=%v", []*big.Int{big.NewInt(2), big.NewInt(3)}, shards)
+	}
+
+	topology.RemovePeer("peerA")
+	if peers := topology.PeersForShard(big.NewInt(1)); len(peers) != 0 {
+		t.Errorf("removed peer should not serve shards anymore. got=%v", peers)
+	}
+	if peers := topology.PeersForShard(big.NewInt(2)); !reflect.DeepEqual(peers, []string{"peerB"}) {
+		t.Errorf("peers for shard incorrect. want=%v. got=%v", []string{"peerB"}, peers)
+	}
+	if shards := topology.ShardsForPeer("peerA"); len(shards) != 0 {
+		t.Errorf("removed peer should not have shards. got=%v", shards)
+	}
+	topology.RemovePeer("unknown")
+}
+
+func TestShardTopology_SelectBestPeer(t *testing.T) {
+	topology := NewShardTopology()
+	for _, peerID := range []string{"peerA", "peerB", "peerC"} {
+		topology.AddPeer(peerID, []*big.Int{big.NewInt(1)})
+	}
+
+	selected := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		peerID, ok := topology.SelectBestPeer(big.NewInt(1), []string{"peerA"})
+		if !ok {
+			t.Fatalf("expected a peer to be selected")
+		}
+		if peerID == "peerA" {
+			t.Fatalf("excluded peer was selected")
+		}
+		selected[peerID] = true
+	}
+	if !selected["peerB"] || !selected["peerC"] {
+		t.Errorf("selection should spread across peers. got=%v", selected)
+	}
+
+	if _, ok := topology.SelectBestPeer(big.NewInt(1), []string{"peerA", "peerB", "peerC"}); ok {
+		t.Errorf("no peer should be selected when all are excluded")
+	}
+	if _, ok := topology.SelectBestPeer(big.NewInt(2), nil); ok {
+		t.Errorf("no peer should be selected for an unserved shard")
+	}
+}
+
+func TestShardTopology_Concurrent(t *testing.T) {
+	topology := NewShardTopology()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			peerID := string(rune('a' + i))
+			topology.AddPeer(peerID, []*big.Int{big.NewInt(int64(i % 3))})
+			topology.SelectBestPeer(big.NewInt(int64(i%3)), nil)
+			topology.ShardsForPeer(peerID)
+			if i%2 == 0 {
+				topology.RemovePeer(peerID)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	total := 0
+	for shard := int64(0); shard < 3; shard++ {
+		total += len(topology.PeersForShard(big.NewInt(shard)))
+	}
+	if total != 5 {
+		t.Errorf("number of peers incorrect. want=%d. got=%d", 5, total)
+	}
+}
@@ -0,0 +1,137 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+// ErrInvalidTransition is returned when a sync event is not valid in the sync
+// state machine's current state.
+var ErrInvalidTransition = errors.New("invalid sync state transition")
+
+// SyncState is a state of a shard sync.
+type SyncState int
+
+const (
+	// SyncIdle is the state before a sync is started.
+	SyncIdle SyncState = iota
+	// SyncFetchingHeaders is the state while collation headers are fetched.
+	SyncFetchingHeaders
+	// SyncFetchingBodies is the state while collation bodies are fetched.
+	SyncFetchingBodies
+	// SyncValidating is the state while the fetched collations are validated.
+	SyncValidating
+	// SyncComplete is the state once the fetched collations are validated.
+	SyncComplete
+	// SyncError is the state once the fetched collations failed validation.
+	SyncError
+)
+
+var syncStateNames = map[SyncState]string{
+	SyncIdle:            "Idle",
+	SyncFetchingHeaders: "FetchingHeaders",
+	SyncFetchingBodies:  "FetchingBodies",
+	SyncValidating:      "Validating",
+	SyncComplete:        "Complete",
+	SyncError:           "Error",
+}
+
+// String returns the name of the state.
+func (s SyncState) String() string {
+	if name, ok := syncStateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("SyncState(%d)", int(s))
+}
+
+// SyncEvent is an event moving a shard sync to another state.
+type SyncEvent int
+
+const (
+	// SyncStart starts fetching headers from SyncIdle.
+	SyncStart SyncEvent = iota
+	// SyncHeadersReceived moves on to fetching bodies once the headers arrived.
+	SyncHeadersReceived
+	// SyncBodiesReceived moves on to validating once the bodies arrived.
+	SyncBodiesReceived
+	// SyncValidationSucceeded completes the sync once the collations are valid.
+	SyncValidationSucceeded
+	// SyncValidationFailed fails the sync once the collations are invalid.
+	SyncValidationFailed
+	// SyncReset returns to SyncIdle from any state.
+	SyncReset
+)
+
+var syncEventNames = map[SyncEvent]string{
+	SyncStart:               "Start",
+	SyncHeadersReceived:     "HeadersReceived",
+	SyncBodiesReceived:      "BodiesReceived",
+	SyncValidationSucceeded: "ValidationSucceeded",
+	SyncValidationFailed:    "ValidationFailed",
+	SyncReset:               "Reset",
+}
+
+// String returns the name of the event.
+func (e SyncEvent) String() string {
+	if name, ok := syncEventNames[e]; ok {
+		return name
+	}
+	return fmt.Sprintf("SyncEvent(%d)", int(e))
+}
+
+// syncTransitions maps every state to the state each valid event leads to.
+// SyncReset is valid in every state and is not listed.
+var syncTransitions = map[SyncState]map[SyncEvent]SyncState{
+	SyncIdle:            {SyncStart: SyncFetchingHeaders},
+	SyncFetchingHeaders: {SyncHeadersReceived: SyncFetchingBodies},
+	SyncFetchingBodies:  {SyncBodiesReceived: SyncValidating},
+	SyncValidating: {
+		SyncValidationSucceeded: SyncComplete,
+		SyncValidationFailed:    SyncError,
+	},
+}
+
+// SyncStateMachine tracks the progress of a shard sync through its states,
+// from SyncIdle to either SyncComplete or SyncError.
+type SyncStateMachine struct {
+	state SyncState
+	lock  sync.Mutex
+}
+
+// NewSyncStateMachine creates a SyncStateMachine in the SyncIdle state.
+func NewSyncStateMachine() *SyncStateMachine {
+	return &SyncStateMachine{state: SyncIdle}
+}
+
+// State returns the current state.
+func (m *SyncStateMachine) State() SyncState {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.state
+}
+
+// Transition moves the machine to the state the event leads to, returning
+// ErrInvalidTransition and keeping the current state if the event is not
+// valid in it.
+func (m *SyncStateMachine) Transition(event SyncEvent) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	next := SyncIdle
+	if event != SyncReset {
+		var ok bool
+		if next, ok = syncTransitions[m.state][event]; !ok {
+			return fmt.Errorf("cannot handle %v in state %v: %w", event, m.state, ErrInvalidTransition)
+		}
+	}
+	log.WithFields(logger.Fields{
+		"event": event,
+		"from":  m.state,
+		"to":    next,
+	}).Info("Sync state changed")
+	m.state = next
+	return nil
+}
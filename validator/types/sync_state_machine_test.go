@@ -0,0 +1,116 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	logTest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestSyncStateMachine_ValidPaths(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []SyncEvent
+		want   []SyncState
+	}{
+		{
+			name:   "complete",
+			events: []SyncEvent{SyncStart, SyncHeadersReceived, SyncBodiesReceived, SyncValidationSucceeded},
+			want:   []SyncState{SyncFetchingHeaders, SyncFetchingBodies, SyncValidating, SyncComplete},
+		},
+		{
+			name:   "validation failure",
+			events: []SyncEvent{SyncStart, SyncHeadersReceived, SyncBodiesReceived, SyncValidationFailed},
+			want:   []SyncState{SyncFetchingHeaders, SyncFetchingBodies, SyncValidating, SyncError},
+		},
+		{
+			name:   "restart after failure",
+			events: []SyncEvent{SyncStart, SyncHeadersReceived, SyncBodiesReceived, SyncValidationFailed, SyncReset, SyncStart, SyncHeadersReceived, SyncBodiesReceived, SyncValidationSucceeded},
+			want:   []SyncState{SyncFetchingHeaders, SyncFetchingBodies, SyncValidating, SyncError, SyncIdle, SyncFetchingHeaders, SyncFetchingBodies, SyncValidating, SyncComplete},
+		},
+		{
+			name:   "reset while fetching",
+			events: []SyncEvent{SyncStart, SyncHeadersReceived, SyncReset, SyncStart},
+			want:   []SyncState{SyncFetchingHeaders, SyncFetchingBodies, SyncIdle, SyncFetchingHeaders},
+		},
+	}
+	for _, tt := range tests {
+		m := NewSyncStateMachine()
+		if m.State() != SyncIdle {
+			t.Fatalf("%s: initial state incorrect. want=%v. got=%v", tt.name, SyncIdle, m.State())
+		}
+		for i, event := range tt.events {
+			if err := m.Transition(event); err != nil {
+				t.Fatalf("%s: could not handle %v: %v", tt.name, event, err)
+			}
+			if m.State() != tt.want[i] {
+				t.Errorf("%s: state after %v incorrect. want=%v. got=%v", tt.name, event, tt.want[i], m.State())
+			}
+		}
+	}
+}
+
+func TestSyncStateMachine_InvalidTransitions(t *testing.T) {
+	// paths leading from SyncIdle to every state.
+	paths := map[SyncState][]SyncEvent{
+		SyncIdle:            nil,
+		SyncFetchingHeaders: {SyncStart},
+		SyncFetchingBodies:  {SyncStart, SyncHeadersReceived},
+		SyncValidating:      {SyncStart, SyncHeadersReceived, SyncBodiesReceived},
+		SyncComplete:        {SyncStart, SyncHeadersReceived, SyncBodiesReceived, SyncValidationSucceeded},
+		SyncError:           {SyncStart, SyncHeadersReceived, SyncBodiesReceived, SyncValidationFailed},
+	}
+	events := []SyncEvent{SyncStart, SyncHeadersReceived, SyncBodiesReceived, SyncValidationSucceeded, SyncValidationFailed, SyncReset}
+
+	for state, path := range paths {
+		for _, event := range events {
+			m := NewSyncStateMachine()
+			for _, e := range path {
+				if err := m.Transition(e); err != nil {
+					t.Fatalf("could not reach state %v: %v", state, err)
+				}
+			}
+
+			_, valid := syncTransitions[state][event]
+			err := m.Transition(event)
+			if valid || event == SyncReset {
+				if err != nil {
+					t.Errorf("%v in state %v should be valid: %v", event, state, err)
+				}
+				continue
+			}
+			if !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("%v in state %v should fail. want=%v. got=%v", event, state, ErrInvalidTransition, err)
+			}
+			if m.State() != state {
+				t.Errorf("invalid transition should keep the state. want=%v. got=%v", state, m.State())
+			}
+		}
+	}
+}
+
+func TestSyncStateMachine_LogsTransitions(t *testing.T) {
+	hook := logTest.NewGlobal()
+	m := NewSyncStateMachine()
+	if err := m.Transition(SyncStart); err != nil {
+		t.Fatalf("could not start sync: %v", err)
+	}
+	testutil.AssertLogsContain(t, hook, "Sync state changed")
+	entry := hook.LastEntry()
+	if entry.Data["from"] != SyncIdle || entry.Data["to"] != SyncFetchingHeaders || entry.Data["event"] != SyncStart {
+		t.Errorf("transition log fields incorrect. got=%v", entry.Data)
+	}
+}
+
+func TestSyncState_String(t *testing.T) {
+	if got := SyncFetchingBodies.String(); got != "FetchingBodies" {
+		t.Errorf("state name incorrect. want=%q. got=%q", "FetchingBodies", got)
+	}
+	if got := SyncValidationFailed.String(); got != "ValidationFailed" {
+		t.Errorf("event name incorrect. want=%q. got=%q", "ValidationFailed", got)
+	}
+	if got := SyncState(42).String(); got != "SyncState(42)" {
+		t.Errorf("unknown state name incorrect. want=%q. got=%q", "SyncState(42)", got)
+	}
+}
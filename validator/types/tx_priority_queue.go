@@ -0,0 +1,79 @@
+package types
+
+import (
+	"container/heap"
+
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxPriorityQueue orders transactions by descending gas price so that
+// proposers fill collations with the highest paying transactions first.
+// Transactions with the same gas price are popped in the order they were
+// pushed.
+type TxPriorityQueue struct {
+	txs txsByPrice
+	// seq is the insertion counter used to break gas price ties.
+	seq uint64
+}
+
+// NewTxPriorityQueue creates a queue holding txs.
+func NewTxPriorityQueue(txs []*gethTypes.Transaction) *TxPriorityQueue {
+	q := &TxPriorityQueue{txs: make(txsByPrice, 0, len(txs))}
+	for _, tx := range txs {
+		q.txs = append(q.txs, prioritizedTx{tx: tx, seq: q.seq})
+		q.seq++
+	}
+	heap.Init(&q.txs)
+	return q
+}
+
+// Push adds a transaction to the queue.
+func (q *TxPriorityQueue) Push(tx *gethTypes.Transaction) {
+	heap.Push(&q.txs, prioritizedTx{tx: tx, seq: q.seq})
+	q.seq++
+}
+
+// Pop removes and returns the transaction with the highest gas price, or nil
+// if the queue is empty.
+func (q *TxPriorityQueue) Pop() *gethTypes.Transaction {
+	if q.txs.Len() == 0 {
+		return nil
+	}
+	return heap.Pop(&q.txs).(prioritizedTx).tx
+}
+
+// Len returns the number of transactions in the queue.
+func (q *TxPriorityQueue) Len() int {
+	return q.txs.Len()
+}
+
+// prioritizedTx is a queued transaction with its insertion sequence number.
+type prioritizedTx struct {
+	tx  *gethTypes.Transaction
+	seq uint64
+}
+
+// txsByPrice implements heap.Interface as a max-heap on gas price.
+type txsByPrice []prioritizedTx
+
+func (s txsByPrice) Len() int      { return len(s) }
+func (s txsByPrice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s txsByPrice) Less(i, j int) bool {
+	if cmp := s[i].tx.GasPrice().Cmp(s[j].tx.GasPrice()); cmp != 0 {
+		return cmp > 0
+	}
+	return s[i].seq < s[j].seq
+}
+
+func (s *txsByPrice) Push(x interface{}) {
+	*s = append(*s, x.(prioritizedTx))
+}
+
+func (s *txsByPrice) Pop() interface{} {
+	old := *s
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = prioritizedTx{}
+	*s = old[:n-1]
+	return item
+}
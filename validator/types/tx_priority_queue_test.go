@@ -0,0 +1,58 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+func makeTxWithGasPrice(nonce uint64, gasPrice int64) *gethTypes.Transaction {
+	return gethTypes.NewTransaction(nonce, common.HexToAddress("0x1"), nil, 21000, big.NewInt(gasPrice), nil)
+}
+
+func TestTxPriorityQueue(t *testing.T) {
+	prices := []int64{5, 1, 9, 3, 9, 7, 1}
+	var txs []*gethTypes.Transaction
+	for i, price := range prices {
+		txs = append(txs, makeTxWithGasPrice(uint64(i), price))
+	}
+	q := NewTxPriorityQueue(txs[:4])
+	for _, tx := range txs[4:] {
+		q.Push(tx)
+	}
+	if q.Len() != len(txs) {
+		t.Fatalf("queue length incorrect. want=%d. got=%d", len(txs), q.Len())
+	}
+
+	// transactions with equal gas prices are popped in the order they were added.
+	want := []int{2, 4, 5, 0, 3, 1, 6}
+	for _, i := range want {
+		tx := q.Pop()
+		if tx != txs[i] {
+			t.Errorf("popped transaction out of order. want nonce=%d, price=%v. got nonce=%d, price=%v", txs[i].Nonce(), txs[i].GasPrice(), tx.Nonce(), tx.GasPrice())
+		}
+	}
+	if q.Len() != 0 {
+		t.Errorf("queue should be empty. got=%d", q.Len())
+	}
+	if tx := q.Pop(); tx != nil {
+		t.Errorf("popping an empty queue should return nil. got=%v", tx.Hash().Hex())
+	}
+}
+
+func TestTxPriorityQueue_DescendingGasPrice(t *testing.T) {
+	q := NewTxPriorityQueue(nil)
+	for i := 0; i < 100; i++ {
+		q.Push(makeTxWithGasPrice(uint64(i), int64((i*37)%101)))
+	}
+	last := q.Pop().GasPrice()
+	for q.Len() > 0 {
+		price := q.Pop().GasPrice()
+		if price.Cmp(last) > 0 {
+			t.Fatalf("gas prices should be popped in descending order. got %v after %v", price, last)
+		}
+		last = price
+	}
+}
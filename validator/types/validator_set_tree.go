@@ -0,0 +1,129 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ValidatorSetTree is a binary Merkle tree over a validator set, allowing a
+// proposer to prove its eligibility with a logarithmic number of hashes. Leaves
+// are the keccak256 hashes of the validator addresses sorted by address, and
+// every parent is the keccak256 hash of its two children in ascending order,
+// so that proofs do not need to carry the position of the leaf. The last node
+// of a layer with an odd number of nodes is carried up to the next layer.
+type ValidatorSetTree struct {
+	// layers contains the leaves in its first element and the root in its last.
+	layers [][]common.Hash
+	index  map[common.Address]int
+}
+
+// NewValidatorSetTree merklizes the validators. Duplicate addresses are only
+// included once and the order of the validators does not affect the root.
+func NewValidatorSetTree(validators []common.Address) *ValidatorSetTree {
+	addrs := append([]common.Address(nil), validators...)
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+
+	t := &ValidatorSetTree{index: make(map[common.Address]int, len(addrs))}
+	leaves := make([]common.Hash, 0, len(addrs))
+	for _, addr := range addrs {
+		if _, ok := t.index[addr]; ok {
+			continue
+		}
+		t.index[addr] = len(leaves)
+		leaves = append(leaves, crypto.Keccak256Hash(addr.Bytes()))
+	}
+
+	t.layers = buildSortedPairLayers(leaves)
+	return t
+}
+
+// Root returns the Merkle root of the validator set, which is the zero hash
+// for an empty set.
+func (t *ValidatorSetTree) Root() common.Hash {
+	return sortedPairRoot(t.layers)
+}
+
+// Proof returns the sibling hashes on the path from the validator's leaf to the
+// root, to be checked with VerifyValidatorProof.
+func (t *ValidatorSetTree) Proof(addr common.Address) ([]common.Hash, error) {
+	index, ok := t.index[addr]
+	if !ok {
+		return nil, fmt.Errorf("validator %s is not in the validator set", addr.Hex())
+	}
+	return sortedPairProof(t.layers, index), nil
+}
+
+// VerifyValidatorProof checks that addr is part of the validator set with the
+// given root, given the sibling hashes returned by Proof.
+func VerifyValidatorProof(root common.Hash, addr common.Address, proof []common.Hash) bool {
+	return verifySortedPairProof(root, crypto.Keccak256Hash(addr.Bytes()), proof)
+}
+
+// buildSortedPairLayers builds the layers of a binary Merkle tree over the
+// leaves, from the leaves up to the root. Parents are the keccak256 hash of
+// their two children in ascending order, and the last node of a layer with
+// an odd number of nodes is carried up to the next layer.
+func buildSortedPairLayers(leaves []common.Hash) [][]common.Hash {
+	var layers [][]common.Hash
+	layer := leaves
+	for len(layer) > 1 {
+		layers = append(layers, layer)
+		next := make([]common.Hash, (len(layer)+1)/2)
+		for i := range next {
+			if 2*i+1 == len(layer) {
+				next[i] = layer[2*i]
+				continue
+			}
+			next[i] = hashValidatorNodes(layer[2*i], layer[2*i+1])
+		}
+		layer = next
+	}
+	return append(layers, layer)
+}
+
+// sortedPairRoot returns the root of the tree with the given layers, which is
+// the zero hash for a tree without leaves.
+func sortedPairRoot(layers [][]common.Hash) common.Hash {
+	top := layers[len(layers)-1]
+	if len(top) == 0 {
+		return common.Hash{}
+	}
+	return top[0]
+}
+
+// sortedPairProof returns the sibling hashes on the path from the leaf at
+// index to the root of the tree with the given layers.
+func sortedPairProof(layers [][]common.Hash, index int) []common.Hash {
+	var proof []common.Hash
+	for _, layer := range layers[:len(layers)-1] {
+		// A node without sibling is carried up without hashing.
+		if sibling := index ^ 1; sibling < len(layer) {
+			proof = append(proof, layer[sibling])
+		}
+		index /= 2
+	}
+	return proof
+}
+
+// verifySortedPairProof checks that the sibling hashes link the leaf to the
+// root of a tree built by buildSortedPairLayers.
+func verifySortedPairProof(root common.Hash, leaf common.Hash, proof []common.Hash) bool {
+	node := leaf
+	for _, sibling := range proof {
+		node = hashValidatorNodes(node, sibling)
+	}
+	return node == root
+}
+
+// hashValidatorNodes computes the parent of two nodes of a validator set tree,
+// hashing them in ascending order.
+func hashValidatorNodes(a common.Hash, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a[:], b[:])
+}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestValidatorSetTree_Proof(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 8, 13, 100} {
+		validators := makeValidators(n)
+		tree := NewValidatorSetTree(validators)
+		root := tree.Root()
+
+		for _, addr := range validators {
+			proof, err := tree.Proof(addr)
+			if err != nil {
+				t.Fatalf("could not generate proof for validator %s: %v", addr.Hex(), err)
+			}
+			if !VerifyValidatorProof(root, addr, proof) {
+				t.Errorf("valid proof of validator %s in a set of %d failed verification", addr.Hex(), n)
+			}
+			if VerifyValidatorProof(root, common.Address{0xff}, proof) {
+				t.Errorf("proof of validator %s in a set of %d verified another address", addr.Hex(), n)
+			}
+			if len(proof) > 0 && VerifyValidatorProof(root, addr, proof[1:]) {
+				t.Errorf("truncated proof of validator %s in a set of %d verified", addr.Hex(), n)
+			}
+		}
+		if _, err := tree.Proof(common.Address{0xff}); err == nil {
+			t.Errorf("generating a proof for a validator outside the set should fail")
+		}
+	}
+}
+
+func TestValidatorSetTree_Root(t *testing.T) {
+	validators := makeValidators(5)
+	root := NewValidatorSetTree(validators).Root()
+
+	reversed := make([]common.Address, len(validators))
+	for i, addr := range validators {
+		reversed[len(validators)-1-i] = addr
+	}
+	if got := NewValidatorSetTree(reversed).Root(); got != root {
+		t.Errorf("root should not depend on the validator order. want=%x. got=%x", root, got)
+	}
+	if got := NewValidatorSetTree(append(validators, validators[0])).Root(); got != root {
+		t.Errorf("root should not depend on duplicate validators. want=%x. got=%x", root, got)
+	}
+	if got := NewValidatorSetTree(validators[:4]).Root(); got == root {
+		t.Errorf("root should change with the validator set")
+	}
+
+	single := NewValidatorSetTree(validators[:1]).Root()
+	if want := crypto.Keccak256Hash(validators[0].Bytes()); single != want {
+		t.Errorf("root of a single validator incorrect. want=%x. got=%x", want, single)
+	}
+	if got := NewValidatorSetTree(nil).Root(); got != (common.Hash{}) {
+		t.Errorf("root of an empty set should be the zero hash. got=%x", got)
+	}
+}
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ValidatorTracker records the shard assignments of notaries, keyed by the
+// address of their signing key. An assignment holds from the period it was
+// made in until the notary rotates to a new key.
+type ValidatorTracker struct {
+	assignments map[common.Address]*shardAssignment
+	lock        sync.RWMutex
+}
+
+// shardAssignment is the shard a key is assigned to, from the first period up
+// to but excluding the until period. A nil until never expires.
+type shardAssignment struct {
+	shardID *big.Int
+	from    *big.Int
+	until   *big.Int
+}
+
+// NewValidatorTracker creates an empty ValidatorTracker.
+func NewValidatorTracker() *ValidatorTracker {
+	return &ValidatorTracker{assignments: make(map[common.Address]*shardAssignment)}
+}
+
+// Assign assigns the notary to the shard from the period on. A key can only
+// be assigned once.
+func (v *ValidatorTracker) Assign(notary common.Address, shardID *big.Int, period *big.Int) error {
+	if shardID == nil || period == nil {
+		return errors.New("shardID and period are required")
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if _, ok := v.assignments[notary]; ok {
+		return fmt.Errorf("notary %s is already assigned", notary.Hex())
+	}
+	v.assignments[notary] = &shardAssignment{
+		shardID: new(big.Int).Set(shardID),
+		from:    new(big.Int).Set(period),
+	}
+	return nil
+}
+
+// ShardAt returns the shard the notary is assigned to in the period, and false
+// if it is not assigned to any shard then.
+func (v *ValidatorTracker) ShardAt(notary common.Address, period *big.Int) (*big.Int, bool) {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	a, ok := v.assignments[notary]
+	if !ok || period == nil || period.Cmp(a.from) < 0 || (a.until != nil && period.Cmp(a.until) >= 0) {
+		return nil, false
+	}
+	return new(big.Int).Set(a.shardID), true
+}
+
+// rotate hands the shard assignment of the old key over to the new key from
+// the period on.
+func (v *ValidatorTracker) rotate(oldNotary, newNotary common.Address, shardID *big.Int, period *big.Int) error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	old, ok := v.assignments[oldNotary]
+	if !ok || old.shardID.Cmp(shardID) != 0 {
+		return fmt.Errorf("notary %s is not assigned to shard %v", oldNotary.Hex(), shardID)
+	}
+	if old.until != nil {
+		return fmt.Errorf("notary %s already rotated its key in period %v", oldNotary.Hex(), old.until)
+	}
+	if period.Cmp(old.from) <= 0 {
+		return fmt.Errorf("rotation period %v is not later than the assignment period %v", period, old.from)
+	}
+	if _, ok := v.assignments[newNotary]; ok {
+		return fmt.Errorf("notary %s is already assigned", newNotary.Hex())
+	}
+	old.until = new(big.Int).Set(period)
+	v.assignments[newNotary] = &shardAssignment{
+		shardID: new(big.Int).Set(shardID),
+		from:    new(big.Int).Set(period),
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestValidatorTracker_Assign(t *testing.T) {
+	tracker := NewValidatorTracker()
+	notary := common.HexToAddress("0xbeef")
+	if err := tracker.Assign(notary, big.NewInt(2), big.NewInt(10)); err != nil {
+		t.Fatalf("could not assign notary: %v", err)
+	}
+	if err := tracker.Assign(notary, big.NewInt(3), big.NewInt(10)); err == nil {
+		t.Errorf("assigning a notary twice should fail")
+	}
+	if err := tracker.Assign(common.HexToAddress("0xdead"), nil, big.NewInt(10)); err == nil {
+		t.Errorf("assigning a notary without a shardID should fail")
+	}
+
+	tests := []struct {
+		notary common.Address
+		period *big.Int
+		ok     bool
+	}{
+		{notary: notary, period: big.NewInt(10), ok: true},
+		{notary: notary, period: big.NewInt(100), ok: true},
+		{notary: notary, period: big.NewInt(9)},
+		{notary: notary},
+		{notary: common.HexToAddress("0xdead"), period: big.NewInt(10)},
+	}
+	for _, tt := range tests {
+		shardID, ok := tracker.ShardAt(tt.notary, tt.period)
+		if ok != tt.ok {
+			t.Errorf("assignment of %s in period %v incorrect. want=%v. got=%v", tt.notary.Hex(), tt.period, tt.ok, ok)
+		}
+		if ok && shardID.Cmp(big.NewInt(2)) != 0 {
+			t.Errorf("assigned shard incorrect. want=%d. got=%v", 2, shardID)
+		}
+	}
+}
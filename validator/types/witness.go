@@ -0,0 +1,194 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// emptyCodeHash is the code hash of accounts without code.
+var emptyCodeHash = crypto.Keccak256Hash(nil)
+
+// AccountWitness proves an account of the shard state. Proof holds the RLP
+// encoded trie nodes on the path from the state root to the account.
+type AccountWitness struct {
+	Address  common.Address
+	Balance  *big.Int
+	Nonce    uint64
+	CodeHash common.Hash
+	Proof    [][]byte
+}
+
+// StorageWitness proves a storage slot of an account of the shard state. Proof
+// holds the RLP encoded trie nodes on the path from the account's storage root
+// to the slot.
+type StorageWitness struct {
+	Address common.Address
+	Key     common.Hash
+	Value   common.Hash
+	Proof   [][]byte
+}
+
+// ShardWitness holds the parts of the shard state a stateless node needs to
+// execute transactions, together with the proofs linking them to the state
+// root.
+type ShardWitness struct {
+	Accounts []AccountWitness
+	Storage  []StorageWitness
+}
+
+// StateDB is the shard state witnesses are created from. Its proofs are the
+// RLP encoded trie nodes from the state root to the account and from the
+// account's storage root to the slot.
+type StateDB interface {
+	GetBalance(addr common.Address) *big.Int
+	GetNonce(addr common.Address) uint64
+	GetCodeHash(addr common.Address) common.Hash
+	GetState(addr common.Address, key common.Hash) common.Hash
+	GetProof(addr common.Address) ([][]byte, error)
+	GetStorageProof(addr common.Address, key common.Hash) ([][]byte, error)
+}
+
+// stateAccount is the RLP encoding of accounts in the state trie.
+type stateAccount struct {
+	Nonce    uint64
+	Balance  *big.Int
+	Root     common.Hash
+	CodeHash []byte
+}
+
+// NewWitnessFromState creates the witness of the accounts the transactions
+// touch, which are their senders and recipients. Senders are recovered with
+// signer, which must match the chain the transactions were signed for.
+// Storage slots are only known once the transactions are executed, so the
+// witness holds no storage.
+func NewWitnessFromState(db StateDB, signer gethTypes.Signer, txs []*gethTypes.Transaction) (*ShardWitness, error) {
+	if signer == nil {
+		return nil, errors.New("no signer provided to recover transaction senders")
+	}
+	var addrs []common.Address
+	seen := make(map[common.Address]bool)
+	add := func(addr common.Address) {
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	for _, tx := range txs {
+		from, err := gethTypes.Sender(signer, tx)
+		if err != nil {
+			return nil, fmt.Errorf("could not recover sender of tx %s: %v", tx.Hash().Hex(), err)
+		}
+		add(from)
+		if to := tx.To(); to != nil {
+			add(*to)
+		}
+	}
+
+	witness := &ShardWitness{}
+	for _, addr := range addrs {
+		proof, err := db.GetProof(addr)
+		if err != nil {
+			return nil, fmt.Errorf("could not prove account %s: %v", addr.Hex(), err)
+		}
+		witness.Accounts = append(witness.Accounts, AccountWitness{
+			Address:  addr,
+			Balance:  new(big.Int).Set(db.GetBalance(addr)),
+			Nonce:    db.GetNonce(addr),
+			CodeHash: db.GetCodeHash(addr),
+			Proof:    proof,
+		})
+	}
+	return witness, nil
+}
+
+// VerifyWitness checks every account of the witness against the state root and
+// every storage slot against the storage root of its account, which must be
+// part of the witness. Accounts and slots missing from the state verify as
+// empty.
+func VerifyWitness(stateRoot common.Hash, w *ShardWitness) bool {
+	if w == nil {
+		return false
+	}
+	storageRoots := make(map[common.Address]common.Hash)
+	for _, account := range w.Accounts {
+		root, ok := verifyAccount(stateRoot, account)
+		if !ok {
+			return false
+		}
+		storageRoots[account.Address] = root
+	}
+	for _, slot := range w.Storage {
+		root, ok := storageRoots[slot.Address]
+		if !ok || !verifyStorage(root, slot) {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyAccount checks the account against the state root and returns its
+// storage root.
+func verifyAccount(stateRoot common.Hash, w AccountWitness) (common.Hash, bool) {
+	if w.Balance == nil {
+		return common.Hash{}, false
+	}
+	enc, err := verifyTrieProof(stateRoot, crypto.Keccak256(w.Address.Bytes()), w.Proof)
+	if err != nil {
+		return common.Hash{}, false
+	}
+	if enc == nil {
+		empty := w.Nonce == 0 && w.Balance.Sign() == 0 && (w.CodeHash == common.Hash{} || w.CodeHash == emptyCodeHash)
+		return gethTypes.EmptyRootHash, empty
+	}
+	var account stateAccount
+	if err := rlp.DecodeBytes(enc, &account); err != nil {
+		return common.Hash{}, false
+	}
+	if account.Nonce != w.Nonce || account.Balance.Cmp(w.Balance) != 0 || !bytes.Equal(account.CodeHash, w.CodeHash.Bytes()) {
+		return common.Hash{}, false
+	}
+	return account.Root, true
+}
+
+// verifyStorage checks the storage slot against the storage root of its
+// account.
+func verifyStorage(storageRoot common.Hash, w StorageWitness) bool {
+	enc, err := verifyTrieProof(storageRoot, crypto.Keccak256(w.Key.Bytes()), w.Proof)
+	if err != nil {
+		return false
+	}
+	if enc == nil {
+		return w.Value == common.Hash{}
+	}
+	var value []byte
+	if err := rlp.DecodeBytes(enc, &value); err != nil {
+		return false
+	}
+	return common.BytesToHash(value) == w.Value
+}
+
+// verifyTrieProof returns the value of key in the trie with the given root,
+// or nil if the proof shows the key is missing.
+func verifyTrieProof(root common.Hash, key []byte, proof [][]byte) ([]byte, error) {
+	// The empty trie has no nodes to prove anything with.
+	if root == gethTypes.EmptyRootHash {
+		return nil, nil
+	}
+	db := ethdb.NewMemDatabase()
+	for _, node := range proof {
+		if err := db.Put(crypto.Keccak256(node), node); err != nil {
+			return nil, err
+		}
+	}
+	value, _, err := trie.VerifyProof(root, key, db)
+	return value, err
+}
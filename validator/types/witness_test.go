@@ -0,0 +1,228 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// proofList collects the trie nodes of a proof in order.
+type proofList [][]byte
+
+func (l *proofList) Put(key []byte, value []byte) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// testStateDB proves accounts and storage of a committed geth state.
+type testStateDB struct {
+	*state.StateDB
+	db   state.Database
+	root common.Hash
+}
+
+func newTestStateDB(t *testing.T, setup func(s *state.StateDB)) *testStateDB {
+	db := state.NewDatabase(ethdb.NewMemDatabase())
+	s, err := state.New(common.Hash{}, db)
+	if err != nil {
+		t.Fatalf("could not create state: %v", err)
+	}
+	setup(s)
+	root, err := s.Commit(false)
+	if err != nil {
+		t.Fatalf("could not commit state: %v", err)
+	}
+	return &testStateDB{StateDB: s, db: db, root: root}
+}
+
+func (s *testStateDB) GetProof(addr common.Address) ([][]byte, error) {
+	tr, err := s.db.OpenTrie(s.root)
+	if err != nil {
+		return nil, err
+	}
+	var proof proofList
+	err = tr.Prove(crypto.Keccak256(addr.Bytes()), 0, &proof)
+	return proof, err
+}
+
+func (s *testStateDB) GetStorageProof(addr common.Address, key common.Hash) ([][]byte, error) {
+	var proof proofList
+	tr := s.StorageTrie(addr)
+	if tr == nil {
+		return proof, nil
+	}
+	err := tr.Prove(crypto.Keccak256(key.Bytes()), 0, &proof)
+	return proof, err
+}
+
+func signedTx(t *testing.T, signer gethTypes.Signer, nonce uint64, to common.Address) (*gethTypes.Transaction, common.Address) {
+	key := generateKeys(t, 1)[0]
+	tx, err := gethTypes.SignTx(gethTypes.NewTransaction(nonce, to, big.NewInt(1), 21000, big.NewInt(1), nil), signer, key)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	return tx, crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func TestNewWitnessFromState(t *testing.T) {
+	recipient := common.HexToAddress("0xbeef")
+	contract := common.HexToAddress("0xc0de")
+	slot := common.BytesToHash([]byte{1})
+	tx1, sender1 := signedTx(t, gethTypes.HomesteadSigner{}, 3, recipient)
+	tx2, sender2 := signedTx(t, gethTypes.HomesteadSigner{}, 0, recipient)
+
+	db := newTestStateDB(t, func(s *state.StateDB) {
+		s.SetBalance(sender1, big.NewInt(1000))
+		s.SetNonce(sender1, 3)
+		s.SetBalance(recipient, big.NewInt(5))
+		s.SetCode(contract, []byte{0x60, 0x00})
+		s.SetState(contract, slot, common.BytesToHash([]byte{42}))
+		// padding accounts so that the proofs have more than one node.
+		for i := int64(0); i < 50; i++ {
+			s.SetBalance(common.BigToAddress(big.NewInt(1000+i)), big.NewInt(i+1))
+		}
+	})
+
+	witness, err := NewWitnessFromState(db, gethTypes.HomesteadSigner{}, []*gethTypes.Transaction{tx1, tx2})
+	if err != nil {
+		t.Fatalf("could not create witness: %v", err)
+	}
+	want := []common.Address{sender1, recipient, sender2}
+	if len(witness.Accounts) != len(want) {
+		t.Fatalf("witness account count incorrect. want=%d. got=%d", len(want), len(witness.Accounts))
+	}
+	for i, account := range witness.Accounts {
+		if account.Address != want[i] {
+			t.Errorf("witness account %d incorrect. want=%s. got=%s", i, want[i].Hex(), account.Address.Hex())
+		}
+	}
+	if witness.Accounts[0].Nonce != 3 || witness.Accounts[0].Balance.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("sender account has wrong nonce or balance. got nonce=%d, balance=%v", witness.Accounts[0].Nonce, witness.Accounts[0].Balance)
+	}
+	if !VerifyWitness(db.root, witness) {
+		t.Fatalf("witness created from the state should verify against its root")
+	}
+
+	// storage of a contract verifies against the contract's storage root.
+	accountProof, err := db.GetProof(contract)
+	if err != nil {
+		t.Fatalf("could not prove contract: %v", err)
+	}
+	storageProof, err := db.GetStorageProof(contract, slot)
+	if err != nil {
+		t.Fatalf("could not prove storage: %v", err)
+	}
+	witness.Accounts = append(witness.Accounts, AccountWitness{
+		Address:  contract,
+		Balance:  new(big.Int),
+		CodeHash: db.GetCodeHash(contract),
+		Proof:    accountProof,
+	})
+	witness.Storage = []StorageWitness{{Address: contract, Key: slot, Value: db.GetState(contract, slot), Proof: storageProof}}
+	if !VerifyWitness(db.root, witness) {
+		t.Errorf("witness with contract storage should verify against the state root")
+	}
+}
+
+func TestVerifyWitness_Tampered(t *testing.T) {
+	addr := common.HexToAddress("0xbeef")
+	contract := common.HexToAddress("0xc0de")
+	slot := common.BytesToHash([]byte{1})
+	db := newTestStateDB(t, func(s *state.StateDB) {
+		s.SetBalance(addr, big.NewInt(100))
+		s.SetNonce(addr, 7)
+		s.SetState(contract, slot, common.BytesToHash([]byte{42}))
+		for i := int64(0); i < 50; i++ {
+			s.SetBalance(common.BigToAddress(big.NewInt(1000+i)), big.NewInt(i+1))
+		}
+	})
+	witness := func() *ShardWitness {
+		accountProof, _ := db.GetProof(addr)
+		contractProof, _ := db.GetProof(contract)
+		storageProof, _ := db.GetStorageProof(contract, slot)
+		return &ShardWitness{
+			Accounts: []AccountWitness{
+				{Address: addr, Balance: big.NewInt(100), Nonce: 7, CodeHash: db.GetCodeHash(addr), Proof: accountProof},
+				{Address: contract, Balance: new(big.Int), CodeHash: db.GetCodeHash(contract), Proof: contractProof},
+			},
+			Storage: []StorageWitness{{Address: contract, Key: slot, Value: common.BytesToHash([]byte{42}), Proof: storageProof}},
+		}
+	}
+	if !VerifyWitness(db.root, witness()) {
+		t.Fatalf("untampered witness should verify")
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(w *ShardWitness)
+	}{
+		{name: "balance", tamper: func(w *ShardWitness) { w.Accounts[0].Balance = big.NewInt(101) }},
+		{name: "nonce", tamper: func(w *ShardWitness) { w.Accounts[0].Nonce = 8 }},
+		{name: "code hash", tamper: func(w *ShardWitness) { w.Accounts[0].CodeHash = common.Hash{1} }},
+		{name: "nil balance", tamper: func(w *ShardWitness) { w.Accounts[0].Balance = nil }},
+		{name: "account proof", tamper: func(w *ShardWitness) { w.Accounts[0].Proof = w.Accounts[0].Proof[:1] }},
+		{name: "storage value", tamper: func(w *ShardWitness) { w.Storage[0].Value = common.BytesToHash([]byte{43}) }},
+		{name: "storage proof", tamper: func(w *ShardWitness) { w.Storage[0].Proof = nil }},
+		{name: "storage without account", tamper: func(w *ShardWitness) { w.Accounts = w.Accounts[:1] }},
+	}
+	for _, tt := range tests {
+		w := witness()
+		tt.tamper(w)
+		if VerifyWitness(db.root, w) {
+			t.Errorf("witness with tampered %s should not verify", tt.name)
+		}
+	}
+	if VerifyWitness(db.root, nil) {
+		t.Errorf("nil witness should not verify")
+	}
+}
+
+func TestVerifyWitness_MissingAccount(t *testing.T) {
+	db := newTestStateDB(t, func(s *state.StateDB) {
+		s.SetBalance(common.HexToAddress("0xbeef"), big.NewInt(1))
+	})
+	missing := common.HexToAddress("0xdead")
+	proof, err := db.GetProof(missing)
+	if err != nil {
+		t.Fatalf("could not prove missing account: %v", err)
+	}
+	w := &ShardWitness{Accounts: []AccountWitness{{Address: missing, Balance: new(big.Int), Proof: proof}}}
+	if !VerifyWitness(db.root, w) {
+		t.Errorf("empty witness of a missing account should verify")
+	}
+	w.Accounts[0].Balance = big.NewInt(1)
+	if VerifyWitness(db.root, w) {
+		t.Errorf("witness giving a missing account a balance should not verify")
+	}
+}
+
+func TestNewWitnessFromState_UnsignedTx(t *testing.T) {
+	db := newTestStateDB(t, func(s *state.StateDB) {})
+	if _, err := NewWitnessFromState(db, gethTypes.HomesteadSigner{}, makeRandomTransactions(1)); err == nil {
+		t.Errorf("creating a witness for an unsigned transaction should fail")
+	}
+}
+
+func TestNewWitnessFromState_EIP155(t *testing.T) {
+	signer := gethTypes.NewEIP155Signer(big.NewInt(5))
+	tx, sender := signedTx(t, signer, 0, common.HexToAddress("0xbeef"))
+	db := newTestStateDB(t, func(s *state.StateDB) {
+		s.SetBalance(sender, big.NewInt(1000))
+	})
+
+	witness, err := NewWitnessFromState(db, signer, []*gethTypes.Transaction{tx})
+	if err != nil {
+		t.Fatalf("could not create witness: %v", err)
+	}
+	if witness.Accounts[0].Address != sender {
+		t.Errorf("witness sender incorrect. want=%v. got=%v", sender.Hex(), witness.Accounts[0].Address.Hex())
+	}
+	if _, err := NewWitnessFromState(db, gethTypes.NewEIP155Signer(big.NewInt(1)), []*gethTypes.Transaction{tx}); err == nil {
+		t.Errorf("recovering the sender with the signer of another chain should fail")
+	}
+}
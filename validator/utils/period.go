@@ -0,0 +1,27 @@
+package utils
+
+import "math/big"
+
+// BlockToPeriod returns the period a mainchain block belongs to given the
+// number of blocks in a period. It returns nil if either input is nil, if the
+// block number is negative or if the period length is not positive.
+func BlockToPeriod(blockNumber *big.Int, periodLength *big.Int) *big.Int {
+	if blockNumber == nil || blockNumber.Sign() < 0 || periodLength == nil || periodLength.Sign() <= 0 {
+		return nil
+	}
+	return new(big.Int).Div(blockNumber, periodLength)
+}
+
+// PeriodToBlockRange returns the first and last mainchain block numbers of a
+// period, both inclusive, so that BlockToPeriod maps every block of the range
+// back to the period. It returns nil bounds if either input is nil, if the
+// period is negative or if the period length is not positive.
+func PeriodToBlockRange(period *big.Int, periodLength *big.Int) (start, end *big.Int) {
+	if period == nil || period.Sign() < 0 || periodLength == nil || periodLength.Sign() <= 0 {
+		return nil, nil
+	}
+	start = new(big.Int).Mul(period, periodLength)
+	end = new(big.Int).Add(start, periodLength)
+	end.Sub(end, big.NewInt(1))
+	return start, end
+}
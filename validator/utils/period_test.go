@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBlockToPeriod(t *testing.T) {
+	large, _ := new(big.Int).SetString("1000000000000000000000005", 10)
+	largePeriod, _ := new(big.Int).SetString("200000000000000000000001", 10)
+
+	tests := []struct {
+		blockNumber  *big.Int
+		periodLength *big.Int
+		period       *big.Int
+	}{
+		{blockNumber: big.NewInt(0), periodLength: big.NewInt(5), period: big.NewInt(0)},
+		{blockNumber: big.NewInt(4), periodLength: big.NewInt(5), period: big.NewInt(0)},
+		{blockNumber: big.NewInt(5), periodLength: big.NewInt(5), period: big.NewInt(1)},
+		{blockNumber: big.NewInt(9), periodLength: big.NewInt(5), period: big.NewInt(1)},
+		{blockNumber: large, periodLength: big.NewInt(5), period: largePeriod},
+		{blockNumber: nil, periodLength: big.NewInt(5), period: nil},
+		{blockNumber: big.NewInt(5), periodLength: nil, period: nil},
+		{blockNumber: big.NewInt(5), periodLength: big.NewInt(0), period: nil},
+		{blockNumber: big.NewInt(-1), periodLength: big.NewInt(5), period: nil},
+	}
+	for _, tt := range tests {
+		period := BlockToPeriod(tt.blockNumber, tt.periodLength)
+		if (period == nil) != (tt.period == nil) || (period != nil && period.Cmp(tt.period) != 0) {
+			t.Errorf("BlockToPeriod(%v, %v) incorrect. want=%v. got=%v", tt.blockNumber, tt.periodLength, tt.period, period)
+		}
+	}
+}
+
+func TestPeriodToBlockRange(t *testing.T) {
+	large, _ := new(big.Int).SetString("200000000000000000000001", 10)
+	largeStart, _ := new(big.Int).SetString("1000000000000000000000005", 10)
+	largeEnd, _ := new(big.Int).SetString("1000000000000000000000009", 10)
+
+	tests := []struct {
+		period *big.Int
+		start  *big.Int
+		end    *big.Int
+	}{
+		{period: big.NewInt(0), start: big.NewInt(0), end: big.NewInt(4)},
+		{period: big.NewInt(1), start: big.NewInt(5), end: big.NewInt(9)},
+		{period: large, start: largeStart, end: largeEnd},
+	}
+	periodLength := big.NewInt(5)
+	for _, tt := range tests {
+		start, end := PeriodToBlockRange(tt.period, periodLength)
+		if start.Cmp(tt.start) != 0 || end.Cmp(tt.end) != 0 {
+			t.Errorf("PeriodToBlockRange(%v) incorrect. want=[%v, %v]. got=[%v, %v]", tt.period, tt.start, tt.end, start, end)
+		}
+		if p := BlockToPeriod(start, periodLength); p.Cmp(tt.period) != 0 {
+			t.Errorf("start of period %v maps back to period %v", tt.period, p)
+		}
+		if p := BlockToPeriod(end, periodLength); p.Cmp(tt.period) != 0 {
+			t.Errorf("end of period %v maps back to period %v", tt.period, p)
+		}
+	}
+
+	if start, end := PeriodToBlockRange(nil, periodLength); start != nil || end != nil {
+		t.Errorf("nil period should return nil bounds. got=[%v, %v]", start, end)
+	}
+	if start, end := PeriodToBlockRange(big.NewInt(1), big.NewInt(0)); start != nil || end != nil {
+		t.Errorf("zero period length should return nil bounds. got=[%v, %v]", start, end)
+	}
+	if start, end := PeriodToBlockRange(big.NewInt(1), nil); start != nil || end != nil {
+		t.Errorf("nil period length should return nil bounds. got=[%v, %v]", start, end)
+	}
+}